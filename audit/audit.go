@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Event describes a successful write made through the controller
+type Event struct {
+	Time         time.Time `json:"time"`
+	User         string    `json:"user"`
+	Service      bool      `json:"service,omitempty"`
+	CollectionID string    `json:"collection_id"`
+	DatasetID    string    `json:"dataset_id"`
+	Edition      string    `json:"edition,omitempty"`
+	Version      string    `json:"version,omitempty"`
+	Action       string    `json:"action"`
+	Changes      []Change  `json:"changes"`
+}
+
+// Sink is a destination that audit events are sent to as they are recorded
+type Sink interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// Store keeps audit events locally so that the history of a dataset can be read back
+type Store interface {
+	Add(ctx context.Context, e Event) error
+	History(ctx context.Context, datasetID string) ([]Event, error)
+}
+
+// Auditor records audit events in a local store and sends them on to an optional sink
+type Auditor struct {
+	store Store
+	sink  Sink
+}
+
+// New creates a new Auditor. The sink can be nil if events only need to be kept in the store
+func New(store Store, sink Sink) *Auditor {
+	return &Auditor{
+		store: store,
+		sink:  sink,
+	}
+}
+
+// Record stores and sends an audit event. Failures are logged rather than returned so that auditing never
+// fails a write that has already been made upstream
+func (a *Auditor) Record(ctx context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	logData := log.Data{"datasetID": e.DatasetID, "action": e.Action}
+
+	if err := a.store.Add(ctx, e); err != nil {
+		log.Error(ctx, "failed to store audit event", err, logData)
+	}
+
+	if a.sink != nil {
+		if err := a.sink.Send(ctx, e); err != nil {
+			log.Error(ctx, "failed to send audit event", err, logData)
+		}
+	}
+}
+
+// History returns the audit events recorded for a dataset, oldest first
+func (a *Auditor) History(ctx context.Context, datasetID string) ([]Event, error) {
+	return a.store.History(ctx, datasetID)
+}
+
+// Sink types that can be configured for an Auditor
+const (
+	SinkLog  = "log"
+	SinkFile = "file"
+	SinkHTTP = "http"
+)
+
+// NewFromConfig creates an Auditor sending events to the configured sink type. History is kept in a FileStore at
+// filePath whatever the sink, so that it survives restarts; the file sink is that store and sends nothing further
+func NewFromConfig(sinkType, filePath, httpURL string) (*Auditor, error) {
+	if filePath == "" {
+		return nil, errors.New("audit file path must be set to keep the audit history")
+	}
+	store := NewFileStore(filePath)
+
+	switch sinkType {
+	case SinkLog:
+		return New(store, LogSink{}), nil
+	case SinkFile:
+		return New(store, nil), nil
+	case SinkHTTP:
+		if httpURL == "" {
+			return nil, errors.New("audit HTTP URL must be set for the http sink")
+		}
+		return New(store, NewHTTPSink(httpURL)), nil
+	default:
+		return nil, fmt.Errorf("unknown audit sink type: %q", sinkType)
+	}
+}
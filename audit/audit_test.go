@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testSink struct {
+	events []Event
+	err    error
+}
+
+func (s *testSink) Send(ctx context.Context, e Event) error {
+	s.events = append(s.events, e)
+	return s.err
+}
+
+func TestUnitAuditor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test Auditor", t, func() {
+		store := NewMemoryStore()
+		sink := &testSink{}
+		auditor := New(store, sink)
+
+		Convey("records events in the store and the sink", func() {
+			auditor.Record(ctx, Event{DatasetID: "ds1", Action: "put-metadata"})
+			auditor.Record(ctx, Event{DatasetID: "ds2", Action: "put-metadata"})
+
+			history, err := auditor.History(ctx, "ds1")
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, 1)
+			So(history[0].Action, ShouldEqual, "put-metadata")
+			So(history[0].Time.IsZero(), ShouldBeFalse)
+			So(sink.events, ShouldHaveLength, 2)
+		})
+
+		Convey("keeps the event in the store when the sink fails", func() {
+			sink.err = errors.New("test sink error")
+			auditor.Record(ctx, Event{DatasetID: "ds1"})
+
+			history, err := auditor.History(ctx, "ds1")
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, 1)
+		})
+	})
+
+	Convey("test NewFromConfig", t, func() {
+		Convey("creates an auditor for each sink type", func() {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+			_, err := NewFromConfig(SinkLog, path, "")
+			So(err, ShouldBeNil)
+
+			_, err = NewFromConfig(SinkFile, path, "")
+			So(err, ShouldBeNil)
+
+			_, err = NewFromConfig(SinkHTTP, path, "http://localhost:9999/audit")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("keeps the history in the file whatever the sink", func() {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			a, err := NewFromConfig(SinkLog, path, "")
+			So(err, ShouldBeNil)
+			a.Record(context.Background(), Event{DatasetID: "ds1", Action: "put-metadata"})
+
+			restarted, err := NewFromConfig(SinkLog, path, "")
+			So(err, ShouldBeNil)
+			history, err := restarted.History(context.Background(), "ds1")
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, 1)
+		})
+
+		Convey("returns an error for a missing file path or url", func() {
+			for _, sinkType := range []string{SinkLog, SinkFile, SinkHTTP} {
+				_, err := NewFromConfig(sinkType, "", "http://localhost:9999/audit")
+				So(err, ShouldNotBeNil)
+			}
+
+			_, err := NewFromConfig(SinkHTTP, filepath.Join(t.TempDir(), "audit.jsonl"), "")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("returns an error for an unknown sink type", func() {
+			_, err := NewFromConfig("kafka", filepath.Join(t.TempDir(), "audit.jsonl"), "")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitFileStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test FileStore", t, func() {
+		store := NewFileStore(filepath.Join(t.TempDir(), "audit.jsonl"))
+
+		Convey("returns an empty history before anything is written", func() {
+			history, err := store.History(ctx, "ds1")
+			So(err, ShouldBeNil)
+			So(history, ShouldBeEmpty)
+		})
+
+		Convey("reads back the events written for a dataset", func() {
+			So(store.Add(ctx, Event{DatasetID: "ds1", Action: "first", Changes: []Change{{Field: "title", From: "a", To: "b"}}}), ShouldBeNil)
+			So(store.Send(ctx, Event{DatasetID: "ds2", Action: "other"}), ShouldBeNil)
+			So(store.Add(ctx, Event{DatasetID: "ds1", Action: "second"}), ShouldBeNil)
+
+			history, err := store.History(ctx, "ds1")
+			So(err, ShouldBeNil)
+			So(history, ShouldHaveLength, 2)
+			So(history[0].Action, ShouldEqual, "first")
+			So(history[0].Changes, ShouldResemble, []Change{{Field: "title", From: "a", To: "b"}})
+			So(history[1].Action, ShouldEqual, "second")
+		})
+	})
+}
+
+func TestUnitHTTPSink(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test HTTPSink", t, func() {
+		Convey("posts the event to the endpoint", func() {
+			var received *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			err := NewHTTPSink(server.URL).Send(ctx, Event{DatasetID: "ds1"})
+			So(err, ShouldBeNil)
+			So(received.Method, ShouldEqual, http.MethodPost)
+			So(received.Header.Get("Content-Type"), ShouldEqual, "application/json")
+		})
+
+		Convey("returns an error when the endpoint does not return a success status", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			}))
+			defer server.Close()
+
+			err := NewHTTPSink(server.URL).Send(ctx, Event{DatasetID: "ds1"})
+			So(err, ShouldResemble, ErrInvalidAuditResponse{http.StatusBadRequest})
+		})
+	})
+}
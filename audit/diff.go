@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change describes the old and new value of a single field
+type Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff compares the json representations of before and after, returning a change for every field set in either
+// that has a different value in the other. Fields that are cleared, and so left out of after, are reported with a
+// nil To. Nested objects are compared field by field and reported using dot separated paths. Fields named in
+// ignore are skipped at any depth
+func Diff(before, after interface{}, ignore ...string) ([]Change, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		ignored[field] = true
+	}
+
+	changes := []Change{}
+	diffMaps("", b, a, ignored, &changes)
+	return changes, nil
+}
+
+func diffMaps(prefix string, before, after map[string]interface{}, ignored map[string]bool, changes *[]Change) {
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		if !ignored[k] {
+			keys = append(keys, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok && !ignored[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+
+		afterMap, afterIsMap := after[k].(map[string]interface{})
+		beforeMap, beforeIsMap := before[k].(map[string]interface{})
+		if (afterIsMap && (beforeIsMap || before[k] == nil)) || (beforeIsMap && after[k] == nil) {
+			diffMaps(field, beforeMap, afterMap, ignored, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(before[k], after[k]) {
+			*changes = append(*changes, Change{Field: field, From: before[k], To: after[k]})
+		}
+	}
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testNested struct {
+	Label string `json:"label,omitempty"`
+	Code  string `json:"code,omitempty"`
+}
+
+type testDoc struct {
+	Title    string      `json:"title,omitempty"`
+	Keywords []string    `json:"keywords,omitempty"`
+	QMI      *testNested `json:"qmi,omitempty"`
+	Updated  string      `json:"last_updated,omitempty"`
+}
+
+func TestUnitDiff(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Diff", t, func() {
+		Convey("returns no changes for equal documents", func() {
+			doc := testDoc{Title: "title", Keywords: []string{"a"}}
+			changes, err := Diff(doc, doc)
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+
+		Convey("returns changed top level and nested fields", func() {
+			before := testDoc{Title: "old", Keywords: []string{"a"}, QMI: &testNested{Label: "qmi", Code: "1"}}
+			after := testDoc{Title: "new", Keywords: []string{"a", "b"}, QMI: &testNested{Label: "qmi", Code: "2"}}
+
+			changes, err := Diff(before, after)
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []Change{
+				{Field: "keywords", From: []interface{}{"a"}, To: []interface{}{"a", "b"}},
+				{Field: "qmi.code", From: "1", To: "2"},
+				{Field: "title", From: "old", To: "new"},
+			})
+		})
+
+		Convey("reports fields cleared in after as removed", func() {
+			changes, err := Diff(testDoc{Title: "old"}, testDoc{Keywords: []string{"a"}})
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []Change{
+				{Field: "keywords", From: nil, To: []interface{}{"a"}},
+				{Field: "title", From: "old", To: nil},
+			})
+		})
+
+		Convey("reports cleared scalars, slices and nested objects", func() {
+			changes, err := Diff(testDoc{Title: "old", Keywords: []string{"a"}, QMI: &testNested{Code: "1", Label: "qmi"}}, testDoc{Keywords: []string{}})
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []Change{
+				{Field: "keywords", From: []interface{}{"a"}, To: nil},
+				{Field: "qmi.code", From: "1", To: nil},
+				{Field: "qmi.label", From: "qmi", To: nil},
+				{Field: "title", From: "old", To: nil},
+			})
+		})
+
+		Convey("reports nested fields added to an empty object", func() {
+			changes, err := Diff(testDoc{}, testDoc{QMI: &testNested{Label: "qmi"}})
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []Change{{Field: "qmi.label", From: nil, To: "qmi"}})
+		})
+
+		Convey("skips ignored fields", func() {
+			changes, err := Diff(testDoc{Updated: "yesterday"}, testDoc{Updated: "today"}, "last_updated")
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+
+		Convey("compares against an empty document when before is nil", func() {
+			var before *testDoc
+			changes, err := Diff(before, testDoc{Title: "new"})
+
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []Change{{Field: "title", From: nil, To: "new"}})
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// LogSink writes audit events to the service log
+type LogSink struct{}
+
+// Send logs an audit event
+func (LogSink) Send(ctx context.Context, e Event) error {
+	log.Info(ctx, "audit event", log.Data{"audit": e})
+	return nil
+}
+
+// ErrInvalidAuditResponse is returned when the audit endpoint does not respond with a success status
+type ErrInvalidAuditResponse struct {
+	responseCode int
+}
+
+// Error should be called by the user to print out the stringified version of the error
+func (e ErrInvalidAuditResponse) Error() string {
+	return fmt.Sprintf("invalid response from audit endpoint - status %d", e.responseCode)
+}
+
+// Code returns the status code received from the audit endpoint
+func (e ErrInvalidAuditResponse) Code() int {
+	return e.responseCode
+}
+
+// HTTPSink posts audit events as json to an HTTP endpoint
+type HTTPSink struct {
+	cli dphttp.Clienter
+	url string
+}
+
+// NewHTTPSink creates an HTTPSink posting to the given url
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{
+		cli: dphttp.NewClient(),
+		url: url,
+	}
+}
+
+// Send posts an audit event to the endpoint
+func (s *HTTPSink) Send(ctx context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.cli.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error(ctx, "error closing http response body", err)
+		}
+	}()
+
+	if _, err = io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return ErrInvalidAuditResponse{resp.StatusCode}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// MemoryStore keeps audit events in memory. History is lost when the service restarts
+type MemoryStore struct {
+	mu     sync.RWMutex
+	events map[string][]Event
+}
+
+// NewMemoryStore creates a new, empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{events: map[string][]Event{}}
+}
+
+// Add stores an audit event
+func (s *MemoryStore) Add(ctx context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[e.DatasetID] = append(s.events[e.DatasetID], e)
+	return nil
+}
+
+// History returns the audit events stored for a dataset
+func (s *MemoryStore) History(ctx context.Context, datasetID string) ([]Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	history := make([]Event, len(s.events[datasetID]))
+	copy(history, s.events[datasetID])
+	return history, nil
+}
+
+// FileStore appends audit events to a file as json lines. It can be used both as the local store and as a sink
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a FileStore writing to the file at path, which is created if it does not exist
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Add appends an audit event to the file
+func (s *FileStore) Add(ctx context.Context, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Send appends an audit event to the file, allowing a FileStore to be used as a sink
+func (s *FileStore) Send(ctx context.Context, e Event) error {
+	return s.Add(ctx, e)
+}
+
+// History reads back the audit events in the file for a dataset
+func (s *FileStore) History(ctx context.Context, datasetID string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := []Event{}
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		if e.DatasetID == datasetID {
+			history = append(history, e)
+		}
+	}
+
+	return history, scanner.Err()
+}
@@ -0,0 +1,216 @@
+package authorisation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/identity"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Permissions checked by the controller
+const (
+	PermissionRead = "datasets:read"
+	PermissionEdit = "datasets:edit"
+)
+
+// groupEntityPrefix prefixes a group name to give the entity it is listed as in a permissions bundle
+const groupEntityPrefix = "groups/"
+
+// ErrNoAccessToken is returned when a request has no access token header or cookie
+var ErrNoAccessToken = errors.New("no access token set")
+
+// publicPaths are served to any caller without a token
+var publicPaths = []string{"/health", "/metrics", "/docs"}
+
+// publisherReadRoutes are reads that need PermissionEdit, as they show who edited a dataset
+var publisherReadRoutes = []string{"get-audit-history"}
+
+// Policy is a grant of a permission to an entity. Conditions are not supported.
+type Policy struct {
+	ID string `json:"id"`
+}
+
+// Bundle maps each permission to the entities, such as "groups/role-publisher", that hold it. It has the
+// same layout as the bundle served by the permissions API.
+type Bundle map[string]map[string][]Policy
+
+// DefaultBundle is used when no bundle file is configured. Admins and publishers can read and edit
+// datasets; viewers can only read them.
+var DefaultBundle = Bundle{
+	PermissionRead: {
+		"groups/role-admin":     {{ID: "1"}},
+		"groups/role-publisher": {{ID: "2"}},
+		"groups/role-viewer":    {{ID: "3"}},
+	},
+	PermissionEdit: {
+		"groups/role-admin":     {{ID: "4"}},
+		"groups/role-publisher": {{ID: "5"}},
+	},
+}
+
+// LoadBundle reads a permissions bundle from a json file
+func LoadBundle(path string) (Bundle, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle Bundle
+	if err = json.Unmarshal(b, &bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Allows reports whether any of groups holds permission
+func (b Bundle) Allows(permission string, groups []string) bool {
+	entities := b[permission]
+	for _, group := range groups {
+		if len(entities[groupEntityPrefix+group]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Authoriser checks that the caller's access token grants the permission a request needs
+type Authoriser struct {
+	enabled  bool
+	verifier *Verifier
+	bundle   Bundle
+	services map[string]Service
+}
+
+// New creates an Authoriser. Tokens are verified against verifier, and every user access token is
+// rejected if it is nil. If enabled is false every user request is allowed.
+// Requests made with the service token of one of services are checked against that service's scope
+// whether or not enabled is set.
+func New(enabled bool, verifier *Verifier, bundle Bundle, services []Service) *Authoriser {
+	a := &Authoriser{enabled: enabled, verifier: verifier, bundle: bundle, services: make(map[string]Service)}
+	for _, s := range services {
+		a.services[strings.ToLower(s.TokenSHA256)] = s
+	}
+	return a
+}
+
+// Middleware rejects requests without a valid access token with a 401, and requests from callers without
+// the permission needed by the request method with a 403. Reads need PermissionRead, other than the reads in
+// publisherReadRoutes, and all other methods need PermissionEdit. The identity in a verified token is passed on in
+// the request context. A request with a service token in the Authorization header, and no user access token, is
+// checked against the scope of the service instead.
+func (a *Authoriser) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if slices.Contains(publicPaths, req.URL.Path) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx := req.Context()
+
+		if len(a.services) > 0 && !hasUserToken(req) {
+			if token := serviceToken(req); token != "" {
+				a.authoriseService(w, req, next, token)
+				return
+			}
+		}
+
+		if !a.enabled {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		permission := requiredPermission(req)
+		logData := log.Data{"path": req.URL.Path, "method": req.Method, "permission": permission}
+
+		token, err := dphandlers.GetFlorenceToken(ctx, req)
+		if err == nil && token == "" {
+			err = ErrNoAccessToken
+		}
+		if err != nil {
+			log.Error(ctx, "error getting access token", err, logData)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		c, err := a.verifier.Claims(token)
+		if err != nil {
+			log.Error(ctx, "access token rejected", err, logData)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if !a.bundle.Allows(permission, c.Groups) {
+			logData["groups"] = c.Groups
+			log.Warn(ctx, "caller does not have permission", logData)
+			http.Error(w, "caller does not have permission "+permission, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, req.WithContext(identity.NewContext(ctx, identity.Identity{ID: c.Subject, Username: c.Username})))
+	})
+}
+
+func hasUserToken(req *http.Request) bool {
+	if req.Header.Get(dprequest.FlorenceHeaderKey) != "" {
+		return true
+	}
+	c, err := req.Cookie(dprequest.FlorenceCookieKey)
+	return err == nil && c.Value != ""
+}
+
+func requiredPermission(req *http.Request) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if slices.Contains(publisherReadRoutes, routeName(req)) {
+			return PermissionEdit
+		}
+		return PermissionRead
+	default:
+		return PermissionEdit
+	}
+}
+
+// NewFromConfig creates an Authoriser from the configured key set, bundle and service files. A key set file
+// must be given when enabled is set, and without a bundle file DefaultBundle is used. Service tokens are only
+// accepted if serviceAuthEnabled is set.
+func NewFromConfig(enabled bool, jwksFile, bundleFile string, serviceAuthEnabled bool, servicesFile string) (*Authoriser, error) {
+	var services []Service
+	if serviceAuthEnabled {
+		if servicesFile == "" {
+			return nil, errors.New("service tokens file must be set when service auth is enabled")
+		}
+		var err error
+		if services, err = LoadServices(servicesFile); err != nil {
+			return nil, fmt.Errorf("failed to load service tokens: %w", err)
+		}
+	}
+
+	if !enabled {
+		return New(false, nil, nil, services), nil
+	}
+
+	if jwksFile == "" {
+		return nil, errors.New("key set file must be set when authorisation is enabled")
+	}
+	verifier, err := LoadVerifier(jwksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key set: %w", err)
+	}
+
+	bundle := DefaultBundle
+	if bundleFile != "" {
+		if bundle, err = LoadBundle(bundleFile); err != nil {
+			return nil, fmt.Errorf("failed to load permissions bundle: %w", err)
+		}
+	}
+
+	return New(true, verifier, bundle, services), nil
+}
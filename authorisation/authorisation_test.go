@@ -0,0 +1,248 @@
+package authorisation
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/identity"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitBundle(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Allows", t, func() {
+		Convey("publishers can read and edit datasets", func() {
+			So(DefaultBundle.Allows(PermissionRead, []string{"role-publisher"}), ShouldBeTrue)
+			So(DefaultBundle.Allows(PermissionEdit, []string{"role-publisher"}), ShouldBeTrue)
+		})
+
+		Convey("viewers can only read datasets", func() {
+			So(DefaultBundle.Allows(PermissionRead, []string{"role-viewer"}), ShouldBeTrue)
+			So(DefaultBundle.Allows(PermissionEdit, []string{"role-viewer"}), ShouldBeFalse)
+		})
+
+		Convey("callers without a group in the bundle have no permissions", func() {
+			So(DefaultBundle.Allows(PermissionRead, []string{"other"}), ShouldBeFalse)
+			So(DefaultBundle.Allows(PermissionRead, nil), ShouldBeFalse)
+		})
+	})
+
+	Convey("test LoadBundle", t, func() {
+		Convey("reads a bundle from a file", func() {
+			path := filepath.Join(t.TempDir(), "bundle.json")
+			So(os.WriteFile(path, []byte(`{"datasets:read":{"groups/1234":[{"id":"policy-1"}]}}`), 0o600), ShouldBeNil)
+
+			bundle, err := LoadBundle(path)
+
+			So(err, ShouldBeNil)
+			So(bundle.Allows(PermissionRead, []string{"1234"}), ShouldBeTrue)
+			So(bundle.Allows(PermissionEdit, []string{"1234"}), ShouldBeFalse)
+		})
+
+		Convey("returns an error if the file is not a bundle", func() {
+			path := filepath.Join(t.TempDir(), "bundle.json")
+			So(os.WriteFile(path, []byte(`[]`), 0o600), ShouldBeNil)
+
+			_, err := LoadBundle(path)
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	expiry := time.Now().Add(time.Hour).Unix()
+	publisherToken := signedToken("key-1", testKey, Claims{Subject: "1", Groups: []string{"role-publisher"}, ExpiresAt: expiry})
+	viewerToken := signedToken("key-1", testKey, Claims{Subject: "2", Groups: []string{"role-viewer"}, ExpiresAt: expiry})
+
+	verifier, err := NewVerifier(testKeySet("key-1", &testKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(a *Authoriser, method, target, token string) (int, bool) {
+		var called bool
+		handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		req := httptest.NewRequest(method, target, http.NoBody)
+		if token != "" {
+			req.Header.Set(dprequest.FlorenceHeaderKey, token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code, called
+	}
+
+	Convey("Given an enabled authoriser", t, func() {
+		a := New(true, verifier, DefaultBundle, nil)
+
+		Convey("a publisher can read and edit datasets", func() {
+			code, called := serve(a, http.MethodGet, "/datasets", publisherToken)
+			So(code, ShouldEqual, http.StatusOK)
+			So(called, ShouldBeTrue)
+
+			code, called = serve(a, http.MethodPut, "/datasets/cpih01/editions/time-series/versions/1", publisherToken)
+			So(code, ShouldEqual, http.StatusOK)
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("the verified identity is passed on in the request context", func() {
+			var caller identity.Identity
+			handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				caller, _ = identity.FromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/datasets", http.NoBody)
+			req.Header.Set(dprequest.FlorenceHeaderKey, signedToken("key-1", testKey, Claims{Subject: "1", Username: "publisher", Groups: []string{"role-publisher"}, ExpiresAt: expiry}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			So(caller, ShouldResemble, identity.Identity{ID: "1", Username: "publisher"})
+		})
+
+		Convey("a viewer can read datasets", func() {
+			code, called := serve(a, http.MethodGet, "/datasets/cpih01/editions", viewerToken)
+
+			So(code, ShouldEqual, http.StatusOK)
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("a viewer cannot edit datasets", func() {
+			for _, method := range []string{http.MethodPut, http.MethodPost, http.MethodDelete} {
+				code, called := serve(a, method, "/datasets/cpih01/editions/time-series/versions/1/collection", viewerToken)
+
+				So(code, ShouldEqual, http.StatusForbidden)
+				So(called, ShouldBeFalse)
+			}
+		})
+
+		Convey("only a publisher can read the audit history", func() {
+			router := mux.NewRouter()
+			router.Use(a.Middleware)
+			router.Name("get-audit-history").Path("/datasets/{datasetID}/audit").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+			for token, want := range map[string]int{publisherToken: http.StatusOK, viewerToken: http.StatusForbidden} {
+				req := httptest.NewRequest(http.MethodGet, "/datasets/cpih01/audit", http.NoBody)
+				req.Header.Set(dprequest.FlorenceHeaderKey, token)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				So(w.Code, ShouldEqual, want)
+			}
+		})
+
+		Convey("a request without a token is rejected", func() {
+			code, called := serve(a, http.MethodGet, "/datasets", "")
+
+			So(code, ShouldEqual, http.StatusUnauthorized)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("a request with an unverified token is rejected", func() {
+			code, called := serve(a, http.MethodGet, "/datasets", testAccessToken("role-publisher"))
+
+			So(code, ShouldEqual, http.StatusUnauthorized)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("a token in the access token cookie is accepted", func() {
+			var called bool
+			handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+			req := httptest.NewRequest(http.MethodGet, "/datasets", http.NoBody)
+			req.AddCookie(&http.Cookie{Name: dprequest.FlorenceCookieKey, Value: viewerToken})
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(called, ShouldBeTrue)
+		})
+
+		Convey("public paths are served without a token", func() {
+			for _, path := range []string{"/health", "/metrics", "/docs"} {
+				code, called := serve(a, http.MethodGet, path, "")
+
+				So(code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+			}
+		})
+	})
+
+	Convey("Given an enabled authoriser without a verifier", t, func() {
+		a := New(true, nil, DefaultBundle, nil)
+
+		Convey("every user access token is rejected, signed or not", func() {
+			for _, token := range []string{publisherToken, testAccessToken("role-publisher")} {
+				code, called := serve(a, http.MethodGet, "/datasets", token)
+
+				So(code, ShouldEqual, http.StatusUnauthorized)
+				So(called, ShouldBeFalse)
+			}
+		})
+	})
+
+	Convey("Given a disabled authoriser", t, func() {
+		a, err := NewFromConfig(false, "", "", false, "")
+		So(err, ShouldBeNil)
+
+		Convey("requests without a token are allowed", func() {
+			code, called := serve(a, http.MethodDelete, "/datasets/cpih01/editions/time-series/versions/1/collection", "")
+
+			So(code, ShouldEqual, http.StatusOK)
+			So(called, ShouldBeTrue)
+		})
+	})
+}
+
+func TestUnitNewFromConfig(t *testing.T) {
+	t.Parallel()
+
+	Convey("test NewFromConfig", t, func() {
+		jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+		So(os.WriteFile(jwksFile, testKeySet("key-1", &testKey.PublicKey), 0o600), ShouldBeNil)
+
+		Convey("uses the default bundle if no bundle file is set", func() {
+			a, err := NewFromConfig(true, jwksFile, "", false, "")
+
+			So(err, ShouldBeNil)
+			So(a.verifier, ShouldNotBeNil)
+			So(a.bundle, ShouldResemble, DefaultBundle)
+		})
+
+		Convey("returns an error if authorisation is enabled without a key set file", func() {
+			_, err := NewFromConfig(true, "", "", false, "")
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("returns an error if the key set file cannot be read", func() {
+			_, err := NewFromConfig(true, filepath.Join(t.TempDir(), "missing.json"), "", false, "")
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("returns an error if the bundle file cannot be read", func() {
+			_, err := NewFromConfig(true, jwksFile, filepath.Join(t.TempDir(), "missing.json"), false, "")
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// testAccessToken returns a token for a caller in group that has not been signed
+func testAccessToken(group string) string {
+	h := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"key-1"}`))
+	c := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"3","cognito:groups":["` + group + `"]}`))
+	return h + "." + c + ".signature"
+}
@@ -0,0 +1,118 @@
+package authorisation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+type contextKey string
+
+const serviceCallerKey contextKey = "serviceCaller"
+
+// ErrUnknownServiceToken is returned when a service token is not in the configured service list
+var ErrUnknownServiceToken = errors.New("service token not recognised")
+
+// Service is an automated caller allowed to use the controller with a service token instead of a user
+// access token. Only a hash of the token is configured, so the token itself is not stored.
+type Service struct {
+	Name        string `json:"name"`
+	TokenSHA256 string `json:"token_sha256"`
+	// Read allows the service to call every GET route
+	Read bool `json:"read"`
+	// Write lists the names of the write routes the service may call
+	Write []string `json:"write"`
+}
+
+// ServiceCaller identifies the service that made a request, and holds the token it authenticated with so
+// it can be passed on to upstream services
+type ServiceCaller struct {
+	Name  string
+	Token string
+}
+
+// LoadServices reads the list of services allowed to use service tokens from a json file
+func LoadServices(path string) ([]Service, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []Service
+	if err = json.Unmarshal(b, &services); err != nil {
+		return nil, err
+	}
+
+	for _, s := range services {
+		if s.Name == "" || s.TokenSHA256 == "" {
+			return nil, errors.New("each service must have a name and token_sha256")
+		}
+	}
+	return services, nil
+}
+
+// WithServiceCaller returns a copy of ctx holding the service that made the request
+func WithServiceCaller(ctx context.Context, c ServiceCaller) context.Context {
+	return context.WithValue(ctx, serviceCallerKey, c)
+}
+
+// ServiceCallerFromContext returns the service that made the request, if it was made with a service token
+func ServiceCallerFromContext(ctx context.Context) (ServiceCaller, bool) {
+	c, ok := ctx.Value(serviceCallerKey).(ServiceCaller)
+	return c, ok
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authoriseService checks the service token is known and that its scope covers the route, then passes the
+// request on with the service caller in its context
+func (a *Authoriser) authoriseService(w http.ResponseWriter, req *http.Request, next http.Handler, token string) {
+	ctx := req.Context()
+	route := routeName(req)
+	logData := log.Data{"path": req.URL.Path, "method": req.Method, "route": route}
+
+	s, ok := a.services[hashToken(token)]
+	if !ok {
+		log.Error(ctx, "service token rejected", ErrUnknownServiceToken, logData)
+		http.Error(w, ErrUnknownServiceToken.Error(), http.StatusUnauthorized)
+		return
+	}
+	logData["service"] = s.Name
+
+	allowed := s.Read
+	if requiredPermission(req) == PermissionEdit {
+		allowed = route != "" && slices.Contains(s.Write, route)
+	}
+	if !allowed {
+		log.Warn(ctx, "service does not have permission for route", logData)
+		http.Error(w, fmt.Sprintf("service %s does not have permission to call %s %s", s.Name, req.Method, req.URL.Path), http.StatusForbidden)
+		return
+	}
+
+	log.Info(ctx, "request made by service", logData)
+	next.ServeHTTP(w, req.WithContext(WithServiceCaller(ctx, ServiceCaller{Name: s.Name, Token: token})))
+}
+
+func routeName(req *http.Request) string {
+	if route := mux.CurrentRoute(req); route != nil {
+		return route.GetName()
+	}
+	return ""
+}
+
+func serviceToken(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
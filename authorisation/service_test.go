@@ -0,0 +1,155 @@
+package authorisation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLoadServices(t *testing.T) {
+	t.Parallel()
+
+	Convey("test LoadServices", t, func() {
+		path := filepath.Join(t.TempDir(), "services.json")
+
+		Convey("reads the services from a file", func() {
+			So(os.WriteFile(path, []byte(`[{"name":"bulk-metadata-fix","token_sha256":"abc","read":true,"write":["put-metadata"]}]`), 0o600), ShouldBeNil)
+
+			services, err := LoadServices(path)
+
+			So(err, ShouldBeNil)
+			So(services, ShouldResemble, []Service{{Name: "bulk-metadata-fix", TokenSHA256: "abc", Read: true, Write: []string{"put-metadata"}}})
+		})
+
+		Convey("returns an error if a service has no token hash", func() {
+			So(os.WriteFile(path, []byte(`[{"name":"bulk-metadata-fix"}]`), 0o600), ShouldBeNil)
+
+			_, err := LoadServices(path)
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("returns an error if the file cannot be read", func() {
+			_, err := LoadServices(filepath.Join(t.TempDir(), "missing.json"))
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("test NewFromConfig with service auth", t, func() {
+		Convey("returns an error if no service tokens file is set", func() {
+			_, err := NewFromConfig(false, "", "", true, "")
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("loads the services when user authorisation is disabled", func() {
+			path := filepath.Join(t.TempDir(), "services.json")
+			So(os.WriteFile(path, []byte(`[{"name":"reader","token_sha256":"`+hashToken("reader-token")+`","read":true}]`), 0o600), ShouldBeNil)
+
+			a, err := NewFromConfig(false, "", "", true, path)
+
+			So(err, ShouldBeNil)
+			So(a.services, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestUnitServiceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	services := []Service{
+		{Name: "reader", TokenSHA256: hashToken("reader-token"), Read: true},
+		{Name: "metadata-fixer", TokenSHA256: hashToken("fixer-token"), Read: true, Write: []string{"put-metadata"}},
+	}
+
+	serve := func(a *Authoriser, method, target string, headers map[string]string) (int, *ServiceCaller) {
+		var caller *ServiceCaller
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c, ok := ServiceCallerFromContext(r.Context()); ok {
+				caller = &c
+			}
+		})
+
+		router := mux.NewRouter()
+		router.Use(a.Middleware)
+		router.Name("get-version").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").Handler(handler).Methods(http.MethodGet)
+		router.Name("put-metadata").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").Handler(handler).Methods(http.MethodPut)
+		router.Name("post-version-state").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/state").Handler(handler).Methods(http.MethodPost)
+
+		req := httptest.NewRequest(method, target, http.NoBody)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code, caller
+	}
+
+	const (
+		versionURL  = "/datasets/cpih01/editions/time-series/versions/1"
+		metadataURL = versionURL + "/metadata"
+		stateURL    = versionURL + "/state"
+	)
+
+	Convey("Given an authoriser with service tokens and user authorisation disabled", t, func() {
+		a := New(false, nil, nil, services)
+
+		Convey("a service with read scope can call read routes", func() {
+			code, caller := serve(a, http.MethodGet, versionURL, map[string]string{"Authorization": "Bearer reader-token"})
+
+			So(code, ShouldEqual, http.StatusOK)
+			So(caller, ShouldResemble, &ServiceCaller{Name: "reader", Token: "reader-token"})
+		})
+
+		Convey("a service can call the write routes named in its scope", func() {
+			code, caller := serve(a, http.MethodPut, metadataURL, map[string]string{"Authorization": "Bearer fixer-token"})
+
+			So(code, ShouldEqual, http.StatusOK)
+			So(caller.Name, ShouldEqual, "metadata-fixer")
+		})
+
+		Convey("a service cannot call write routes missing from its scope", func() {
+			code, caller := serve(a, http.MethodPost, stateURL, map[string]string{"Authorization": "Bearer fixer-token"})
+			So(code, ShouldEqual, http.StatusForbidden)
+			So(caller, ShouldBeNil)
+
+			code, _ = serve(a, http.MethodPut, metadataURL, map[string]string{"Authorization": "Bearer reader-token"})
+			So(code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("an unknown service token is rejected", func() {
+			code, caller := serve(a, http.MethodGet, versionURL, map[string]string{"Authorization": "Bearer unknown-token"})
+
+			So(code, ShouldEqual, http.StatusUnauthorized)
+			So(caller, ShouldBeNil)
+		})
+
+		Convey("a request with a user access token is not treated as a service request", func() {
+			code, caller := serve(a, http.MethodGet, versionURL, map[string]string{
+				"Authorization":             "Bearer unknown-token",
+				dprequest.FlorenceHeaderKey: testAccessToken("role-viewer"),
+			})
+
+			So(code, ShouldEqual, http.StatusOK)
+			So(caller, ShouldBeNil)
+		})
+	})
+
+	Convey("Given an authoriser without service tokens", t, func() {
+		a := New(true, nil, DefaultBundle, nil)
+
+		Convey("a service token is not accepted in place of a user access token", func() {
+			code, _ := serve(a, http.MethodGet, versionURL, map[string]string{"Authorization": "Bearer reader-token"})
+
+			So(code, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+}
@@ -0,0 +1,154 @@
+package authorisation
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// Errors returned when an access token is rejected
+var (
+	ErrInvalidToken     = errors.New("access token is not a valid JWT")
+	ErrUnsupportedAlg   = errors.New("access token is not signed with RS256")
+	ErrUnknownKey       = errors.New("access token is signed with an unknown key")
+	ErrInvalidSignature = errors.New("access token signature is invalid")
+	ErrTokenExpired     = errors.New("access token has expired")
+	ErrNoKeySet         = errors.New("no key set to verify access tokens against")
+)
+
+// Claims are the claims of an access token used to authorise a request
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Username  string   `json:"cognito:username"`
+	Groups    []string `json:"cognito:groups"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Verifier checks access token signatures against a set of RSA public keys
+type Verifier struct {
+	keys map[string]*rsa.PublicKey
+	now  func() time.Time
+}
+
+// LoadVerifier reads a JSON Web Key Set from a file and returns a Verifier for its RSA keys
+func LoadVerifier(path string) (*Verifier, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(b)
+}
+
+// NewVerifier returns a Verifier for the RSA keys in a JSON Web Key Set
+func NewVerifier(keySet []byte) (*Verifier, error) {
+	var set jwks
+	if err := json.Unmarshal(keySet, &set); err != nil {
+		return nil, err
+	}
+
+	v := &Verifier{keys: make(map[string]*rsa.PublicKey), now: time.Now}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := rsaKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		v.keys[k.Kid] = key
+	}
+
+	if len(v.keys) == 0 {
+		return nil, errors.New("key set has no RSA keys")
+	}
+	return v, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+// Claims returns the claims of token once its signature and expiry have been checked. A nil Verifier
+// rejects every token with ErrNoKeySet.
+func (v *Verifier) Claims(token string) (Claims, error) {
+	if v == nil {
+		return Claims{}, ErrNoKeySet
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, "Bearer "), ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var c Claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if h.Alg != "RS256" {
+		return Claims{}, ErrUnsupportedAlg
+	}
+	key, ok := v.keys[h.Kid]
+	if !ok {
+		return Claims{}, ErrUnknownKey
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrInvalidSignature
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	if c.ExpiresAt == 0 || v.now().Unix() >= c.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+
+	return c, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
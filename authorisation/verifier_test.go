@@ -0,0 +1,152 @@
+package authorisation
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+func testKeySet(kid string, key *rsa.PublicKey) []byte {
+	b, _ := json.Marshal(jwks{Keys: []jwk{{
+		Kid: kid,
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	return b
+}
+
+func signedToken(kid string, key *rsa.PrivateKey, claims Claims) string {
+	h, _ := json.Marshal(header{Alg: "RS256", Kid: kid})
+	c, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestUnitVerifier(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	claims := Claims{Subject: "1234", Groups: []string{"role-publisher"}, ExpiresAt: now.Add(time.Hour).Unix()}
+
+	Convey("Given a verifier for a key set", t, func() {
+		v, err := NewVerifier(testKeySet("key-1", &testKey.PublicKey))
+		So(err, ShouldBeNil)
+		v.now = func() time.Time { return now }
+
+		Convey("a token signed by a key in the set is accepted", func() {
+			c, err := v.Claims(signedToken("key-1", testKey, claims))
+
+			So(err, ShouldBeNil)
+			So(c, ShouldResemble, claims)
+		})
+
+		Convey("a bearer prefix is ignored", func() {
+			_, err := v.Claims("Bearer " + signedToken("key-1", testKey, claims))
+
+			So(err, ShouldBeNil)
+		})
+
+		Convey("a token signed by an unknown key ID is rejected", func() {
+			_, err := v.Claims(signedToken("key-2", testKey, claims))
+
+			So(err, ShouldEqual, ErrUnknownKey)
+		})
+
+		Convey("a token signed by a different key is rejected", func() {
+			other, _ := rsa.GenerateKey(rand.Reader, 2048)
+			_, err := v.Claims(signedToken("key-1", other, claims))
+
+			So(err, ShouldEqual, ErrInvalidSignature)
+		})
+
+		Convey("a token whose claims have been changed is rejected", func() {
+			token := signedToken("key-1", testKey, claims)
+			forged := signedToken("key-1", testKey, Claims{Subject: "1234", Groups: []string{"role-admin"}, ExpiresAt: claims.ExpiresAt})
+			parts := strings.Split(token, ".")
+			forgedParts := strings.Split(forged, ".")
+
+			_, err := v.Claims(parts[0] + "." + forgedParts[1] + "." + parts[2])
+
+			So(err, ShouldEqual, ErrInvalidSignature)
+		})
+
+		Convey("an expired token is rejected", func() {
+			expired := claims
+			expired.ExpiresAt = now.Add(-time.Minute).Unix()
+			_, err := v.Claims(signedToken("key-1", testKey, expired))
+
+			So(err, ShouldEqual, ErrTokenExpired)
+		})
+
+		Convey("a token without an expiry is rejected", func() {
+			noExpiry := claims
+			noExpiry.ExpiresAt = 0
+			_, err := v.Claims(signedToken("key-1", testKey, noExpiry))
+
+			So(err, ShouldEqual, ErrTokenExpired)
+		})
+
+		Convey("a token not signed with RS256 is rejected", func() {
+			h := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"key-1"}`))
+			parts := strings.Split(signedToken("key-1", testKey, claims), ".")
+
+			_, err := v.Claims(h + "." + parts[1] + ".")
+
+			So(err, ShouldEqual, ErrUnsupportedAlg)
+		})
+
+		Convey("a token that is not a JWT is rejected", func() {
+			_, err := v.Claims("not-a-jwt")
+
+			So(err, ShouldEqual, ErrInvalidToken)
+		})
+	})
+
+	Convey("A nil verifier rejects every token", t, func() {
+		var v *Verifier
+
+		_, err := v.Claims(signedToken("key-1", testKey, claims))
+
+		So(err, ShouldEqual, ErrNoKeySet)
+	})
+
+	Convey("LoadVerifier", t, func() {
+		Convey("reads a key set from a file", func() {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			So(os.WriteFile(path, testKeySet("key-1", &testKey.PublicKey), 0o600), ShouldBeNil)
+
+			v, err := LoadVerifier(path)
+
+			So(err, ShouldBeNil)
+			So(v.keys, ShouldContainKey, "key-1")
+		})
+
+		Convey("returns an error if the file does not exist", func() {
+			_, err := LoadVerifier(filepath.Join(t.TempDir(), "missing.json"))
+
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("returns an error if the key set has no RSA keys", func() {
+			_, err := NewVerifier([]byte(`{"keys":[{"kid":"ec","kty":"EC"}]}`))
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
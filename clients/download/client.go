@@ -0,0 +1,81 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Client reads the files of a version from the download service
+type Client struct {
+	cli dphttp.Clienter
+	url string
+}
+
+// ErrInvalidDownloadResponse is returned when the download service does not respond with a status 200
+type ErrInvalidDownloadResponse struct {
+	responseCode int
+	uri          string
+}
+
+// Error should be called by the user to print out the stringified version of the error
+func (e ErrInvalidDownloadResponse) Error() string {
+	return fmt.Sprintf("invalid response from download service - status %d, uri %s", e.responseCode, e.uri)
+}
+
+// Code returns the status code received from the download service if an error is returned
+func (e ErrInvalidDownloadResponse) Code() int {
+	return e.responseCode
+}
+
+// New creates a new instance of Client with a given download service url
+func New(downloadServiceURL string, cli dphttp.Clienter) *Client {
+	return &Client{
+		cli: cli,
+		url: downloadServiceURL,
+	}
+}
+
+// Open returns the body of the file at href. Only the path of href is requested from the download service, so the
+// caller's token is never sent to another host, and the caller has to close the body
+func (c *Client) Open(ctx context.Context, userAccessToken, collectionID, href string) (io.ReadCloser, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.url+u.EscapedPath(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	dprequest.AddFlorenceHeader(req, userAccessToken)
+	if collectionID != "" {
+		req.Header.Set(dprequest.CollectionIDHeaderKey, collectionID)
+	}
+
+	resp, err := c.cli.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		closeResponseBody(ctx, resp)
+		return nil, ErrInvalidDownloadResponse{responseCode: resp.StatusCode, uri: req.URL.Path}
+	}
+
+	return resp.Body, nil
+}
+
+// closeResponseBody closes the response body and logs an error containing the context if unsuccessful
+func closeResponseBody(ctx context.Context, resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Error(ctx, "error closing http response body", err)
+	}
+}
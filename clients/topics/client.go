@@ -40,9 +40,14 @@ func (e ErrInvalidBabbageResponse) Code() int {
 func New(babbageURL string) *Client {
 	hcClient := healthcheck.NewClient(service, babbageURL)
 
+	return NewWithHealthClient(hcClient)
+}
+
+// NewWithHealthClient creates a new instance of Client, reusing the URL and Clienter from the provided healthcheck client
+func NewWithHealthClient(hcCli *healthcheck.Client) *Client {
 	return &Client{
-		cli: hcClient.Client,
-		url: babbageURL,
+		cli: hcCli.Client,
+		url: hcCli.URL,
 	}
 }
 
@@ -0,0 +1,91 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Client reads users from the identity API
+type Client struct {
+	cli dphttp.Clienter
+	url string
+}
+
+// User is a user held by the identity API
+type User struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Forename string `json:"forename"`
+	Lastname string `json:"lastname"`
+}
+
+// ErrInvalidIdentityResponse is returned when the identity API does not respond with a status 200
+type ErrInvalidIdentityResponse struct {
+	responseCode int
+	uri          string
+}
+
+// Error should be called by the user to print out the stringified version of the error
+func (e ErrInvalidIdentityResponse) Error() string {
+	return fmt.Sprintf("invalid response from identity API - status %d, uri %s", e.responseCode, e.uri)
+}
+
+// Code returns the status code received from the identity API if an error is returned
+func (e ErrInvalidIdentityResponse) Code() int {
+	return e.responseCode
+}
+
+// NewWithHealthClient creates a new instance of Client, reusing the URL and Clienter from the provided health
+// check client
+func NewWithHealthClient(hcCli *healthcheck.Client) *Client {
+	return &Client{
+		cli: hcCli.Client,
+		url: hcCli.URL,
+	}
+}
+
+// GetUser returns the user with the given ID, which is the sub claim of their access token
+func (c *Client) GetUser(ctx context.Context, userAccessToken, userID string) (User, error) {
+	var u User
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/users/%s", c.url, url.PathEscape(userID)), http.NoBody)
+	if err != nil {
+		return u, err
+	}
+
+	dprequest.AddFlorenceHeader(req, userAccessToken)
+
+	resp, err := c.cli.Do(ctx, req)
+	if err != nil {
+		return u, err
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return u, ErrInvalidIdentityResponse{responseCode: resp.StatusCode, uri: req.URL.Path}
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return u, err
+	}
+
+	err = json.Unmarshal(b, &u)
+	return u, err
+}
+
+// closeResponseBody closes the response body and logs an error containing the context if unsuccessful
+func closeResponseBody(ctx context.Context, resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Error(ctx, "error closing http response body", err)
+	}
+}
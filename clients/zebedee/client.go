@@ -0,0 +1,76 @@
+package zebedee
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	dprequest "github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Client extends the zebedee client with the collection operations it does not provide
+type Client struct {
+	*zebedeeclient.Client
+	cli dphttp.Clienter
+	url string
+}
+
+// NewWithHealthClient creates a new instance of Client, reusing the URL and Clienter from the provided health
+// check client
+func NewWithHealthClient(hcCli *healthcheck.Client) *Client {
+	return &Client{
+		Client: zebedeeclient.NewWithHealthClient(hcCli),
+		cli:    hcCli.Client,
+		url:    hcCli.URL,
+	}
+}
+
+// DeleteDatasetFromCollection removes a dataset from a collection
+func (c *Client) DeleteDatasetFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID string) error {
+	uri := fmt.Sprintf("%s/collections/%s/datasets/%s", c.url, collectionID, datasetID)
+	return c.delete(ctx, userAccessToken, uri)
+}
+
+// DeleteDatasetVersionFromCollection removes a dataset version from a collection
+func (c *Client) DeleteDatasetVersionFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+	uri := fmt.Sprintf("%s/collections/%s/datasets/%s/editions/%s/versions/%s", c.url, collectionID, datasetID, edition, version)
+	return c.delete(ctx, userAccessToken, uri)
+}
+
+// delete sends a DELETE request to zebedee, returning an error if the response is not successful
+func (c *Client) delete(ctx context.Context, userAccessToken, uri string) error {
+	req, err := http.NewRequest(http.MethodDelete, uri, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	dprequest.AddFlorenceHeader(req, userAccessToken)
+
+	resp, err := c.cli.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer closeResponseBody(ctx, resp)
+
+	if _, err = io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return zebedeeclient.ErrInvalidZebedeeResponse{ActualCode: resp.StatusCode, URI: req.URL.Path}
+	}
+
+	return nil
+}
+
+// closeResponseBody closes the response body and logs an error containing the context if unsuccessful
+func closeResponseBody(ctx context.Context, resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Error(ctx, "error closing http response body", err)
+	}
+}
@@ -16,8 +16,37 @@ type Config struct {
 	HealthCheckInterval       time.Duration `envconfig:"HEALTHCHECK_INTERVAL"`
 	HealthCheckCritialTimeout time.Duration `envconfig:"HEALTHCHECK_CRITICAL_TIMEOUT"`
 	BabbageURL                string        `envconfig:"BABBAGE_URL"`
+	DownloadServiceURL        string        `envconfig:"DOWNLOAD_SERVICE_URL"`
 	DatasetsBatchSize         int           `envconfig:"DATASET_BATCH_SIZE"`
 	DatasetsBatchWorkers      int           `envconfig:"DATASET_BATCH_WORKERS"`
+	AuditSink                 string        `envconfig:"AUDIT_SINK"`
+	AuditFilePath             string        `envconfig:"AUDIT_FILE_PATH"`
+	AuditHTTPURL              string        `envconfig:"AUDIT_HTTP_URL"`
+	MaxRequestBodySize        int64         `envconfig:"MAX_REQUEST_BODY_SIZE"`
+	MaxUploadSize             int64         `envconfig:"MAX_UPLOAD_SIZE"`
+	FileBackend               string        `envconfig:"FILE_BACKEND"`
+	FileLocalDir              string        `envconfig:"FILE_LOCAL_DIR"`
+	AllowLocalFileBackend     bool          `envconfig:"ALLOW_LOCAL_FILE_BACKEND"`
+	ResumableUploadDir        string        `envconfig:"RESUMABLE_UPLOAD_DIR"`
+	MaxResumableUploadSize    int64         `envconfig:"MAX_RESUMABLE_UPLOAD_SIZE"`
+	ResumableUploadTTL        time.Duration `envconfig:"RESUMABLE_UPLOAD_TTL"`
+	MaxCSVValidationSize      int64         `envconfig:"MAX_CSV_VALIDATION_SIZE"`
+	ReleaseSource             string        `envconfig:"RELEASE_SOURCE"`
+	ReleaseLocalFile          string        `envconfig:"RELEASE_LOCAL_FILE"`
+	ReleaseLinksFile          string        `envconfig:"RELEASE_LINKS_FILE"`
+	ReleaseLinkStore          string        `envconfig:"RELEASE_LINK_STORE"`
+	MongoDBURI                string        `envconfig:"MONGODB_URI" json:"-"`
+	MongoDBDatabase           string        `envconfig:"MONGODB_DATABASE"`
+	MongoDBReleaseLinks       string        `envconfig:"MONGODB_RELEASE_LINKS_COLLECTION"`
+	AuthorisationEnabled      bool          `envconfig:"AUTHORISATION_ENABLED"`
+	JWKSFile                  string        `envconfig:"JWKS_FILE"`
+	PermissionsBundleFile     string        `envconfig:"PERMISSIONS_BUNDLE_FILE"`
+	ServiceAuthEnabled        bool          `envconfig:"SERVICE_AUTH_ENABLED"`
+	ServiceTokensFile         string        `envconfig:"SERVICE_TOKENS_FILE"`
+	OtelEnabled               bool          `envconfig:"OTEL_ENABLED"`
+	OTServiceName             string        `envconfig:"OTEL_SERVICE_NAME"`
+	OTExporterOTLPEndpoint    string        `envconfig:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTBatchTimeout            time.Duration `envconfig:"OTEL_BATCH_TIMEOUT"`
 }
 
 // Get retrieves the config from the environment for florence
@@ -33,8 +62,37 @@ func Get() (*Config, error) {
 		HealthCheckInterval:       30 * time.Second,
 		HealthCheckCritialTimeout: 90 * time.Second,
 		BabbageURL:                "http://localhost:8080",
+		DownloadServiceURL:        "http://localhost:23600",
 		DatasetsBatchSize:         100,
 		DatasetsBatchWorkers:      10,
+		AuditSink:                 "log",
+		AuditFilePath:             "audit.jsonl",
+		AuditHTTPURL:              "",
+		MaxRequestBodySize:        1024 * 1024,
+		MaxUploadSize:             50 * 1024 * 1024,
+		FileBackend:               "local",
+		FileLocalDir:              "uploads",
+		AllowLocalFileBackend:     false,
+		ResumableUploadDir:        "resumable-uploads",
+		MaxResumableUploadSize:    5 * 1024 * 1024 * 1024,
+		ResumableUploadTTL:        24 * time.Hour,
+		MaxCSVValidationSize:      1024 * 1024 * 1024,
+		ReleaseSource:             "local",
+		ReleaseLocalFile:          "",
+		ReleaseLinksFile:          "release-links.json",
+		ReleaseLinkStore:          "local",
+		MongoDBURI:                "mongodb://localhost:27017",
+		MongoDBDatabase:           "publishing-dataset-controller",
+		MongoDBReleaseLinks:       "release_links",
+		AuthorisationEnabled:      false,
+		JWKSFile:                  "",
+		PermissionsBundleFile:     "",
+		ServiceAuthEnabled:        false,
+		ServiceTokensFile:         "",
+		OtelEnabled:               false,
+		OTServiceName:             "dp-publishing-dataset-controller",
+		OTExporterOTLPEndpoint:    "localhost:4318",
+		OTBatchTimeout:            5 * time.Second,
 	}
 
 	return cfg, envconfig.Process("", cfg)
@@ -23,8 +23,31 @@ func TestSpec(t *testing.T) {
 				So(cfg.HealthCheckInterval, ShouldEqual, 30*time.Second)
 				So(cfg.HealthCheckCritialTimeout, ShouldEqual, 90*time.Second)
 				So(cfg.BabbageURL, ShouldEqual, "http://localhost:8080")
+				So(cfg.DownloadServiceURL, ShouldEqual, "http://localhost:23600")
 				So(cfg.DatasetsBatchSize, ShouldEqual, 100)
 				So(cfg.DatasetsBatchWorkers, ShouldEqual, 10)
+				So(cfg.AuditSink, ShouldEqual, "log")
+				So(cfg.AuditFilePath, ShouldEqual, "audit.jsonl")
+				So(cfg.AuditHTTPURL, ShouldEqual, "")
+				So(cfg.MaxRequestBodySize, ShouldEqual, 1024*1024)
+				So(cfg.AllowLocalFileBackend, ShouldBeFalse)
+				So(cfg.ResumableUploadTTL, ShouldEqual, 24*time.Hour)
+				So(cfg.ReleaseSource, ShouldEqual, "local")
+				So(cfg.ReleaseLocalFile, ShouldEqual, "")
+				So(cfg.ReleaseLinksFile, ShouldEqual, "release-links.json")
+				So(cfg.ReleaseLinkStore, ShouldEqual, "local")
+				So(cfg.MongoDBURI, ShouldEqual, "mongodb://localhost:27017")
+				So(cfg.MongoDBDatabase, ShouldEqual, "publishing-dataset-controller")
+				So(cfg.MongoDBReleaseLinks, ShouldEqual, "release_links")
+				So(cfg.AuthorisationEnabled, ShouldBeFalse)
+				So(cfg.JWKSFile, ShouldEqual, "")
+				So(cfg.PermissionsBundleFile, ShouldEqual, "")
+				So(cfg.ServiceAuthEnabled, ShouldBeFalse)
+				So(cfg.ServiceTokensFile, ShouldEqual, "")
+				So(cfg.OtelEnabled, ShouldBeFalse)
+				So(cfg.OTServiceName, ShouldEqual, "dp-publishing-dataset-controller")
+				So(cfg.OTExporterOTLPEndpoint, ShouldEqual, "localhost:4318")
+				So(cfg.OTBatchTimeout, ShouldEqual, 5*time.Second)
 			})
 		})
 	})
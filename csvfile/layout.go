@@ -0,0 +1,163 @@
+package csvfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// Delimiters that are detected, in order of preference when more than one fits the file equally well
+var delimiters = []rune{',', '\t', ';', '|'}
+
+// sampleRows is how many rows at the start of a file are used to detect its layout
+const sampleRows = 100
+
+var v4Pattern = regexp.MustCompile(`(?i)^v4_(\d+)$`)
+
+// dateLayouts are the formats of the dates found in ONS datasets, including the mmm-yy months of CMD time code lists
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05.000Z",
+	"02/01/2006",
+	"2006-01",
+	"Jan-06",
+	"Jan 2006",
+}
+
+// detectDelimiter works out the delimiter of a file from a sample of its start. The delimiter that splits every row
+// of the sample into the same number of fields, with the most fields, is chosen. A file with a single column is
+// taken to be comma delimited
+func detectDelimiter(sample []byte) rune {
+	// the last line of the sample may have been cut short
+	if i := bytes.LastIndexByte(sample, '\n'); i > 0 {
+		sample = sample[:i]
+	}
+
+	best, bestFields, bestConsistent := ',', 1, false
+	for _, d := range delimiters {
+		r := csv.NewReader(bytes.NewReader(sample))
+		r.Comma = d
+		r.FieldsPerRecord = -1
+		r.LazyQuotes = true
+
+		fields, consistent := 0, true
+		for i := 0; i < sampleRows; i++ {
+			record, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				consistent = false
+				break
+			}
+			if i == 0 {
+				fields = len(record)
+			} else if len(record) != fields {
+				consistent = false
+			}
+		}
+
+		if fields < 2 {
+			continue
+		}
+		if (consistent && !bestConsistent) || (consistent == bestConsistent && fields > bestFields) {
+			best, bestFields, bestConsistent = d, fields, consistent
+		}
+	}
+
+	return best
+}
+
+// detectV4 returns the layout of a V4 file from its header row, or nil if the file is not in the V4 format
+func detectV4(header []string) *model.V4Header {
+	if len(header) == 0 {
+		return nil
+	}
+	match := v4Pattern.FindStringSubmatch(strings.TrimSpace(header[0]))
+	if match == nil {
+		return nil
+	}
+	markings, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil
+	}
+
+	v4 := &model.V4Header{DataMarkings: markings, Dimensions: []model.V4Dimension{}}
+	for i := 1 + markings; i+1 < len(header); i += 2 {
+		v4.Dimensions = append(v4.Dimensions, model.V4Dimension{CodeList: header[i], Name: header[i+1]})
+	}
+	return v4
+}
+
+// detectHeader reports whether the first row of a file is a header. It is taken to be one if the file is in the
+// V4 format, or if every cell of the row is a different piece of text
+func detectHeader(first []string) bool {
+	if detectV4(first) != nil {
+		return true
+	}
+
+	seen := map[string]bool{}
+	for _, cell := range first {
+		cell = strings.TrimSpace(cell)
+		if cell == "" || seen[cell] || cellType(cell) != model.ColumnTypeString {
+			return false
+		}
+		seen[cell] = true
+	}
+	return len(first) > 0
+}
+
+// columnType returns the type of the cells in a column of rows. Empty cells are ignored, and a column that has no
+// other cells is empty
+func columnType(rows [][]string, column int) string {
+	columnType := model.ColumnTypeEmpty
+	for _, row := range rows {
+		if column >= len(row) || strings.TrimSpace(row[column]) == "" {
+			continue
+		}
+		columnType = widen(columnType, cellType(strings.TrimSpace(row[column])))
+	}
+	return columnType
+}
+
+// widen returns the type that holds the values of both types
+func widen(a, b string) string {
+	switch {
+	case a == b || b == model.ColumnTypeEmpty:
+		return a
+	case a == model.ColumnTypeEmpty:
+		return b
+	case isNumeric(a) && isNumeric(b):
+		return model.ColumnTypeNumber
+	default:
+		return model.ColumnTypeString
+	}
+}
+
+func isNumeric(t string) bool {
+	return t == model.ColumnTypeInteger || t == model.ColumnTypeNumber
+}
+
+func cellType(cell string) string {
+	if _, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return model.ColumnTypeInteger
+	}
+	// ParseFloat also reads words such as NaN and Inf, which are text in a csv file
+	if _, err := strconv.ParseFloat(cell, 64); err == nil && strings.ContainsAny(cell, "0123456789") {
+		return model.ColumnTypeNumber
+	}
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, cell); err == nil {
+			return model.ColumnTypeDate
+		}
+	}
+	return model.ColumnTypeString
+}
@@ -0,0 +1,120 @@
+package csvfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// MaxPreviewRows is how far into a file a preview can page, so that a preview only ever reads the head of a file
+const MaxPreviewRows = 10000
+
+// sampleBytes is how much of the start of a file is used to detect its delimiter
+const sampleBytes = 64 * 1024
+
+// ErrEmpty is returned for a file that has no rows
+var ErrEmpty = errors.New("the file is empty")
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Preview reads the rows at the start of the csv file read from r, detecting its delimiter, header row, column
+// types and V4 layout, and returns limit of its rows after offset. The header row is not counted as a row. Quotes
+// are read leniently, as the preview is meant to show what is wrong with a file rather than reject it
+func Preview(r io.Reader, path string, offset, limit int) (model.Preview, error) {
+	if offset+limit > MaxPreviewRows {
+		return model.Preview{}, fmt.Errorf("a preview only covers the first %d rows", MaxPreviewRows)
+	}
+
+	reader, delimiter, err := newReader(r)
+	if err != nil {
+		return model.Preview{}, err
+	}
+
+	first, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return model.Preview{}, ErrEmpty
+	}
+	if err != nil {
+		return model.Preview{}, err
+	}
+
+	preview := model.Preview{
+		Path:      path,
+		Delimiter: string(delimiter),
+		HasHeader: detectHeader(first),
+		Offset:    offset,
+		Limit:     limit,
+		Rows:      [][]string{},
+	}
+
+	var rows [][]string
+	if !preview.HasHeader {
+		rows = append(rows, first)
+	}
+
+	// one row more than the page is read to find out whether there are more rows after it
+	want := max(offset+limit+1, sampleRows)
+	for len(rows) < want {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return model.Preview{}, err
+		}
+		rows = append(rows, record)
+	}
+
+	columns := len(first)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	sample := rows[:min(len(rows), sampleRows)]
+	for i := 0; i < columns; i++ {
+		column := model.PreviewColumn{Type: columnType(sample, i)}
+		if preview.HasHeader && i < len(first) {
+			column.Name = first[i]
+		}
+		preview.Columns = append(preview.Columns, column)
+	}
+
+	if preview.HasHeader {
+		preview.V4 = detectV4(first)
+	}
+
+	if offset < len(rows) {
+		preview.Rows = rows[offset:min(len(rows), offset+limit)]
+	}
+	preview.Count = len(preview.Rows)
+	preview.HasMore = len(rows) > offset+limit
+
+	return preview, nil
+}
+
+// newReader returns a csv reader for the file read from r, with the delimiter detected from the start of the file
+func newReader(r io.Reader) (*csv.Reader, rune, error) {
+	br := bufio.NewReaderSize(r, sampleBytes)
+	sample, err := br.Peek(sampleBytes)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, 0, err
+	}
+	if bytes.HasPrefix(sample, utf8BOM) {
+		if _, err = br.Discard(len(utf8BOM)); err != nil {
+			return nil, 0, err
+		}
+		sample = sample[len(utf8BOM):]
+	}
+
+	delimiter := detectDelimiter(sample)
+	reader := csv.NewReader(br)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	return reader, delimiter, nil
+}
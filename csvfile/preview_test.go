@@ -0,0 +1,103 @@
+package csvfile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPreview(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Preview", t, func() {
+		Convey("detects the layout of a V4 file and returns a page of its rows", func() {
+			file := "\xEF\xBB\xBFV4_1,Data Marking,mmm-yy,Time,uk-only,Geography,cpih1dim1aggid,Aggregate\n" +
+				"88.1,,Jan-20,Jan-20,K02000001,United Kingdom,cpih1dim1A0,Overall Index\n" +
+				"88.4,p,Feb-20,Feb-20,K02000001,United Kingdom,cpih1dim1A0,Overall Index\n" +
+				"89,,Mar-20,Mar-20,K02000001,United Kingdom,cpih1dim1A0,Overall Index\n"
+
+			p, err := Preview(strings.NewReader(file), "cpih01/time-series/2/cpih.csv", 1, 1)
+			So(err, ShouldBeNil)
+			So(p.Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+			So(p.Delimiter, ShouldEqual, ",")
+			So(p.HasHeader, ShouldBeTrue)
+			So(p.V4, ShouldResemble, &model.V4Header{
+				DataMarkings: 1,
+				Dimensions: []model.V4Dimension{
+					{CodeList: "mmm-yy", Name: "Time"},
+					{CodeList: "uk-only", Name: "Geography"},
+					{CodeList: "cpih1dim1aggid", Name: "Aggregate"},
+				},
+			})
+			So(p.Columns, ShouldHaveLength, 8)
+			So(p.Columns[0], ShouldResemble, model.PreviewColumn{Name: "V4_1", Type: model.ColumnTypeNumber})
+			So(p.Columns[1], ShouldResemble, model.PreviewColumn{Name: "Data Marking", Type: model.ColumnTypeString})
+			So(p.Columns[2].Type, ShouldEqual, model.ColumnTypeDate)
+			So(p.Rows, ShouldResemble, [][]string{{"88.4", "p", "Feb-20", "Feb-20", "K02000001", "United Kingdom", "cpih1dim1A0", "Overall Index"}})
+			So(p.Count, ShouldEqual, 1)
+			So(p.Offset, ShouldEqual, 1)
+			So(p.Limit, ShouldEqual, 1)
+			So(p.HasMore, ShouldBeTrue)
+		})
+
+		Convey("detects a tab delimiter and a file without a header", func() {
+			file := "2019\t1.5\t2019-01-01\n2020\t2\t2020-01-01\n"
+
+			p, err := Preview(strings.NewReader(file), "a.csv", 0, 20)
+			So(err, ShouldBeNil)
+			So(p.Delimiter, ShouldEqual, "\t")
+			So(p.HasHeader, ShouldBeFalse)
+			So(p.V4, ShouldBeNil)
+			So(p.Columns, ShouldResemble, []model.PreviewColumn{
+				{Type: model.ColumnTypeInteger},
+				{Type: model.ColumnTypeNumber},
+				{Type: model.ColumnTypeDate},
+			})
+			So(p.Rows, ShouldHaveLength, 2)
+			So(p.HasMore, ShouldBeFalse)
+		})
+
+		Convey("prefers the delimiter that splits every row the same way", func() {
+			file := "name;note\n\"Smith, J\";a\nJones;\"b, c\"\n"
+
+			p, err := Preview(strings.NewReader(file), "a.csv", 0, 20)
+			So(err, ShouldBeNil)
+			So(p.Delimiter, ShouldEqual, ";")
+			So(p.HasHeader, ShouldBeTrue)
+			So(p.Rows[0], ShouldResemble, []string{"Smith, J", "a"})
+		})
+
+		Convey("returns no rows for a page after the end of the file", func() {
+			p, err := Preview(strings.NewReader("a,b\n1,2\n"), "a.csv", 5, 20)
+			So(err, ShouldBeNil)
+			So(p.Rows, ShouldBeEmpty)
+			So(p.Rows, ShouldNotBeNil)
+			So(p.Count, ShouldEqual, 0)
+		})
+
+		Convey("reads only as far into the file as the page needs", func() {
+			var b strings.Builder
+			b.WriteString("id,value\n")
+			for i := 0; i < MaxPreviewRows*2; i++ {
+				fmt.Fprintf(&b, "%d,%d\n", i, i*2)
+			}
+
+			p, err := Preview(strings.NewReader(b.String()), "a.csv", 200, 10)
+			So(err, ShouldBeNil)
+			So(p.Rows[0], ShouldResemble, []string{"200", "400"})
+			So(p.Columns[1].Type, ShouldEqual, model.ColumnTypeInteger)
+			So(p.HasMore, ShouldBeTrue)
+
+			_, err = Preview(strings.NewReader(b.String()), "a.csv", MaxPreviewRows, 1)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("errors for an empty file", func() {
+			_, err := Preview(strings.NewReader(""), "a.csv", 0, 20)
+			So(err, ShouldEqual, ErrEmpty)
+		})
+	})
+}
@@ -0,0 +1,201 @@
+package csvfile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// MaxErrors is how many errors are listed for a file before validation stops
+const MaxErrors = 100
+
+// Validate checks the structure of the csv file read from r: that it is no larger than maxSize bytes, is UTF-8
+// encoded, is comma separated and every row has as many columns as the first. Files in the V4 format also have their header checked, and
+// each dimension of each row must have a code and label, with the same code always having the same label.
+// Errors are returned with the line and column they are on, as JSON pointers such as /lines/3/columns/2, counting
+// from 1. An error is only returned if the file cannot be read.
+func Validate(r io.Reader, maxSize int64) ([]model.FieldError, error) {
+	v := &validator{}
+	counter := &countingReader{r: io.LimitReader(r, maxSize+1)}
+
+	reader, delimiter, err := newReader(counter)
+	if err != nil {
+		return nil, err
+	}
+	// the dataset API only reads comma separated files, so a file using another delimiter is not checked further
+	if delimiter != ',' {
+		return []model.FieldError{{Path: "/lines/1", Message: fmt.Sprintf("the file must be separated by commas, not %q", delimiter)}}, nil
+	}
+	// the preview reads quotes leniently, but a file that is attached has to be read by the dataset API
+	reader.LazyQuotes = false
+
+	err = v.validate(reader)
+	if counter.n > maxSize {
+		// the rest of the file was not read, so only the size is reported
+		return []model.FieldError{{Path: "/", Message: fmt.Sprintf("the file must not be larger than %d bytes", maxSize)}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return v.errors, nil
+}
+
+type validator struct {
+	errors  []model.FieldError
+	columns int
+	v4      *model.V4Header
+	// labels holds the label of each code of each dimension of a V4 file, and the line it was first seen on
+	labels []map[string]codeLabel
+}
+
+type codeLabel struct {
+	label string
+	line  int
+}
+
+// validate reads every row of the file, stopping early when MaxErrors errors have been found
+func (v *validator) validate(reader *csv.Reader) error {
+	for first := true; len(v.errors) < MaxErrors; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			if first {
+				v.add("/", ErrEmpty.Error())
+			}
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// the reader cannot carry on reliably after a quoting error, so it is the last error reported
+			v.add(fmt.Sprintf("/lines/%d", parseErr.Line), fmt.Sprintf("%s at character %d", parseErr.Err.Error(), parseErr.Column))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// a row can be split across lines by a quoted line break, so its line is where it starts
+		line, _ := reader.FieldPos(0)
+		if first {
+			v.header(record, line)
+		} else {
+			v.row(record, line)
+		}
+	}
+
+	v.add("/", fmt.Sprintf("validation stopped after %d errors", MaxErrors))
+	return nil
+}
+
+// header checks the first row of the file, which sets the number of columns every other row must have
+func (v *validator) header(record []string, line int) {
+	v.columns = len(record)
+	v.encoding(record, line)
+
+	first := strings.TrimSpace(record[0])
+	if !strings.HasPrefix(strings.ToLower(first), "v4_") {
+		return
+	}
+
+	v.v4 = detectV4(record)
+	if v.v4 == nil {
+		v.add(fmt.Sprintf("/lines/%d/columns/1", line), fmt.Sprintf("%q is not a V4 header, which is V4_ followed by the number of data markings columns", first))
+		return
+	}
+
+	dimensionColumns := len(record) - 1 - v.v4.DataMarkings
+	switch {
+	case dimensionColumns < 2:
+		v.add(fmt.Sprintf("/lines/%d", line), fmt.Sprintf("a V4 file with %d data markings columns must have a code list and label column for at least one dimension after them", v.v4.DataMarkings))
+	case dimensionColumns%2 != 0:
+		v.add(fmt.Sprintf("/lines/%d/columns/%d", line, len(record)), "every dimension of a V4 file must have a code list column and a label column")
+	}
+
+	names := map[string]int{}
+	for i, d := range v.v4.Dimensions {
+		codeColumn := 2 + v.v4.DataMarkings + 2*i
+		if strings.TrimSpace(d.CodeList) == "" {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn), "the code list of a dimension must not be empty")
+		}
+		if strings.TrimSpace(d.Name) == "" {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn+1), "the name of a dimension must not be empty")
+			continue
+		}
+		if column, ok := names[strings.ToLower(d.Name)]; ok {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn+1), fmt.Sprintf("dimension %q is also in column %d", d.Name, column))
+		}
+		names[strings.ToLower(d.Name)] = codeColumn + 1
+	}
+
+	v.labels = make([]map[string]codeLabel, len(v.v4.Dimensions))
+	for i := range v.labels {
+		v.labels[i] = map[string]codeLabel{}
+	}
+}
+
+// row checks a row after the header
+func (v *validator) row(record []string, line int) {
+	v.encoding(record, line)
+
+	if len(record) != v.columns {
+		v.add(fmt.Sprintf("/lines/%d", line), fmt.Sprintf("the row has %d columns but the header has %d", len(record), v.columns))
+		return
+	}
+	if v.v4 == nil {
+		return
+	}
+
+	for i, d := range v.v4.Dimensions {
+		codeColumn := 2 + v.v4.DataMarkings + 2*i
+		code, label := strings.TrimSpace(record[codeColumn-1]), strings.TrimSpace(record[codeColumn])
+		if code == "" {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn), fmt.Sprintf("the %s code must not be empty", d.Name))
+		}
+		if label == "" {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn+1), fmt.Sprintf("the %s label must not be empty", d.Name))
+		}
+		if code == "" || label == "" {
+			continue
+		}
+
+		seen, ok := v.labels[i][code]
+		if !ok {
+			v.labels[i][code] = codeLabel{label: label, line: line}
+			continue
+		}
+		if seen.label != label {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, codeColumn+1), fmt.Sprintf("%s code %q has the label %q, but has the label %q on line %d", d.Name, code, label, seen.label, seen.line))
+		}
+	}
+}
+
+// encoding reports the cells of a row that are not UTF-8 encoded
+func (v *validator) encoding(record []string, line int) {
+	for i, cell := range record {
+		if !utf8.ValidString(cell) {
+			v.add(fmt.Sprintf("/lines/%d/columns/%d", line, i+1), "the cell is not UTF-8 encoded")
+		}
+	}
+}
+
+func (v *validator) add(path, message string) {
+	v.errors = append(v.errors, model.FieldError{Path: path, Message: message})
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+package csvfile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitValidate(t *testing.T) {
+	t.Parallel()
+
+	const header = "V4_1,Data Marking,mmm-yy,Time,uk-only,Geography\n"
+
+	Convey("test Validate", t, func() {
+		Convey("accepts a valid V4 file", func() {
+			file := header +
+				"88.1,,Jan-20,Jan-20,K02000001,United Kingdom\n" +
+				",x,\"Feb-20\",Feb-20,K02000001,United Kingdom\n"
+
+			errs, err := Validate(strings.NewReader(file), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldBeEmpty)
+		})
+
+		Convey("accepts a file that is not in the V4 format with consistent columns", func() {
+			errs, err := Validate(strings.NewReader("\xEF\xBB\xBFa,b\n1,2\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldBeEmpty)
+		})
+
+		Convey("reports a file that is not separated by commas", func() {
+			errs, err := Validate(strings.NewReader("a;b\n1;2\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{{Path: "/lines/1", Message: `the file must be separated by commas, not ';'`}})
+
+			errs, err = Validate(strings.NewReader(strings.ReplaceAll(header, ",", "\t")+"88.1\t\tJan-20\tJan-20\tK02000001\tUnited Kingdom\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{{Path: "/lines/1", Message: `the file must be separated by commas, not '\t'`}})
+		})
+
+		Convey("reports rows with a different number of columns to the header", func() {
+			errs, err := Validate(strings.NewReader("a,b\n1,2\n1,2,3\n\"multi\nline\"\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{
+				{Path: "/lines/3", Message: "the row has 3 columns but the header has 2"},
+				{Path: "/lines/4", Message: "the row has 1 columns but the header has 2"},
+			})
+		})
+
+		Convey("reports dimensions without a code or label and codes with more than one label", func() {
+			file := header +
+				"88.1,,Jan-20,Jan-20,K02000001,United Kingdom\n" +
+				"88.4,,Feb-20,,K02000001,UK\n" +
+				"88.4,,,Mar-20,K02000001,United Kingdom\n"
+
+			errs, err := Validate(strings.NewReader(file), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{
+				{Path: "/lines/3/columns/4", Message: "the Time label must not be empty"},
+				{Path: "/lines/3/columns/6", Message: `Geography code "K02000001" has the label "UK", but has the label "United Kingdom" on line 2`},
+				{Path: "/lines/4/columns/3", Message: "the Time code must not be empty"},
+			})
+		})
+
+		Convey("reports V4 headers that are not valid", func() {
+			cases := map[string][]model.FieldError{
+				"V4_x,mmm-yy,Time\n": {{Path: "/lines/1/columns/1", Message: `"V4_x" is not a V4 header, which is V4_ followed by the number of data markings columns`}},
+				"V4_2,a,b,mmm-yy\n":  {{Path: "/lines/1", Message: "a V4 file with 2 data markings columns must have a code list and label column for at least one dimension after them"}},
+				"V4_0,mmm-yy,Time,uk-only\n": {
+					{Path: "/lines/1/columns/4", Message: "every dimension of a V4 file must have a code list column and a label column"},
+				},
+				"V4_0,mmm-yy,Time,calendar-years,time\n": {{Path: "/lines/1/columns/5", Message: `dimension "time" is also in column 3`}},
+				"V4_0,,Time\n":                           {{Path: "/lines/1/columns/2", Message: "the code list of a dimension must not be empty"}},
+			}
+			for file, expected := range cases {
+				errs, err := Validate(strings.NewReader(file), 1024)
+				So(err, ShouldBeNil)
+				So(errs, ShouldResemble, expected)
+			}
+		})
+
+		Convey("reports cells that are not UTF-8 encoded", func() {
+			errs, err := Validate(strings.NewReader("a,b\n1,caf\xe9\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{{Path: "/lines/2/columns/2", Message: "the cell is not UTF-8 encoded"}})
+		})
+
+		Convey("reports a quoting error and stops", func() {
+			errs, err := Validate(strings.NewReader("a,b\n1,2\"x\n3,4,5\n"), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldHaveLength, 1)
+			So(errs[0].Path, ShouldEqual, "/lines/2")
+		})
+
+		Convey("reports a file that is empty or too large", func() {
+			errs, err := Validate(strings.NewReader(""), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{{Path: "/", Message: "the file is empty"}})
+
+			errs, err = Validate(strings.NewReader(header+strings.Repeat("1,,Jan-20,Jan-20,K02000001,United Kingdom\n", 100)), 1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldResemble, []model.FieldError{{Path: "/", Message: "the file must not be larger than 1024 bytes"}})
+		})
+
+		Convey("stops after MaxErrors errors", func() {
+			var b strings.Builder
+			b.WriteString("a,b\n")
+			for i := 0; i < MaxErrors*2; i++ {
+				fmt.Fprintf(&b, "%d\n", i)
+			}
+
+			errs, err := Validate(strings.NewReader(b.String()), 1024*1024)
+			So(err, ShouldBeNil)
+			So(errs, ShouldHaveLength, MaxErrors+1)
+			So(errs[MaxErrors].Message, ShouldEqual, "validation stopped after 100 errors")
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/identity"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const unknownUser = "unknown"
+
+// auditIgnoredFields are set by the dataset API rather than the caller, so are left out of audit diffs
+var auditIgnoredFields = []string{"last_updated", "links"}
+
+// auditedMetadata holds the dataset, version and dimension fields written by the put metadata handlers
+type auditedMetadata struct {
+	Dataset    datasetApiModels.Dataset     `json:"dataset"`
+	Version    datasetApiModels.Version     `json:"version"`
+	Dimensions []datasetApiModels.Dimension `json:"dimensions,omitempty"`
+}
+
+// getCurrentMetadata gets the dataset and version as they were read before a write, so the write can be audited
+func getCurrentMetadata(l lockedVersion) (*auditedMetadata, error) {
+	if l.Dataset.Next == nil {
+		return nil, errors.New("dataset has no next document")
+	}
+
+	return &auditedMetadata{
+		Dataset:    *l.Dataset.Next,
+		Version:    l.Version,
+		Dimensions: l.Version.Dimensions,
+	}, nil
+}
+
+// newAuditEvent creates an audit event for a write made by the service that made the request, or otherwise
+// by the caller identified by the access token
+func newAuditEvent(ctx context.Context, action, userAccessToken, collectionID, datasetID, edition, version string) audit.Event {
+	user := unknownUser
+	service, isService := authorisation.ServiceCallerFromContext(ctx)
+	if isService {
+		user = service.Name
+	} else if caller, err := identity.Caller(ctx, userAccessToken); err == nil {
+		user = caller.String()
+	}
+
+	return audit.Event{
+		User:         user,
+		Service:      isService,
+		CollectionID: collectionID,
+		DatasetID:    datasetID,
+		Edition:      edition,
+		Version:      version,
+		Action:       action,
+	}
+}
+
+// recordAuditDiff records an audit event with the field level changes between before and after
+func recordAuditDiff(ctx context.Context, ar AuditRecorder, e audit.Event, before, after interface{}) {
+	changes, err := audit.Diff(before, after, auditIgnoredFields...)
+	if err != nil {
+		log.Warn(ctx, "failed to diff metadata for audit", log.FormatErrors([]error{err}), log.Data{"datasetID": e.DatasetID})
+	}
+	e.Changes = changes
+
+	ar.Record(ctx, e)
+}
@@ -0,0 +1,147 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newMockAuditRecorder returns an AuditRecorderMock that accepts every event and has no history
+func newMockAuditRecorder() *AuditRecorderMock {
+	return &AuditRecorderMock{
+		RecordFunc: func(ctx context.Context, e audit.Event) {},
+		HistoryFunc: func(ctx context.Context, datasetID string) ([]audit.Event, error) {
+			return []audit.Event{}, nil
+		},
+	}
+}
+
+func TestUnitAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test newAuditEvent", t, func() {
+		Convey("records the caller identity from the access token", func() {
+			e := newAuditEvent(ctx, "put-metadata", testAccessToken("editor@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+
+			So(e, ShouldResemble, audit.Event{
+				User:         "editor@ons.gov.uk",
+				CollectionID: "testcollection",
+				DatasetID:    "test-dataset",
+				Edition:      "2021",
+				Version:      "1",
+				Action:       "put-metadata",
+			})
+		})
+
+		Convey("records an unknown user if the access token cannot be read", func() {
+			e := newAuditEvent(ctx, "put-metadata", "testuser", "testcollection", "test-dataset", "2021", "1")
+
+			So(e.User, ShouldEqual, unknownUser)
+		})
+
+		Convey("records the service that made the request", func() {
+			serviceCtx := authorisation.WithServiceCaller(ctx, authorisation.ServiceCaller{Name: "bulk-metadata-fix", Token: "service-token"})
+			e := newAuditEvent(serviceCtx, "put-metadata", "service-token", "testcollection", "test-dataset", "2021", "1")
+
+			So(e.User, ShouldEqual, "bulk-metadata-fix")
+			So(e.Service, ShouldBeTrue)
+		})
+	})
+
+	Convey("test recordAuditDiff", t, func() {
+		ar := newMockAuditRecorder()
+		before := auditedMetadata{
+			Dataset: datasetApiModels.Dataset{ID: "test-dataset", Title: "old title"},
+			Version: datasetApiModels.Version{ReleaseDate: "2021-01-01"},
+		}
+		after := auditedMetadata{
+			Dataset: datasetApiModels.Dataset{ID: "test-dataset", Title: "new title"},
+			Version: datasetApiModels.Version{ReleaseDate: "2021-01-01"},
+		}
+
+		recordAuditDiff(ctx, ar, audit.Event{DatasetID: "test-dataset"}, before, after)
+
+		So(ar.RecordCalls(), ShouldHaveLength, 1)
+		So(ar.RecordCalls()[0].E.Changes, ShouldResemble, []audit.Change{{Field: "dataset.title", From: "old title", To: "new title"}})
+	})
+
+	Convey("test getAuditHistory", t, func() {
+		const target = "/datasets/{datasetID}/audit"
+
+		newAuditZebedeeClient := func() *ZebedeeClientMock {
+			return &ZebedeeClientMock{
+				GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+					return zebedeeclient.Collection{ID: collectionID}, nil
+				},
+			}
+		}
+
+		Convey("returns the history of the dataset", func() {
+			ar := newMockAuditRecorder()
+			ar.HistoryFunc = func(ctx context.Context, datasetID string) ([]audit.Event, error) {
+				return []audit.Event{{DatasetID: datasetID, Action: "put-metadata", User: "editor@ons.gov.uk"}}, nil
+			}
+
+			req := httptest.NewRequest("GET", "/datasets/test-dataset/audit", http.NoBody)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			w := doTestRequest(target, req, GetAuditHistory(ar, newAuditZebedeeClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldContainSubstring, `"dataset_id":"test-dataset","events":[{`)
+			So(w.Body.String(), ShouldContainSubstring, `"user":"editor@ons.gov.uk"`)
+			So(ar.HistoryCalls()[0].DatasetID, ShouldEqual, "test-dataset")
+		})
+
+		Convey("returns 500 if the history cannot be read", func() {
+			ar := newMockAuditRecorder()
+			ar.HistoryFunc = func(ctx context.Context, datasetID string) ([]audit.Event, error) {
+				return nil, errors.New("test store error")
+			}
+
+			req := httptest.NewRequest("GET", "/datasets/test-dataset/audit", http.NoBody)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			w := doTestRequest(target, req, GetAuditHistory(ar, newAuditZebedeeClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Body.String(), ShouldEqual, "error getting audit history\n")
+		})
+
+		Convey("returns 403 without reading the history if zebedee does not let the caller read the collection", func() {
+			ar := newMockAuditRecorder()
+			zc := newAuditZebedeeClient()
+			zc.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{}, zebedeeclient.ErrInvalidZebedeeResponse{ActualCode: http.StatusUnauthorized, URI: "/collectionDetails/testcollection"}
+			}
+
+			req := httptest.NewRequest("GET", "/datasets/test-dataset/audit", http.NoBody)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "viewer")
+			w := doTestRequest(target, req, GetAuditHistory(ar, zc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusForbidden)
+			So(zc.GetCollectionCalls()[0].UserAccessToken, ShouldEqual, "viewer")
+			So(ar.HistoryCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 if no collection ID header is set", func() {
+			req := httptest.NewRequest("GET", "/datasets/test-dataset/audit", http.NoBody)
+			req.Header.Set("X-Florence-Token", "testuser")
+			w := doTestRequest(target, req, GetAuditHistory(newMockAuditRecorder(), newAuditZebedeeClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+	})
+}
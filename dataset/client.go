@@ -2,14 +2,19 @@ package dataset
 
 import (
 	"context"
+	"io"
 
 	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
 	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
 )
 
-//go:generate moq -out mocks_test.go -pkg dataset . DatasetAPIClient ZebedeeClient BabbageClient
+//go:generate moq -out mocks_test.go -pkg dataset . DatasetAPIClient ZebedeeClient BabbageClient AuditRecorder FileBackend ChunkUploader ReleaseCalendar DownloadClient IdentityClient
 
 type DatasetAPIClient interface {
 	GetDatasetsInBatches(ctx context.Context, headers datasetApiSdk.Headers, batchSize, maxWorkers int) (datasetApiSdk.DatasetsList, error)
@@ -18,10 +23,12 @@ type DatasetAPIClient interface {
 	GetDatasetCurrentAndNext(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (m datasetApiModels.DatasetUpdate, err error)
 	GetVersionWithHeaders(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (v datasetApiModels.Version, h datasetApiSdk.ResponseHeaders, err error)
 	GetVersion(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (m datasetApiModels.Version, err error)
+	GetVersionDimensionOptions(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (m datasetApiSdk.VersionDimensionOptionsList, err error)
 	GetVersionsInBatches(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string, batchSize, maxWorkers int) (versions datasetApiSdk.VersionsList, err error)
 	PutDataset(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error
 	PutMetadata(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, metadata datasetApiModels.EditableMetadata, versionEtag string) error
 	PutVersion(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID string, version datasetApiModels.Version) (updatedVersion datasetApiModels.Version, err error)
+	PutVersionState(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, state string) (err error)
 	PutInstance(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (eTag string, err error)
 }
 
@@ -29,8 +36,41 @@ type ZebedeeClient interface {
 	GetCollection(ctx context.Context, userAccessToken, collectionID string) (c zebedeeclient.Collection, err error)
 	PutDatasetInCollection(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error
 	PutDatasetVersionInCollection(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error
+	DeleteDatasetFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID string) error
+	DeleteDatasetVersionFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error
 }
 
 type BabbageClient interface {
 	GetTopics(ctx context.Context, userAccessToken string) (result babbageclient.TopicsResult, err error)
 }
+
+type AuditRecorder interface {
+	Record(ctx context.Context, e audit.Event)
+	History(ctx context.Context, datasetID string) ([]audit.Event, error)
+}
+
+type FileBackend interface {
+	Upload(ctx context.Context, path, contentType string, r io.Reader) (upload.File, error)
+	Get(ctx context.Context, path string) (upload.File, error)
+	List(ctx context.Context, prefix string) ([]upload.File, error)
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+}
+
+type ChunkUploader interface {
+	HasChunk(ctx context.Context, path string, c upload.Chunk) (bool, error)
+	WriteChunk(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error)
+}
+
+type IdentityClient interface {
+	GetUser(ctx context.Context, userAccessToken, userID string) (users.User, error)
+}
+
+type DownloadClient interface {
+	Open(ctx context.Context, userAccessToken, collectionID, href string) (io.ReadCloser, error)
+}
+
+type ReleaseCalendar interface {
+	Link(ctx context.Context, userAccessToken, datasetID, edition, version, releaseID string) (releases.Release, error)
+	Unlink(ctx context.Context, datasetID, edition, version string) (releases.Link, error)
+	Linked(ctx context.Context, userAccessToken, datasetID, edition, version string) (releases.Link, releases.Release, error)
+}
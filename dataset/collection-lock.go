@@ -0,0 +1,88 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// ErrCollectionLocked is returned when a dataset or version is already held by a collection other than the caller's
+type ErrCollectionLocked struct {
+	CollectionID string
+}
+
+func (e ErrCollectionLocked) Error() string {
+	return fmt.Sprintf("dataset is locked by collection %s", e.CollectionID)
+}
+
+// lockedVersion is the dataset and version read to check the collection lock, so a handler does not have to
+// read them again. VersionETag is the ETag the version was read with, for a handler that updates it.
+type lockedVersion struct {
+	Dataset     datasetApiModels.DatasetUpdate
+	Version     datasetApiModels.Version
+	VersionETag string
+}
+
+// getVersionForEdit reads the dataset and version that a handler is about to edit, without checking the collection lock
+func getVersionForEdit(ctx context.Context, dc DatasetAPIClient, headers datasetApiSdk.Headers, datasetID, edition, version string) (lockedVersion, error) {
+	d, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	if err != nil {
+		return lockedVersion{}, err
+	}
+
+	v, h, err := dc.GetVersionWithHeaders(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		return lockedVersion{}, err
+	}
+
+	return lockedVersion{Dataset: d, Version: v, VersionETag: h.ETag}, nil
+}
+
+// getCollectionLock returns an ErrCollectionLocked if the dataset or version is held by a collection other than
+// collectionID. A dataset or version that is not in a collection is not locked.
+func getCollectionLock(ctx context.Context, dc DatasetAPIClient, headers datasetApiSdk.Headers, collectionID, datasetID, edition, version string) (lockedVersion, error) {
+	d, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	if err != nil {
+		return lockedVersion{}, err
+	}
+	if d.Next != nil && d.Next.CollectionID != "" && d.Next.CollectionID != collectionID {
+		return lockedVersion{}, ErrCollectionLocked{CollectionID: d.Next.CollectionID}
+	}
+
+	v, h, err := dc.GetVersionWithHeaders(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		return lockedVersion{}, err
+	}
+	if v.CollectionID != "" && v.CollectionID != collectionID {
+		return lockedVersion{}, ErrCollectionLocked{CollectionID: v.CollectionID}
+	}
+
+	return lockedVersion{Dataset: d, Version: v, VersionETag: h.ETag}, nil
+}
+
+// checkCollectionLock writes an error response and returns false if the dataset or version cannot be read or
+// is held by a collection other than the caller's. Otherwise it returns the dataset and version it read.
+func checkCollectionLock(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, headers datasetApiSdk.Headers, datasetID, edition, version string) (lockedVersion, bool) {
+	ctx := req.Context()
+	logData := log.Data{"datasetID": datasetID, "edition": edition, "version": version, "collectionID": headers.CollectionID}
+
+	l, err := getCollectionLock(ctx, dc, headers, headers.CollectionID, datasetID, edition, version)
+	if err == nil {
+		return l, true
+	}
+
+	if locked, ok := err.(ErrCollectionLocked); ok {
+		logData["lockedBy"] = locked.CollectionID
+		log.Error(ctx, "dataset is locked by another collection", err, logData)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return lockedVersion{}, false
+	}
+
+	log.Error(ctx, "error checking collection lock", err, logData)
+	setErrorStatusCode(req, w, err, datasetID)
+	return lockedVersion{}, false
+}
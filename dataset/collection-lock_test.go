@@ -0,0 +1,66 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetCollectionLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	headers := datasetApiSdk.Headers{CollectionID: "testcollection", AccessToken: "testuser"}
+
+	newMockClient := func(datasetCollectionID, versionCollectionID string) *DatasetAPIClientMock {
+		return &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID, CollectionID: datasetCollectionID}}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{CollectionID: versionCollectionID}, datasetApiSdk.ResponseHeaders{}, nil
+			},
+		}
+	}
+
+	Convey("test getCollectionLock", t, func() {
+		Convey("returns nil when the dataset and version are in the caller's collection", func() {
+			l, err := getCollectionLock(ctx, newMockClient("testcollection", "testcollection"), headers, "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldBeNil)
+			So(l.Dataset.Next.CollectionID, ShouldEqual, "testcollection")
+			So(l.Version.CollectionID, ShouldEqual, "testcollection")
+		})
+
+		Convey("returns nil when the dataset and version are not in a collection", func() {
+			_, err := getCollectionLock(ctx, newMockClient("", ""), headers, "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("returns the owning collection when the dataset is in another collection", func() {
+			dc := newMockClient("othercollection", "")
+			_, err := getCollectionLock(ctx, dc, headers, "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldResemble, ErrCollectionLocked{CollectionID: "othercollection"})
+			So(err.Error(), ShouldEqual, "dataset is locked by collection othercollection")
+			So(dc.GetVersionWithHeadersCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns the owning collection when the version is in another collection", func() {
+			_, err := getCollectionLock(ctx, newMockClient("", "othercollection"), headers, "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldResemble, ErrCollectionLocked{CollectionID: "othercollection"})
+		})
+
+		Convey("returns the dataset API error", func() {
+			dc := newMockClient("", "")
+			dc.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{}, datasetApiSdk.ResponseHeaders{}, errors.New("test dataset API error")
+			}
+			_, err := getCollectionLock(ctx, dc, headers, "testcollection", "test-dataset", "2021", "1")
+			So(err.Error(), ShouldEqual, "test dataset API error")
+		})
+	})
+}
@@ -0,0 +1,412 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/docs"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/requestid"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func init() {
+	// the metadata exports are json documents with their own media types
+	openapi3filter.RegisterBodyDecoder(csvwContentType, openapi3filter.JSONBodyDecoder)
+	openapi3filter.RegisterBodyDecoder(jsonLDContentType, openapi3filter.JSONBodyDecoder)
+}
+
+// newContractRouter loads the OpenAPI spec and returns a router that finds the spec operation for a request
+func newContractRouter() (routers.Router, error) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData(docs.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err = spec.Validate(loader.Context); err != nil {
+		return nil, err
+	}
+	// match requests whatever host they are made to
+	spec.Servers = nil
+
+	return gorillamux.NewRouter(spec)
+}
+
+// validateContract checks that the request, with the body it was sent with, and the response written for it match
+// the OpenAPI spec
+func validateContract(specRouter routers.Router, req *http.Request, body string, w *httptest.ResponseRecorder) error {
+	req.Body = io.NopCloser(bytes.NewBufferString(body))
+
+	route, pathParams, err := specRouter.FindRoute(req)
+	if err != nil {
+		return err
+	}
+
+	requestInput := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{IncludeResponseStatus: true},
+	}
+	if err = openapi3filter.ValidateRequest(req.Context(), requestInput); err != nil {
+		return err
+	}
+
+	return openapi3filter.ValidateResponse(req.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 w.Code,
+		Header:                 w.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+		Options:                requestInput.Options,
+	})
+}
+
+func TestContract(t *testing.T) {
+	t.Parallel()
+
+	specRouter, err := newContractRouter()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI spec: %v", err)
+	}
+
+	nationalStatistic := true
+	dataset := &datasetApiModels.Dataset{
+		ID:                "cpih01",
+		Title:             "Consumer Prices Index including owner occupiers' housing costs",
+		CollectionID:      "testcollection",
+		Keywords:          []string{"inflation"},
+		NationalStatistic: &nationalStatistic,
+		Contacts:          []datasetApiModels.ContactDetails{{Name: "Prices team", Email: "prices@ons.gov.uk"}},
+		Links: &datasetApiModels.DatasetLinks{
+			LatestVersion: &datasetApiModels.LinkObject{HRef: "/datasets/cpih01/editions/time-series/versions/1"},
+		},
+	}
+	numberOfOptions := 12
+	version := datasetApiModels.Version{
+		ID:           "version-2",
+		Edition:      "time-series",
+		Version:      2,
+		State:        "associated",
+		CollectionID: "testcollection",
+		ReleaseDate:  "2020-11-07T00:00:00.000Z",
+		Dimensions:   []datasetApiModels.Dimension{{ID: "aggregate", Name: "aggregate", Label: "Aggregate", NumberOfOptions: &numberOfOptions}},
+		Downloads:    &datasetApiModels.DownloadList{CSV: &datasetApiModels.DownloadObject{HRef: "http://localhost:23600/downloads/cpih01.csv", Size: "1024"}},
+		Temporal:     &[]datasetApiModels.TemporalFrequency{{StartDate: "2005-01-01", EndDate: "2020-10-01", Frequency: "monthly"}},
+	}
+
+	dc := &DatasetAPIClientMock{
+		GetDatasetsInBatchesFunc: func(ctx context.Context, headers datasetApiSdk.Headers, batchSize, maxWorkers int) (datasetApiSdk.DatasetsList, error) {
+			return datasetApiSdk.DatasetsList{Items: []datasetApiModels.DatasetUpdate{{ID: "cpih01", Next: dataset}}}, nil
+		},
+		GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+			return datasetApiModels.DatasetUpdate{ID: datasetID, Current: dataset, Next: dataset}, nil
+		},
+		GetEditionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string) (datasetApiModels.Edition, error) {
+			return datasetApiModels.Edition{Edition: edition}, nil
+		},
+		GetEditionsFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.EditionsList, error) {
+			return datasetApiSdk.EditionsList{Items: []datasetApiModels.Edition{{
+				Edition: "time-series",
+				Links:   &datasetApiModels.EditionUpdateLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "/datasets/cpih01/editions/time-series/versions/2"}},
+			}}}, nil
+		},
+		GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v string) (datasetApiModels.Version, error) {
+			return version, nil
+		},
+		GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+			return version, datasetApiSdk.ResponseHeaders{ETag: "version-etag"}, nil
+		},
+		GetVersionDimensionOptionsFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v, dimension string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+			return datasetApiSdk.VersionDimensionOptionsList{Items: []datasetApiModels.PublicDimensionOption{{Name: dimension, Option: "cpih1dim1A0", Label: "Overall Index"}}}, nil
+		},
+		GetVersionsInBatchesFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string, batchSize, maxWorkers int) (datasetApiSdk.VersionsList, error) {
+			return datasetApiSdk.VersionsList{Items: []datasetApiModels.Version{version}}, nil
+		},
+		PutDatasetFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
+			return nil
+		},
+		PutMetadataFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v string, metadata datasetApiModels.EditableMetadata, versionEtag string) error {
+			return nil
+		},
+		PutVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v string, update datasetApiModels.Version) (datasetApiModels.Version, error) {
+			return update, nil
+		},
+		PutVersionStateFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, v, state string) error {
+			return nil
+		},
+		PutInstanceFunc: func(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (string, error) {
+			return "", nil
+		},
+	}
+
+	zc := &ZebedeeClientMock{
+		GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+			return zebedeeclient.Collection{ID: collectionID, Datasets: []zebedeeclient.CollectionItem{{ID: "cpih01", State: "InProgress", LastEditedBy: "editor@ons.gov.uk"}}}, nil
+		},
+		PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+			return nil
+		},
+		PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, v, state string) error {
+			return nil
+		},
+		DeleteDatasetFromCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, datasetID string) error {
+			return nil
+		},
+		DeleteDatasetVersionFromCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, datasetID, edition, v string) error {
+			return nil
+		},
+	}
+
+	bc := &BabbageClientMock{
+		GetTopicsFunc: func(ctx context.Context, userAccessToken string) (babbageclient.TopicsResult, error) {
+			return babbageclient.TopicsResult{}, nil
+		},
+	}
+
+	ar := newMockAuditRecorder()
+
+	uploadedAt := time.Date(2020, 11, 7, 0, 0, 0, 0, time.UTC)
+	csvFile := upload.File{Path: "cpih01/time-series/2/cpih.csv", ContentType: "text/csv", SizeInBytes: 1024, State: upload.StateUploaded, ScanState: upload.ScanSkipped, UploadedAt: &uploadedAt}
+	fb := &FileBackendMock{
+		UploadFunc: func(ctx context.Context, path, contentType string, r io.Reader) (upload.File, error) {
+			return upload.File{Path: path, ContentType: contentType, State: upload.StateUploaded, ScanState: upload.ScanSkipped, UploadedAt: &uploadedAt}, nil
+		},
+		GetFunc: func(ctx context.Context, path string) (upload.File, error) {
+			return csvFile, nil
+		},
+		ListFunc: func(ctx context.Context, prefix string) ([]upload.File, error) {
+			return []upload.File{csvFile}, nil
+		},
+		OpenFunc: func(ctx context.Context, path string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewBufferString("V4_0,mmm-yy,Time\n88.1,Jan-20,Jan-20\n")), nil
+		},
+	}
+
+	cu := &ChunkUploaderMock{
+		HasChunkFunc: func(ctx context.Context, path string, c upload.Chunk) (bool, error) {
+			return c.Number == 1, nil
+		},
+		WriteChunkFunc: func(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error) {
+			if c.Number < c.TotalChunks {
+				return upload.File{Path: path, SizeInBytes: c.TotalSize, State: upload.StateCreated, ScanState: upload.ScanPending}, nil
+			}
+			return upload.File{Path: path, SizeInBytes: c.TotalSize, State: upload.StateUploaded, ScanState: upload.ScanSkipped, UploadedAt: &uploadedAt}, nil
+		},
+	}
+
+	release := releases.Release{ID: "cpih-oct-2020", Title: "Consumer price inflation, UK: October 2020", ReleaseDate: time.Date(2020, 11, 18, 7, 0, 0, 0, time.UTC), URI: "/releases/consumerpriceinflationukoctober2020"}
+	rc := &ReleaseCalendarMock{
+		LinkFunc: func(ctx context.Context, userAccessToken, datasetID, edition, v, releaseID string) (releases.Release, error) {
+			if releaseID != release.ID {
+				return releases.Release{}, releases.ErrNotFound
+			}
+			return release, nil
+		},
+		LinkedFunc: func(ctx context.Context, userAccessToken, datasetID, edition, v string) (releases.Link, releases.Release, error) {
+			return releases.Link{ReleaseID: release.ID, LinkedAt: uploadedAt}, release, nil
+		},
+		UnlinkFunc: func(ctx context.Context, datasetID, edition, v string) (releases.Link, error) {
+			return releases.Link{ReleaseID: release.ID, LinkedAt: uploadedAt}, nil
+		},
+	}
+
+	router := mux.NewRouter()
+	router.Use(requestid.Middleware)
+	router.Path("/datasets").HandlerFunc(GetAll(dc, 10, 1)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/create").HandlerFunc(GetTopics(bc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/audit").HandlerFunc(GetAuditHistory(ar, zc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions").HandlerFunc(GetEditions(dc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions").HandlerFunc(GetVersions(dc, 10, 1)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(GetMetadataHandler(dc, zc, rc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(dc, zc, &IdentityClientMock{}, ar)).Methods(http.MethodPut)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(GetMetadataExport(dc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(PutEditableMetadata(dc, zc, &IdentityClientMock{}, ar)).Methods(http.MethodPut)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata/import").HandlerFunc(ImportMetadata(dc, zc, ar)).Methods(http.MethodPost)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release").HandlerFunc(PutVersionRelease(dc, ar, rc)).Methods(http.MethodPut)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release").HandlerFunc(DeleteVersionRelease(dc, ar, rc)).Methods(http.MethodDelete)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/summary").HandlerFunc(GetVersionSummary(dc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}").HandlerFunc(GetDimension(dc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}").HandlerFunc(PutDimension(dc, zc, ar)).Methods(http.MethodPut)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}/options").HandlerFunc(GetDimensionOptions(dc)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files").HandlerFunc(GetVersionFiles(dc, fb)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files").HandlerFunc(PostVersionFile(dc, zc, ar, fb, 1024*1024)).Methods(http.MethodPost)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files/chunks").HandlerFunc(GetVersionFileChunk(cu)).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files/chunks").HandlerFunc(PostVersionFileChunk(dc, zc, ar, fb, cu, 1024*1024)).Methods(http.MethodPost)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/preview").HandlerFunc(GetPreview(dc, fb, &DownloadClientMock{})).Methods(http.MethodGet)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/state").HandlerFunc(PostVersionState(dc, zc, &IdentityClientMock{}, ar)).Methods(http.MethodPost)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection").HandlerFunc(PutVersionCollection(dc, zc, ar)).Methods(http.MethodPut)
+	router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection").HandlerFunc(DeleteVersionCollection(dc, zc, ar)).Methods(http.MethodDelete)
+
+	const versionURL = "/datasets/cpih01/editions/time-series/versions/2"
+	metadataBody := `{"dataset":{"id":"cpih01","title":"CPIH"},"version":{"id":"version-2","release_date":"2020-11-07T00:00:00.000Z"},"dimensions":[],"collection_id":"testcollection","collection_state":"InProgress","version_etag":"version-etag"}`
+
+	importBody := `{"title":"CPIH","keywords":["inflation","prices"],"alerts":[{"date":"2020-11-07T00:00:00Z","description":"Corrected weights","type":"correction"}],"dimensions":[{"name":"aggregate","label":"Special aggregate"}]}`
+
+	chunkQuery := "resumableIdentifier=10-cpihcsv&resumableFilename=cpih.csv&resumableCurrentChunkSize=5&resumableTotalSize=10&resumableTotalChunks=2"
+
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		status int
+	}{
+		{"get all datasets", http.MethodGet, "/datasets", "", http.StatusOK},
+		{"get topics", http.MethodGet, "/datasets/cpih01/create", "", http.StatusOK},
+		{"get audit history", http.MethodGet, "/datasets/cpih01/audit", "", http.StatusOK},
+		{"get editions", http.MethodGet, "/datasets/cpih01/editions", "", http.StatusOK},
+		{"get versions", http.MethodGet, "/datasets/cpih01/editions/time-series/versions", "", http.StatusOK},
+		{"get edit metadata", http.MethodGet, versionURL, "", http.StatusOK},
+		{"export metadata as csvw", http.MethodGet, versionURL + "/metadata?format=csvw", "", http.StatusOK},
+		{"export metadata as jsonld", http.MethodGet, versionURL + "/metadata?format=jsonld", "", http.StatusOK},
+		{"export metadata as dcat", http.MethodGet, versionURL + "/metadata?format=dcat", "", http.StatusOK},
+		{"put version release", http.MethodPut, versionURL + "/release", `{"release_id":"cpih-oct-2020"}`, http.StatusOK},
+		{"put version release that is not in the calendar", http.MethodPut, versionURL + "/release", `{"release_id":"cpih-nov-2020"}`, http.StatusBadRequest},
+		{"delete version release", http.MethodDelete, versionURL + "/release", "", http.StatusNoContent},
+		{"get version summary", http.MethodGet, versionURL + "/summary", "", http.StatusOK},
+		{"get dimension", http.MethodGet, versionURL + "/dimensions/aggregate", "", http.StatusOK},
+		{"get dimension options", http.MethodGet, versionURL + "/dimensions/aggregate/options?q=index&limit=10", "", http.StatusOK},
+		{"put dimension without an etag", http.MethodPut, versionURL + "/dimensions/aggregate", `{"label":"Special aggregate"}`, http.StatusPreconditionRequired},
+		{"put metadata", http.MethodPut, versionURL, metadataBody, http.StatusOK},
+		{"put editable metadata", http.MethodPut, versionURL + "/metadata", metadataBody, http.StatusOK},
+		{"preview metadata import", http.MethodPost, versionURL + "/metadata/import", importBody, http.StatusOK},
+		{"commit metadata import", http.MethodPost, versionURL + "/metadata/import?commit=true", importBody, http.StatusOK},
+		{"invalid metadata import", http.MethodPost, versionURL + "/metadata/import", `{"dimensions":[{"name":"geography"}]}`, http.StatusBadRequest},
+		{"list version files", http.MethodGet, versionURL + "/files", "", http.StatusOK},
+		{"post version file reference", http.MethodPost, versionURL + "/files", `{"path":"cpih01/time-series/2/cpih.csv","title":"CPIH csv"}`, http.StatusCreated},
+		{"get a received version file chunk", http.MethodGet, versionURL + "/files/chunks?" + chunkQuery + "&resumableChunkNumber=1", "", http.StatusOK},
+		{"get a missing version file chunk", http.MethodGet, versionURL + "/files/chunks?" + chunkQuery + "&resumableChunkNumber=2", "", http.StatusNoContent},
+		{"get preview", http.MethodGet, versionURL + "/preview?file=cpih01/time-series/2/cpih.csv&limit=5", "", http.StatusOK},
+		{"post version state", http.MethodPost, versionURL + "/state", `{"state":"approved"}`, http.StatusOK},
+		{"put version collection", http.MethodPut, versionURL + "/collection", `{"collection_id":"othercollection"}`, http.StatusOK},
+		{"delete version collection", http.MethodDelete, versionURL + "/collection", "", http.StatusNoContent},
+		{"invalid state", http.MethodPost, versionURL + "/state", `{"state":"published"}`, http.StatusConflict},
+	}
+
+	Convey("Given the handlers registered in routes.Init", t, func() {
+		for _, tc := range cases {
+			Convey("When a "+tc.name+" request is made", func() {
+				req := httptest.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", testAccessToken("reviewer@ons.gov.uk"))
+				if tc.body != "" {
+					req.Header.Set("Content-Type", "application/json")
+				}
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Convey("Then the response matches the OpenAPI spec", func() {
+					So(w.Code, ShouldEqual, tc.status)
+					So(validateContract(specRouter, req, tc.body, w), ShouldBeNil)
+				})
+			})
+		}
+
+		Convey("When a put dimension request is made with the version ETag", func() {
+			body := `{"label":"Special aggregate","description":"The goods and services in the basket","number_of_options":12}`
+			req := httptest.NewRequest(http.MethodPut, versionURL+"/dimensions/aggregate", bytes.NewBufferString(body))
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", testAccessToken("reviewer@ons.gov.uk"))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", "version-etag")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Convey("Then the response matches the OpenAPI spec", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(validateContract(specRouter, req, body, w), ShouldBeNil)
+			})
+		})
+
+		Convey("When a version file is uploaded as multipart form data", func() {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			So(mw.WriteField("title", "CPIH xlsx"), ShouldBeNil)
+			part, err := mw.CreateFormFile("file", "cpih.xlsx")
+			So(err, ShouldBeNil)
+			_, err = part.Write([]byte("xlsx content"))
+			So(err, ShouldBeNil)
+			So(mw.Close(), ShouldBeNil)
+
+			req := httptest.NewRequest(http.MethodPost, versionURL+"/files", bytes.NewReader(body.Bytes()))
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", testAccessToken("reviewer@ons.gov.uk"))
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Convey("Then the response matches the OpenAPI spec", func() {
+				So(w.Code, ShouldEqual, http.StatusCreated)
+				So(validateContract(specRouter, req, body.String(), w), ShouldBeNil)
+			})
+		})
+
+		for number, status := range map[string]int{"1": http.StatusOK, "2": http.StatusCreated} {
+			Convey("When chunk "+number+" of a version file is uploaded", func() {
+				var body bytes.Buffer
+				mw := multipart.NewWriter(&body)
+				for k, v := range map[string]string{
+					"resumableIdentifier":       "10-cpihcsv",
+					"resumableFilename":         "cpih.csv",
+					"resumableChunkNumber":      number,
+					"resumableCurrentChunkSize": "5",
+					"resumableTotalSize":        "10",
+					"resumableTotalChunks":      "2",
+				} {
+					So(mw.WriteField(k, v), ShouldBeNil)
+				}
+				part, err := mw.CreateFormFile("file", "blob")
+				So(err, ShouldBeNil)
+				_, err = part.Write([]byte("a,b\n1"))
+				So(err, ShouldBeNil)
+				So(mw.Close(), ShouldBeNil)
+
+				req := httptest.NewRequest(http.MethodPost, versionURL+"/files/chunks", bytes.NewReader(body.Bytes()))
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", testAccessToken("reviewer@ons.gov.uk"))
+				req.Header.Set("Content-Type", mw.FormDataContentType())
+
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Convey("Then the response matches the OpenAPI spec", func() {
+					So(w.Code, ShouldEqual, status)
+					So(validateContract(specRouter, req, body.String(), w), ShouldBeNil)
+				})
+			})
+		}
+
+		Convey("When a request without headers is made", func() {
+			req := httptest.NewRequest(http.MethodGet, "/datasets", http.NoBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Convey("Then the error response matches the OpenAPI spec", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				req.Header.Set("Collection-Id", "testcollection")
+				req.Header.Set("X-Florence-Token", "testuser")
+				So(validateContract(specRouter, req, "", w), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package dataset
+
+import (
+	"net/http"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// DeleteVersionCollection removes a version from the caller's collection, clearing its collection ID in the dataset
+// API and moving it back through the workflow to edition-confirmed, the state of a version that is not associated
+// with any collection
+func DeleteVersionCollection(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		deleteVersionCollection(w, r, dc, zc, ar, accessToken, collectionID)
+	})
+}
+
+func deleteVersionCollection(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+	v := l.Version
+	logInfo["from"] = v.State
+
+	if v.State == datasetApiModels.PublishedState {
+		log.Error(ctx, "version is published", errVersionPublished, log.Data(logInfo))
+		http.Error(w, errVersionPublished.Error(), http.StatusConflict)
+		return
+	}
+
+	// a version that is already edition-confirmed only needs to leave the zebedee collection
+	changeState := v.State != datasetApiModels.EditionConfirmedState
+	if changeState {
+		if err = workflow.CheckTransition(v.State, datasetApiModels.EditionConfirmedState); err != nil {
+			log.Error(ctx, "version state transition not allowed", err, log.Data(logInfo))
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	}
+
+	c, err := zc.GetCollection(ctx, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, "error getting collection", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+	state := versionCollectionState(c, datasetID, edition, version)
+
+	err = zc.DeleteDatasetVersionFromCollection(ctx, userAccessToken, collectionID, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "error removing version from collection", err, log.Data(logInfo))
+		http.Error(w, "error removing version from collection", http.StatusInternalServerError)
+		return
+	}
+
+	// the update leaves the collection ID empty to clear it, and moves the version back to edition-confirmed in
+	// the same write
+	update := datasetApiModels.Version{CollectionID: ""}
+	if changeState {
+		update.State = datasetApiModels.EditionConfirmedState
+	}
+	_, err = dc.PutVersion(ctx, headers, datasetID, edition, version, update)
+	if err != nil {
+		log.Error(ctx, "error updating version", err, log.Data(logInfo))
+
+		// put the version back in the collection so zebedee and the dataset API still agree
+		if rollbackErr := zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, state); rollbackErr != nil {
+			log.Error(ctx, "error adding version back to collection", rollbackErr, log.Data(logInfo))
+		}
+
+		http.Error(w, "error updating version", http.StatusInternalServerError)
+		return
+	}
+
+	event := newAuditEvent(ctx, "remove-collection", userAccessToken, collectionID, datasetID, edition, version)
+	event.Changes = []audit.Change{
+		{Field: "collection_id", From: collectionID, To: nil},
+		{Field: "collection_state", From: state, To: nil},
+	}
+	if changeState {
+		event.Changes = append(event.Changes, audit.Change{Field: "state", From: v.State, To: datasetApiModels.EditionConfirmedState})
+	}
+	ar.Record(ctx, event)
+
+	w.WriteHeader(http.StatusNoContent)
+
+	log.Info(ctx, "delete version collection: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,149 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDeleteVersionCollection(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection"
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("DELETE", "/datasets/test-dataset/editions/test-edition/versions/1/collection", http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test deleteVersionCollection", t, func() {
+		mockDatasetClient := &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID, CollectionID: "testcollection"}}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "associated", CollectionID: "testcollection"}, datasetApiSdk.ResponseHeaders{}, nil
+			},
+			PutVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error) {
+				return version, nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{
+					ID:              collectionID,
+					DatasetVersions: []zebedeeclient.CollectionItem{{ID: "test-dataset", Edition: "test-edition", Version: "1", State: "Complete"}},
+				}, nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+			DeleteDatasetVersionFromCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+				return nil
+			},
+		}
+
+		auditRecorder := newMockAuditRecorder()
+
+		Convey("on success", func() {
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(mockDatasetClient.GetVersionWithHeadersCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls()[0].CollectionID, ShouldEqual, "testcollection")
+			So(mockDatasetClient.PutVersionCalls(), ShouldHaveLength, 1)
+			So(mockDatasetClient.PutVersionCalls()[0].Version.CollectionID, ShouldBeEmpty)
+			So(mockDatasetClient.PutVersionCalls()[0].Version.State, ShouldEqual, "edition-confirmed")
+			So(auditRecorder.RecordCalls(), ShouldHaveLength, 1)
+			So(auditRecorder.RecordCalls()[0].E.Action, ShouldEqual, "remove-collection")
+			So(auditRecorder.RecordCalls()[0].E.Changes, ShouldResemble, []audit.Change{
+				{Field: "collection_id", From: "testcollection", To: nil},
+				{Field: "collection_state", From: "Complete", To: nil},
+				{Field: "state", From: "associated", To: "edition-confirmed"},
+			})
+		})
+
+		Convey("only clears the collection of an edition-confirmed version", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "edition-confirmed", CollectionID: "testcollection"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldHaveLength, 1)
+			So(mockDatasetClient.PutVersionCalls(), ShouldHaveLength, 1)
+			So(mockDatasetClient.PutVersionCalls()[0].Version.CollectionID, ShouldBeEmpty)
+			So(mockDatasetClient.PutVersionCalls()[0].Version.State, ShouldBeEmpty)
+			So(auditRecorder.RecordCalls()[0].E.Changes, ShouldResemble, []audit.Change{
+				{Field: "collection_id", From: "testcollection", To: nil},
+				{Field: "collection_state", From: "Complete", To: nil},
+			})
+		})
+
+		Convey("returns 409 when the workflow does not allow the version to go back to edition-confirmed", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "approved", CollectionID: "testcollection"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldBeEmpty)
+			So(mockDatasetClient.PutVersionCalls(), ShouldBeEmpty)
+			So(auditRecorder.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 when the version is published", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "published"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldBeEmpty)
+			So(mockDatasetClient.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 404 when the version does not exist", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{}, datasetApiSdk.ResponseHeaders{}, &testCliError{}
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("returns 500 when zebedee fails", func() {
+			mockZebedeeClient.DeleteDatasetVersionFromCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+				return errors.New("test zebedee error")
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(mockDatasetClient.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("adds the version back to the collection when the dataset API update fails", func() {
+			mockDatasetClient.PutVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{}, errors.New("test dataset API error")
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Complete")
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package dataset
+
+import (
+	"errors"
+	"net/http"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// DeleteVersionRelease removes the link of a version to an entry in the release calendar
+func DeleteVersionRelease(dc DatasetAPIClient, ar AuditRecorder, rc ReleaseCalendar) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		deleteVersionRelease(w, r, dc, ar, rc, accessToken, collectionID)
+	})
+}
+
+func deleteVersionRelease(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, ar AuditRecorder, rc ReleaseCalendar, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	if _, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version); !ok {
+		return
+	}
+
+	removed, err := rc.Unlink(ctx, datasetID, edition, version)
+	if errors.Is(err, releases.ErrNotLinked) {
+		log.Error(ctx, "deleteVersionRelease endpoint: version not linked", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error unlinking version from release", err, log.Data(logInfo))
+		http.Error(w, "error unlinking version from release", http.StatusInternalServerError)
+		return
+	}
+
+	event := newAuditEvent(ctx, "unlink-release", userAccessToken, collectionID, datasetID, edition, version)
+	event.Changes = []audit.Change{{Field: "release_id", From: removed.ReleaseID, To: nil}}
+	ar.Record(ctx, event)
+
+	w.WriteHeader(http.StatusNoContent)
+
+	log.Info(ctx, "delete version release: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,64 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDeleteVersionRelease(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release"
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, "/datasets/cpih01/editions/time-series/versions/2/release", http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test deleteVersionRelease", t, func() {
+		rc := &ReleaseCalendarMock{
+			UnlinkFunc: func(ctx context.Context, datasetID, edition, version string) (releases.Link, error) {
+				return releases.Link{ReleaseID: "cpih-jan-2020"}, nil
+			},
+		}
+		ar := newMockAuditRecorder()
+
+		Convey("on success", func() {
+			w := doTestRequest(target, newRequest(), DeleteVersionRelease(newVersionFilesDatasetClient(), ar, rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(rc.UnlinkCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "unlink-release")
+			So(ar.RecordCalls()[0].E.Changes[0].From, ShouldEqual, "cpih-jan-2020")
+		})
+
+		Convey("returns 404 when the version is not linked to a release", func() {
+			rc.UnlinkFunc = func(ctx context.Context, datasetID, edition, version string) (releases.Link, error) {
+				return releases.Link{}, releases.ErrNotLinked
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionRelease(newVersionFilesDatasetClient(), ar, rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 500 when the link cannot be removed", func() {
+			rc.UnlinkFunc = func(ctx context.Context, datasetID, edition, version string) (releases.Link, error) {
+				return releases.Link{}, errors.New("disk full")
+			}
+			w := doTestRequest(target, newRequest(), DeleteVersionRelease(newVersionFilesDatasetClient(), ar, rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
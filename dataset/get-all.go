@@ -6,14 +6,13 @@ import (
 
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
 
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
 // GetAll returns a mapped list of all datasets
 func GetAll(dc DatasetAPIClient, batchSize, maxWorkers int) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
 		getAll(w, r, dc, accessToken, collectionID, lang, batchSize, maxWorkers)
 	})
 }
@@ -21,7 +20,7 @@ func GetAll(dc DatasetAPIClient, batchSize, maxWorkers int) http.HandlerFunc {
 func getAll(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, userAccessToken, collectionID, lang string, batchSize, maxWorkers int) {
 	ctx := req.Context()
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -0,0 +1,75 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetAuditHistory returns the audit history of writes made to a dataset through the controller. The history names the
+// editor of each change, so it is only returned to callers that zebedee lets read the collection
+func GetAuditHistory(ar AuditRecorder, zc ZebedeeClient) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getAuditHistory(w, r, ar, zc, accessToken, collectionID)
+	})
+}
+
+func getAuditHistory(w http.ResponseWriter, req *http.Request, ar AuditRecorder, zc ZebedeeClient, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+	}
+
+	// only publishers can read collections, which also covers requests made when authorisation is disabled
+	if _, err = zc.GetCollection(ctx, userAccessToken, collectionID); err != nil {
+		status := http.StatusInternalServerError
+		var zebedeeErr zebedeeclient.ErrInvalidZebedeeResponse
+		if errors.As(err, &zebedeeErr) && (zebedeeErr.ActualCode == http.StatusUnauthorized || zebedeeErr.ActualCode == http.StatusForbidden) {
+			status = http.StatusForbidden
+		}
+		log.Error(ctx, "error getting collection", err, log.Data(logInfo))
+		http.Error(w, "error getting collection", status)
+		return
+	}
+
+	events, err := ar.History(ctx, datasetID)
+	if err != nil {
+		log.Error(ctx, "error getting audit history", err, log.Data(logInfo))
+		http.Error(w, "error getting audit history", http.StatusInternalServerError)
+		return
+	}
+
+	b, err := json.Marshal(AuditHistory{
+		DatasetID: datasetID,
+		Events:    events,
+	})
+	if err != nil {
+		log.Error(ctx, "error marshalling response to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling response to json", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(b)
+	if err != nil {
+		log.Error(ctx, "error writing response", err)
+		http.Error(w, "error writing response", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info(ctx, "get audit history: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,274 @@
+package dataset
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/links"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultDimensionOptionsLimit = 20
+	maxDimensionOptionsLimit     = 1000
+	// dimensionOptionsBatchSize is the largest page the dataset API will return
+	dimensionOptionsBatchSize = 1000
+)
+
+// publishedOptionsCacheSize is the number of dimensions whose published options are cached
+const publishedOptionsCacheSize = 50
+
+// publishedOptionsCache holds the options of dimensions of published versions, which never change once published, so
+// comparing every page of an unpublished version does not page through the published version again. It holds the
+// options of at most size dimensions, dropping the least recently used when it is full.
+type publishedOptionsCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type publishedOptionsEntry struct {
+	key     string
+	options []datasetApiModels.PublicDimensionOption
+}
+
+func newPublishedOptionsCache(size int) *publishedOptionsCache {
+	return &publishedOptionsCache{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *publishedOptionsCache) get(key string) ([]datasetApiModels.PublicDimensionOption, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*publishedOptionsEntry).options, true
+}
+
+func (c *publishedOptionsCache) set(key string, options []datasetApiModels.PublicDimensionOption) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*publishedOptionsEntry).options = options
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&publishedOptionsEntry{key: key, options: options})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*publishedOptionsEntry).key)
+	}
+}
+
+// GetDimensionOptions is a handler that wraps getDimensionOptions passing in addition arguments
+func GetDimensionOptions(dc DatasetAPIClient) http.HandlerFunc {
+	cache := newPublishedOptionsCache(publishedOptionsCacheSize)
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getDimensionOptions(w, r, dc, cache, accessToken, collectionID)
+	})
+}
+
+// getDimensionOptions returns a page of the options of a dimension of a version, optionally filtered by the q query
+// parameter. The options are compared with the same dimension of the latest published version of the dataset, so an
+// editor can see which options are new and which have been removed. Only a search or a comparison needs every option,
+// otherwise the page is read straight from the dataset API
+func getDimensionOptions(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, cache *publishedOptionsCache, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	dimension := vars["dimension"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"dimension":    dimension,
+		"collectionID": collectionID,
+	}
+
+	offset, limit, err := pagination(req)
+	if err != nil {
+		log.Error(ctx, "getDimensionOptions endpoint: invalid pagination", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	log.Info(ctx, "calling get dimension options", log.Data(logInfo))
+
+	v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	i := findDimension(v.Dimensions, dimension)
+	if i < 0 {
+		log.Error(ctx, "dimension not found", nil, log.Data(logInfo))
+		http.Error(w, "dimension not found", http.StatusNotFound)
+		return
+	}
+	dim := v.Dimensions[i]
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	var published *[]datasetApiModels.PublicDimensionOption
+	comparedWith := ""
+	if d.Current != nil && d.Current.Links != nil && d.Current.Links.LatestVersion != nil {
+		latest, err := links.ParseVersion(d.Current.Links.LatestVersion.HRef)
+		if err != nil {
+			// without the published version the options are still shown, just without the new and removed flags
+			log.Warn(ctx, "failed to parse latest published version link", log.Data{"error": err.Error(), "datasetID": datasetID})
+		} else if latest.EditionID != edition || latest.VersionID != version {
+			publishedOptions, err := getPublishedDimensionOptions(ctx, dc, cache, headers, latest, dim.Name)
+			if err != nil {
+				log.Error(ctx, "failed Get latest published version dimension options", err, log.Data(logInfo))
+				setErrorStatusCode(req, w, err, datasetID)
+				return
+			}
+			published = &publishedOptions
+			comparedWith = d.Current.Links.LatestVersion.HRef
+		}
+	}
+
+	q := req.URL.Query().Get("q")
+	var page model.DimensionOptions
+	if published == nil && strings.TrimSpace(q) == "" && dim.NumberOfOptions != nil {
+		options, err := dc.GetVersionDimensionOptions(ctx, headers, datasetID, edition, version, dim.Name, &datasetApiSdk.QueryParams{Offset: offset, Limit: limit})
+		if err != nil {
+			log.Error(ctx, "failed Get dimension options", err, log.Data(logInfo))
+			setErrorStatusCode(req, w, err, datasetID)
+			return
+		}
+		page = mapper.DimensionOptions(dim, options.Items, nil, "", 0, limit)
+		page.Offset = offset
+		page.TotalCount = *dim.NumberOfOptions
+	} else {
+		options, err := getAllDimensionOptions(ctx, dc, headers, datasetID, edition, version, dim.Name)
+		if err != nil {
+			log.Error(ctx, "failed Get dimension options", err, log.Data(logInfo))
+			setErrorStatusCode(req, w, err, datasetID)
+			return
+		}
+		page = mapper.DimensionOptions(dim, options, published, q, offset, limit)
+	}
+	page.ComparedWith = comparedWith
+
+	b, err := json.Marshal(page)
+	if err != nil {
+		log.Error(ctx, "error marshalling dimension options to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling dimension options to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(b)
+	if err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get dimension options: request successful", log.Data(logInfo))
+}
+
+// getPublishedDimensionOptions returns every option of a dimension of a published version, reading them from the cache
+// when they have been read before. A dimension that is not in the published version has no options
+func getPublishedDimensionOptions(ctx context.Context, dc DatasetAPIClient, cache *publishedOptionsCache, headers datasetApiSdk.Headers, v links.Resource, dimension string) ([]datasetApiModels.PublicDimensionOption, error) {
+	key := v.DatasetID + "/" + v.EditionID + "/" + v.VersionID + "/" + dimension
+	if options, ok := cache.get(key); ok {
+		return options, nil
+	}
+
+	options, err := getAllDimensionOptions(ctx, dc, headers, v.DatasetID, v.EditionID, v.VersionID, dimension)
+	var clientErr ClientError
+	switch {
+	case errors.As(err, &clientErr) && clientErr.Code() == http.StatusNotFound:
+		// the dimension is new in this version, so all of its options are new
+		options = []datasetApiModels.PublicDimensionOption{}
+	case err != nil:
+		return nil, err
+	}
+
+	cache.set(key, options)
+	return options, nil
+}
+
+// getAllDimensionOptions pages through every option of a dimension, because the dataset API cannot search them
+func getAllDimensionOptions(ctx context.Context, dc DatasetAPIClient, headers datasetApiSdk.Headers, datasetID, edition, version, dimension string) ([]datasetApiModels.PublicDimensionOption, error) {
+	options := []datasetApiModels.PublicDimensionOption{}
+	for offset := 0; ; offset += dimensionOptionsBatchSize {
+		q := &datasetApiSdk.QueryParams{Limit: dimensionOptionsBatchSize, Offset: offset}
+		page, err := dc.GetVersionDimensionOptions(ctx, headers, datasetID, edition, version, dimension, q)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, page.Items...)
+		if len(page.Items) < dimensionOptionsBatchSize {
+			return options, nil
+		}
+	}
+}
+
+// pagination reads the offset and limit query parameters, defaulting the limit when it is not given
+func pagination(req *http.Request) (offset, limit int, err error) {
+	query := req.URL.Query()
+
+	limit = defaultDimensionOptionsLimit
+	if l := query.Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxDimensionOptionsLimit {
+			return 0, 0, errors.New("limit must be a number from 1 to " + strconv.Itoa(maxDimensionOptionsLimit))
+		}
+	}
+
+	if o := query.Get("offset"); o != "" {
+		offset, err = strconv.Atoi(o)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a number that is not negative")
+		}
+	}
+
+	return offset, limit, nil
+}
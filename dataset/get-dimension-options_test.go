@@ -0,0 +1,272 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetDimensionOptions(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}/options"
+	const url = "/datasets/cpih01/editions/time-series/versions/2/dimensions/aggregate/options"
+
+	option := func(code, label string) datasetApiModels.PublicDimensionOption {
+		return datasetApiModels.PublicDimensionOption{Name: "aggregate", Option: code, Label: label}
+	}
+
+	newDatasetClient := func() *DatasetAPIClientMock {
+		return &DatasetAPIClientMock{
+			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Dimensions: []datasetApiModels.Dimension{{ID: "aggregate", Name: "aggregate"}}}, nil
+			},
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID: datasetID,
+					Current: &datasetApiModels.Dataset{
+						ID:    datasetID,
+						Links: &datasetApiModels.DatasetLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "http://localhost:22000/datasets/cpih01/editions/time-series/versions/1"}},
+					},
+				}, nil
+			},
+			GetVersionDimensionOptionsFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+				if versionID == "1" {
+					return datasetApiSdk.VersionDimensionOptionsList{Items: []datasetApiModels.PublicDimensionOption{
+						option("cpih1dim1A0", "Overall Index"),
+						option("cpih1dim1G10100", "Food"),
+					}}, nil
+				}
+				return datasetApiSdk.VersionDimensionOptionsList{Items: []datasetApiModels.PublicDimensionOption{
+					option("cpih1dim1A0", "Overall Index"),
+					option("cpih1dim1G20100", "Alcoholic beverages"),
+				}}, nil
+			},
+		}
+	}
+
+	serveHandler := func(handler http.HandlerFunc, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url+query, http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		router := mux.NewRouter()
+		router.Path(target).HandlerFunc(handler).Methods(http.MethodGet)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	serve := func(dc DatasetAPIClient, query string) *httptest.ResponseRecorder {
+		return serveHandler(GetDimensionOptions(dc), query)
+	}
+
+	Convey("test getDimensionOptions", t, func() {
+		Convey("returns the options flagged against the latest published version", func() {
+			dc := newDatasetClient()
+			w := serve(dc, "")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var page model.DimensionOptions
+			So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+			So(page.ComparedWith, ShouldEqual, "http://localhost:22000/datasets/cpih01/editions/time-series/versions/1")
+			So(page.Limit, ShouldEqual, 20)
+			So(page.TotalCount, ShouldEqual, 3)
+			So(page.Items[0], ShouldResemble, model.DimensionOption{Code: "cpih1dim1A0", Label: "Overall Index"})
+			So(page.Items[1].New, ShouldBeTrue)
+			So(page.Items[2].Code, ShouldEqual, "cpih1dim1G10100")
+			So(page.Items[2].Removed, ShouldBeTrue)
+
+			calls := dc.GetVersionDimensionOptionsCalls()
+			So(calls, ShouldHaveLength, 2)
+			So(calls[0].VersionID, ShouldEqual, "1")
+			So(calls[1].VersionID, ShouldEqual, "2")
+			So(calls[1].Q, ShouldResemble, &datasetApiSdk.QueryParams{Limit: 1000})
+		})
+
+		Convey("reads the published options once for every page", func() {
+			dc := newDatasetClient()
+			handler := GetDimensionOptions(dc)
+
+			So(serveHandler(handler, "").Code, ShouldEqual, http.StatusOK)
+			So(serveHandler(handler, "?offset=1").Code, ShouldEqual, http.StatusOK)
+
+			calls := dc.GetVersionDimensionOptionsCalls()
+			So(calls, ShouldHaveLength, 3)
+			So(calls[0].VersionID, ShouldEqual, "1")
+			So(calls[1].VersionID, ShouldEqual, "2")
+			So(calls[2].VersionID, ShouldEqual, "2")
+		})
+
+		Convey("passes the page to the dataset api if there is no search or comparison", func() {
+			dc := newDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				count := 42
+				return datasetApiModels.Version{Dimensions: []datasetApiModels.Dimension{{ID: "aggregate", Name: "aggregate", NumberOfOptions: &count}}}, nil
+			}
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID}, nil
+			}
+
+			w := serve(dc, "?offset=40&limit=5")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			calls := dc.GetVersionDimensionOptionsCalls()
+			So(calls, ShouldHaveLength, 1)
+			So(calls[0].Q, ShouldResemble, &datasetApiSdk.QueryParams{Offset: 40, Limit: 5})
+
+			var page model.DimensionOptions
+			So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+			So(page.Count, ShouldEqual, 2)
+			So(page.Offset, ShouldEqual, 40)
+			So(page.Limit, ShouldEqual, 5)
+			So(page.TotalCount, ShouldEqual, 42)
+		})
+
+		Convey("searches and pages the options", func() {
+			w := serve(newDatasetClient(), "?q=FOOD&offset=0&limit=1")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var page model.DimensionOptions
+			So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+			So(page.TotalCount, ShouldEqual, 1)
+			So(page.Items[0].Label, ShouldEqual, "Food")
+		})
+
+		Convey("reads every page of options from the dataset api", func() {
+			dc := newDatasetClient()
+			dc.GetVersionDimensionOptionsFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+				if q.Offset > 0 {
+					return datasetApiSdk.VersionDimensionOptionsList{Items: []datasetApiModels.PublicDimensionOption{option("last", "Last")}}, nil
+				}
+				return datasetApiSdk.VersionDimensionOptionsList{Items: make([]datasetApiModels.PublicDimensionOption, 1000)}, nil
+			}
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID}, nil
+			}
+
+			w := serve(dc, "?q=last")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(dc.GetVersionDimensionOptionsCalls(), ShouldHaveLength, 2)
+			So(dc.GetVersionDimensionOptionsCalls()[1].Q.Offset, ShouldEqual, 1000)
+
+			var page model.DimensionOptions
+			So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+			So(page.ComparedWith, ShouldBeEmpty)
+			So(page.TotalCount, ShouldEqual, 1)
+		})
+
+		Convey("does not compare the options if the version is the latest published version", func() {
+			dc := newDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID: datasetID,
+					Current: &datasetApiModels.Dataset{
+						Links: &datasetApiModels.DatasetLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "/datasets/cpih01/editions/time-series/versions/2"}},
+					},
+				}, nil
+			}
+
+			w := serve(dc, "")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(dc.GetVersionDimensionOptionsCalls(), ShouldHaveLength, 1)
+		})
+
+		Convey("flags every option as new if the published version does not have the dimension", func() {
+			dc := newDatasetClient()
+			options := dc.GetVersionDimensionOptionsFunc
+			dc.GetVersionDimensionOptionsFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+				if versionID == "1" {
+					return datasetApiSdk.VersionDimensionOptionsList{}, &testCliError{}
+				}
+				return options(ctx, headers, datasetID, editionID, versionID, dimensionID, q)
+			}
+
+			w := serve(dc, "")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var page model.DimensionOptions
+			So(json.Unmarshal(w.Body.Bytes(), &page), ShouldBeNil)
+			So(page.TotalCount, ShouldEqual, 2)
+			So(page.Items[0].New, ShouldBeTrue)
+			So(page.Items[1].New, ShouldBeTrue)
+		})
+
+		Convey("returns 404 if the version does not have the dimension", func() {
+			dc := newDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{}, nil
+			}
+
+			w := serve(dc, "")
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(dc.GetVersionDimensionOptionsCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 for an invalid limit or offset", func() {
+			So(serve(newDatasetClient(), "?limit=0").Code, ShouldEqual, http.StatusBadRequest)
+			So(serve(newDatasetClient(), "?limit=1001").Code, ShouldEqual, http.StatusBadRequest)
+			So(serve(newDatasetClient(), "?offset=-1").Code, ShouldEqual, http.StatusBadRequest)
+			So(serve(newDatasetClient(), "?offset=first").Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("returns 500 if the options cannot be read", func() {
+			dc := newDatasetClient()
+			dc.GetVersionDimensionOptionsFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+				return datasetApiSdk.VersionDimensionOptionsList{}, errors.New("dataset api error")
+			}
+
+			w := serve(dc, "")
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
+
+func TestUnitPublishedOptionsCache(t *testing.T) {
+	t.Parallel()
+
+	Convey("test publishedOptionsCache", t, func() {
+		cache := newPublishedOptionsCache(2)
+		options := []datasetApiModels.PublicDimensionOption{{Name: "aggregate", Option: "cpih1dim1A0"}}
+
+		cache.set("cpih01/time-series/1/aggregate", options)
+		cache.set("cpih01/time-series/1/time", nil)
+
+		Convey("returns the options that were set", func() {
+			got, ok := cache.get("cpih01/time-series/1/aggregate")
+			So(ok, ShouldBeTrue)
+			So(got, ShouldResemble, options)
+		})
+
+		Convey("drops the least recently used dimension when it is full", func() {
+			_, ok := cache.get("cpih01/time-series/1/aggregate")
+			So(ok, ShouldBeTrue)
+
+			cache.set("cpih01/time-series/1/geography", nil)
+
+			_, ok = cache.get("cpih01/time-series/1/time")
+			So(ok, ShouldBeFalse)
+			_, ok = cache.get("cpih01/time-series/1/aggregate")
+			So(ok, ShouldBeTrue)
+			_, ok = cache.get("cpih01/time-series/1/geography")
+			So(ok, ShouldBeTrue)
+		})
+	})
+}
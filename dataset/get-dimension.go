@@ -0,0 +1,96 @@
+package dataset
+
+import (
+	"encoding/json"
+	"net/http"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetDimension is a handler that wraps getDimension passing in addition arguments
+func GetDimension(dc DatasetAPIClient) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getDimension(w, r, dc, accessToken, collectionID)
+	})
+}
+
+// getDimension returns a single dimension of a version, with the instance ETag that must be sent back to edit it
+func getDimension(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	dimension := vars["dimension"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"dimension":    dimension,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	log.Info(ctx, "calling get dimension", log.Data(logInfo))
+
+	v, versionHeaders, err := dc.GetVersionWithHeaders(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	i := findDimension(v.Dimensions, dimension)
+	if i < 0 {
+		log.Error(ctx, "dimension not found", nil, log.Data(logInfo))
+		http.Error(w, "dimension not found", http.StatusNotFound)
+		return
+	}
+
+	writeDimension(w, req, v.Dimensions[i], versionHeaders.ETag, logInfo)
+
+	log.Info(ctx, "get dimension: request successful", log.Data(logInfo))
+}
+
+// findDimension returns the index of the dimension with the given name or id, or -1 if the version does not have it
+func findDimension(dimensions []datasetApiModels.Dimension, dimension string) int {
+	for i, dim := range dimensions {
+		if dim.Name == dimension || (dim.ID != "" && dim.ID == dimension) {
+			return i
+		}
+	}
+	return -1
+}
+
+func writeDimension(w http.ResponseWriter, req *http.Request, dim datasetApiModels.Dimension, eTag string, logInfo map[string]interface{}) {
+	b, err := json.Marshal(dim)
+	if err != nil {
+		log.Error(req.Context(), "error marshalling dimension to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling dimension to json", http.StatusInternalServerError)
+		return
+	}
+
+	if eTag != "" {
+		w.Header().Set("ETag", eTag)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(req.Context(), "error writing response", err, log.Data(logInfo))
+	}
+}
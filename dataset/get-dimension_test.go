@@ -0,0 +1,100 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const dimensionTarget = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}"
+
+func newDimensionDatasetClient() *DatasetAPIClientMock {
+	numberOfOptions := 12
+	return &DatasetAPIClientMock{
+		GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+			return datasetApiModels.DatasetUpdate{ID: datasetID, Next: &datasetApiModels.Dataset{ID: datasetID}}, nil
+		},
+		GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+			return datasetApiModels.Version{CollectionID: "testcollection"}, nil
+		},
+		GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+			return datasetApiModels.Version{
+				ID:           "instance-1",
+				CollectionID: "testcollection",
+				Dimensions: []datasetApiModels.Dimension{
+					{ID: "aggregate", Name: "aggregate", Label: "Aggregate", NumberOfOptions: &numberOfOptions},
+					{Name: "time", Label: "Time"},
+				},
+			}, datasetApiSdk.ResponseHeaders{ETag: "instance-etag"}, nil
+		},
+		PutInstanceFunc: func(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (string, error) {
+			return "new-instance-etag", nil
+		},
+	}
+}
+
+func TestUnitGetDimension(t *testing.T) {
+	t.Parallel()
+
+	serve := func(dc DatasetAPIClient, dimension string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/datasets/cpih01/editions/time-series/versions/2/dimensions/"+dimension, http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		router := mux.NewRouter()
+		router.Path(dimensionTarget).HandlerFunc(GetDimension(dc)).Methods(http.MethodGet)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Convey("test getDimension", t, func() {
+		Convey("returns the dimension with the instance ETag", func() {
+			w := serve(newDimensionDatasetClient(), "aggregate")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("ETag"), ShouldEqual, "instance-etag")
+
+			var dim datasetApiModels.Dimension
+			So(json.Unmarshal(w.Body.Bytes(), &dim), ShouldBeNil)
+			So(dim.Label, ShouldEqual, "Aggregate")
+			So(*dim.NumberOfOptions, ShouldEqual, 12)
+		})
+
+		Convey("finds a dimension without an id by its name", func() {
+			w := serve(newDimensionDatasetClient(), "time")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var dim datasetApiModels.Dimension
+			So(json.Unmarshal(w.Body.Bytes(), &dim), ShouldBeNil)
+			So(dim.Label, ShouldEqual, "Time")
+		})
+
+		Convey("returns 404 if the version does not have the dimension", func() {
+			w := serve(newDimensionDatasetClient(), "geography")
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(w.Body.String(), ShouldEqual, "dimension not found\n")
+		})
+
+		Convey("returns 500 if the version cannot be read", func() {
+			dc := newDimensionDatasetClient()
+			dc.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{}, datasetApiSdk.ResponseHeaders{}, errors.New("dataset api error")
+			}
+
+			w := serve(dc, "aggregate")
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
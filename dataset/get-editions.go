@@ -6,7 +6,7 @@ import (
 	"net/http"
 
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/links"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
@@ -14,7 +14,7 @@ import (
 
 // GetEditions returns a mapped list of all editions
 func GetEditions(dc DatasetAPIClient) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
 		getEditions(w, r, dc, accessToken, collectionID, lang)
 	})
 }
@@ -25,7 +25,7 @@ func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 	vars := mux.Vars(req)
 	datasetID := vars["datasetID"]
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -62,13 +62,19 @@ func getEditions(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 
 	latestVersionInEdition := make(map[string]string)
 	for e := range editions.Items {
-		_, _, versionID, err := getIDsFromURL(editions.Items[e].Links.LatestVersion.HRef)
+		if editions.Items[e].Links == nil || editions.Items[e].Links.LatestVersion == nil {
+			latestVersionInEdition[editions.Items[e].Edition] = ""
+			continue
+		}
+
+		latestVersion, err := links.ParseVersion(editions.Items[e].Links.LatestVersion.HRef)
 		if err != nil {
+			log.Warn(ctx, "failed to parse latest version link", log.FormatErrors([]error{err}), log.Data(logInfo))
 			latestVersionInEdition[editions.Items[e].Edition] = ""
 			continue
 		}
 
-		version, err := dc.GetVersion(ctx, headers, datasetID, editions.Items[e].Edition, versionID)
+		version, err := dc.GetVersion(ctx, headers, datasetID, editions.Items[e].Edition, latestVersion.VersionID)
 		if err != nil {
 			latestVersionInEdition[editions.Items[e].Edition] = ""
 			continue
@@ -10,6 +10,7 @@ import (
 
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
 	"github.com/gorilla/mux"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -39,7 +40,7 @@ func TestUnitGetEditions(t *testing.T) {
 		ReleaseDate: "2020-11-07T00:00:00.000Z",
 	}
 
-	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"editions\":[{\"id\":\"edition-1\",\"title\":\"edition-1\",\"release_date\":\"\"},{\"id\":\"edition-2\",\"title\":\"edition-2\",\"release_date\":\"\"}]}"
+	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"editions\":[{\"id\":\"edition-1\",\"title\":\"edition-1\",\"release_date\":\"07 November 2020\"},{\"id\":\"edition-2\",\"title\":\"edition-2\",\"release_date\":\"07 November 2020\"}]}"
 
 	Convey("test getAllEditions", t, func() {
 		mockDatasetClient := &DatasetAPIClientMock{
@@ -76,6 +77,38 @@ func TestUnitGetEditions(t *testing.T) {
 			})
 		})
 
+		Convey("leaves the release date empty when the latest version link cannot be parsed", func() {
+			mockDatasetClient.GetEditionsFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.EditionsList, error) {
+				return datasetApiSdk.EditionsList{Items: []datasetApiModels.Edition{{Edition: "edition-1", Links: &datasetApiModels.EditionUpdateLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "/not/a/version/link"}}}}}, nil
+			}
+
+			reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
+			req := httptest.NewRequest("GET", reqURL, http.NoBody)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Body.String(), ShouldEqual, "{\"dataset_name\":\"Test title\",\"editions\":[{\"id\":\"edition-1\",\"title\":\"edition-1\",\"release_date\":\"\"}]}")
+			So(mockDatasetClient.GetVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("a service can read editions without a collection ID", func() {
+			reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
+			req := httptest.NewRequest("GET", reqURL, http.NoBody)
+			req = req.WithContext(authorisation.WithServiceCaller(req.Context(), authorisation.ServiceCaller{Name: "bulk-metadata-fix", Token: "service-token"}))
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetEditions(mockDatasetClient))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(mockDatasetClient.GetEditionsCalls()[0].Headers, ShouldResemble, datasetApiSdk.Headers{AccessToken: "service-token"})
+		})
+
 		Convey("errors if no headers are passed", func() {
 			Convey("collection id not set", func() {
 				reqURL := fmt.Sprintf("/datasets/%v/editions", datasetID)
@@ -0,0 +1,119 @@
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	csvwContentType   = "application/csvm+json"
+	jsonLDContentType = "application/ld+json"
+)
+
+// GetMetadataExport is a handler that wraps getMetadataExport passing in addition arguments
+func GetMetadataExport(dc DatasetAPIClient) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getMetadataExport(w, r, dc, accessToken, collectionID)
+	})
+}
+
+// getMetadataExport returns the metadata of a version as a CSV on the Web, schema.org JSON-LD or DCAT document,
+// so that editors can check the machine readable metadata that will be published with it
+func getMetadataExport(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	format := req.URL.Query().Get("format")
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+		"format":       format,
+	}
+
+	if format != model.ExportFormatCSVW && format != model.ExportFormatJSONLD && format != model.ExportFormatDCAT {
+		err = fmt.Errorf("format must be one of %s, %s or %s", model.ExportFormatCSVW, model.ExportFormatJSONLD, model.ExportFormatDCAT)
+		log.Error(ctx, "invalid export format", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	log.Info(ctx, "calling get metadata export", log.Data(logInfo))
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	// the export previews the metadata being edited, so the next document is used if there is one
+	ds := d.Current
+	if d.Next != nil {
+		ds = d.Next
+	}
+	if ds == nil {
+		log.Error(ctx, "dataset has no current or next document", nil, log.Data(logInfo))
+		http.Error(w, "dataset has no metadata", http.StatusNotFound)
+		return
+	}
+
+	v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	var doc interface{}
+	contentType := jsonLDContentType
+	switch format {
+	case model.ExportFormatCSVW:
+		doc = mapper.CSVW(*ds, v)
+		contentType = csvwContentType
+	case model.ExportFormatJSONLD:
+		doc = mapper.JSONLD(*ds, v)
+	case model.ExportFormatDCAT:
+		doc = mapper.DCAT(*ds, v)
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		log.Error(ctx, "error marshalling metadata export to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling metadata export to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, err = w.Write(b)
+	if err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get metadata export: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,143 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetMetadataExport(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata"
+	const url = "/datasets/cpih01/editions/time-series/versions/2/metadata"
+
+	newDatasetClient := func() *DatasetAPIClientMock {
+		return &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID:      datasetID,
+					Current: &datasetApiModels.Dataset{ID: datasetID, Title: "published title"},
+					Next:    &datasetApiModels.Dataset{ID: datasetID, Title: "edited title"},
+				}, nil
+			},
+			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{
+					Version:    2,
+					Dimensions: []datasetApiModels.Dimension{{ID: "time", Label: "Time"}},
+					Downloads:  &datasetApiModels.DownloadList{CSV: &datasetApiModels.DownloadObject{HRef: "http://localhost:23600/cpih01.csv"}},
+				}, nil
+			},
+		}
+	}
+
+	serve := func(dc DatasetAPIClient, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url+query, http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		router := mux.NewRouter()
+		router.Path(target).HandlerFunc(GetMetadataExport(dc)).Methods(http.MethodGet)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Convey("test getMetadataExport", t, func() {
+		Convey("exports the edited metadata as CSV on the Web", func() {
+			dc := newDatasetClient()
+			w := serve(dc, "?format=csvw")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/csvm+json")
+
+			var m model.CSVWMetadata
+			So(json.Unmarshal(w.Body.Bytes(), &m), ShouldBeNil)
+			So(m.Title, ShouldEqual, "edited title")
+			So(m.URL, ShouldEqual, "http://localhost:23600/cpih01.csv")
+			So(m.TableSchema.Columns, ShouldHaveLength, 3)
+
+			So(dc.GetVersionCalls()[0].Headers, ShouldResemble, datasetApiSdk.Headers{CollectionID: "testcollection", AccessToken: "testuser"})
+		})
+
+		Convey("exports the metadata as schema.org JSON-LD", func() {
+			w := serve(newDatasetClient(), "?format=jsonld")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/ld+json")
+
+			var m model.SchemaDataset
+			So(json.Unmarshal(w.Body.Bytes(), &m), ShouldBeNil)
+			So(m.Type, ShouldEqual, "Dataset")
+			So(m.Name, ShouldEqual, "edited title")
+		})
+
+		Convey("exports the metadata as DCAT", func() {
+			w := serve(newDatasetClient(), "?format=dcat")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/ld+json")
+
+			var m model.DCATDataset
+			So(json.Unmarshal(w.Body.Bytes(), &m), ShouldBeNil)
+			So(m.Type, ShouldEqual, "dcat:Dataset")
+			So(m.Distribution, ShouldHaveLength, 1)
+		})
+
+		Convey("uses the current document if the dataset has no next document", func() {
+			dc := newDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID, Current: &datasetApiModels.Dataset{Title: "published title"}}, nil
+			}
+
+			w := serve(dc, "?format=jsonld")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			var m model.SchemaDataset
+			So(json.Unmarshal(w.Body.Bytes(), &m), ShouldBeNil)
+			So(m.Name, ShouldEqual, "published title")
+		})
+
+		Convey("returns 400 if the format is missing or unknown", func() {
+			for _, query := range []string{"", "?format=xml"} {
+				dc := newDatasetClient()
+				w := serve(dc, query)
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(w.Body.String(), ShouldEqual, "format must be one of csvw, jsonld or dcat\n")
+				So(dc.GetVersionCalls(), ShouldBeEmpty)
+			}
+		})
+
+		Convey("returns 404 if the dataset has no documents", func() {
+			dc := newDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID}, nil
+			}
+
+			w := serve(dc, "?format=dcat")
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("returns 500 if the version cannot be read", func() {
+			dc := newDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{}, errors.New("dataset api error")
+			}
+
+			w := serve(dc, "?format=csvw")
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
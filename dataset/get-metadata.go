@@ -5,14 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
-	"net/url"
-	"strings"
 
 	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/links"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
 )
@@ -26,17 +26,18 @@ type ClientError interface {
 const editionConfirmedState = "edition-confirmed"
 
 // GetEditMetadataHandler is a handler that wraps getEditMetadataHandler passing in addition arguments
-func GetMetadataHandler(dc DatasetAPIClient, zc ZebedeeClient) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		getEditMetadataHandler(w, r, dc, zc, accessToken, collectionID, lang)
+func GetMetadataHandler(dc DatasetAPIClient, zc ZebedeeClient, rc ReleaseCalendar) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getEditMetadataHandler(w, r, dc, zc, rc, accessToken, collectionID, lang)
 	})
 }
 
-// getEditMetadataHandler gets the Edit Metadata page information used on the edit metadata screens
-func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, userAccessToken, collectionID, lang string) {
+// getEditMetadataHandler gets the Edit Metadata page information used on the edit metadata screens, with the release
+// calendar entry the version is linked to
+func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, rc ReleaseCalendar, userAccessToken, collectionID, lang string) {
 	ctx := req.Context()
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -93,6 +94,7 @@ func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc Dataset
 
 	editMetadata := mapper.EditMetadata(d.Next, v, dims, c)
 	editMetadata.VersionEtag = sdkheaders.ETag
+	editMetadata.Release = versionRelease(ctx, rc, userAccessToken, datasetID, edition, version, v)
 
 	b, err := json.Marshal(editMetadata)
 	if err != nil {
@@ -110,6 +112,30 @@ func getEditMetadataHandler(w http.ResponseWriter, req *http.Request, dc Dataset
 	}
 }
 
+// versionRelease returns the release a version is linked to, or nil if it is not linked. The release calendar does
+// not stop the metadata being shown, so a release that cannot be read is returned with only its ID and a warning
+func versionRelease(ctx context.Context, rc ReleaseCalendar, userAccessToken, datasetID, edition, version string, v datasetApiModels.Version) *model.VersionRelease {
+	logData := log.Data{"datasetID": datasetID, "edition": edition, "version": version}
+
+	l, r, err := rc.Linked(ctx, userAccessToken, datasetID, edition, version)
+	switch {
+	case errors.Is(err, releases.ErrNotLinked):
+		return nil
+	case l.ReleaseID == "":
+		log.Error(ctx, "failed to read release link", err, logData)
+		return nil
+	case errors.Is(err, releases.ErrNotFound):
+		log.Warn(ctx, "linked release is not in the release calendar", log.Data{"datasetID": datasetID, "releaseID": l.ReleaseID})
+		return &model.VersionRelease{ID: l.ReleaseID, Warning: "the release is no longer in the release calendar"}
+	case err != nil:
+		log.Error(ctx, "failed to read release from release calendar", err, logData)
+		return &model.VersionRelease{ID: l.ReleaseID, Warning: "the release could not be read from the release calendar"}
+	}
+
+	vr := mapper.VersionRelease(v, r)
+	return &vr
+}
+
 func getCollectionDetails(ctx context.Context, zc ZebedeeClient, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
 	if collectionID != "" {
 		c, err := zc.GetCollection(ctx, userAccessToken, collectionID)
@@ -123,38 +149,22 @@ func getCollectionDetails(ctx context.Context, zc ZebedeeClient, userAccessToken
 }
 
 func getLatestPublishedVersionDimensions(ctx context.Context, w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, headers datasetApiSdk.Headers, latestVersionURL string) []datasetApiModels.Dimension {
-	datasetID, editionID, versionID, err := getIDsFromURL(latestVersionURL)
+	latestVersion, err := links.ParseVersion(latestVersionURL)
 	if err != nil {
 		log.Error(ctx, "failed to parse latest version url", err)
 		return []datasetApiModels.Dimension{}
 	}
 
-	latestPublishedVersion, err := dc.GetVersion(ctx, headers, datasetID, editionID, versionID)
+	latestPublishedVersion, err := dc.GetVersion(ctx, headers, latestVersion.DatasetID, latestVersion.EditionID, latestVersion.VersionID)
 	if err != nil {
 		log.Error(ctx, "failed Get latest published version details", err)
-		setErrorStatusCode(req, w, err, datasetID)
+		setErrorStatusCode(req, w, err, latestVersion.DatasetID)
 		return []datasetApiModels.Dimension{}
 	}
 
 	return latestPublishedVersion.Dimensions
 }
 
-func getIDsFromURL(urlValue string) (datasetID, editionID, versionID string, err error) {
-	parsedURL, err := url.Parse(urlValue)
-	if err != nil {
-		return "", "", "", err
-	}
-
-	s := strings.Split(parsedURL.Path, "/")
-	if len(s) < 8 {
-		return "", "", "", errors.New("not enough arguements in path")
-	}
-	datasetID = s[3]
-	editionID = s[5]
-	versionID = s[7]
-	return datasetID, editionID, versionID, nil
-}
-
 func setErrorStatusCode(req *http.Request, w http.ResponseWriter, err error, datasetID string) {
 	status := http.StatusInternalServerError
 	if err, ok := err.(ClientError); ok {
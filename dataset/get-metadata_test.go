@@ -7,12 +7,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
 
 	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	. "github.com/smartystreets/goconvey/convey"
@@ -57,7 +59,7 @@ func TestUnitHandlers(t *testing.T) {
 		mockDatasetDetails := datasetApiModels.Dataset{
 			ID:           "test-dataset",
 			CollectionID: mockCollectionId,
-			Links:        &datasetApiModels.DatasetLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "/v1/datasets/test/editions/test/version/1"}},
+			Links:        &datasetApiModels.DatasetLinks{LatestVersion: &datasetApiModels.LinkObject{HRef: "/v1/datasets/test/editions/test/versions/1"}},
 		}
 
 		mockDataset := datasetApiModels.DatasetUpdate{
@@ -113,13 +115,19 @@ func TestUnitHandlers(t *testing.T) {
 			},
 		}
 
+		mockReleaseCalendar := &ReleaseCalendarMock{
+			LinkedFunc: func(ctx context.Context, userAccessToken, datasetID, edition, version string) (releases.Link, releases.Release, error) {
+				return releases.Link{}, releases.Release{}, releases.ErrNotLinked
+			},
+		}
+
 		Convey("when Version.State is NOT edition-confirmed returns correctly with empty dimensions struct", func() {
 			mockVersionDetails.State = "associated"
 
 			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", http.NoBody)
 			req.Header.Set("Collection-Id", mockCollectionId)
 			req.Header.Set("X-Florence-Token", mockUserAuthToken)
-			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient), nil)
+			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, mockReleaseCalendar), nil)
 
 			So(w.Code, ShouldEqual, http.StatusOK)
 			So(w.Body.String(), ShouldNotBeNil)
@@ -134,6 +142,7 @@ func TestUnitHandlers(t *testing.T) {
 			So(body.CollectionID, ShouldEqual, mockCollectionId)
 			So(body.CollectionState, ShouldEqual, datasetCollectionItem.State)
 			So(body.CollectionLastEditedBy, ShouldEqual, datasetCollectionItem.LastEditedBy)
+			So(body.Release, ShouldBeNil)
 		})
 
 		Convey("when Version.State is edition-confirmed returns correctly with populated dimensions struct", func() {
@@ -145,7 +154,7 @@ func TestUnitHandlers(t *testing.T) {
 			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", http.NoBody)
 			req.Header.Set("Collection-Id", mockCollectionId)
 			req.Header.Set("X-Florence-Token", mockUserAuthToken)
-			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient), nil)
+			w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, mockReleaseCalendar), nil)
 
 			So(w.Code, ShouldEqual, http.StatusOK)
 			So(w.Body.String(), ShouldNotBeNil)
@@ -161,23 +170,44 @@ func TestUnitHandlers(t *testing.T) {
 			So(body.CollectionState, ShouldEqual, datasetCollectionItem.State)
 			So(body.CollectionLastEditedBy, ShouldEqual, datasetCollectionItem.LastEditedBy)
 		})
-	})
 
-	Convey("test getIDsFromURL", t, func() {
-		expectedErr := errors.New("not enough arguements in path")
-		Convey("returns error if url doesn't have enough path elements", func() {
-			_, _, _, err := getIDsFromURL("https://test.ons.gov.uk/this/isnt/enough")
+		Convey("when the version is linked to a release", func() {
+			mockVersionDetails.ReleaseDate = "2020-02-20T00:00:00.000Z"
+			release := releases.Release{ID: "cpih-jan-2020", Title: "Consumer price inflation, UK: January 2020", ReleaseDate: time.Date(2020, 2, 19, 7, 0, 0, 0, time.UTC)}
+			rc := &ReleaseCalendarMock{
+				LinkedFunc: func(ctx context.Context, userAccessToken, datasetID, edition, version string) (releases.Link, releases.Release, error) {
+					return releases.Link{ReleaseID: release.ID}, release, nil
+				},
+			}
 
-			So(err, ShouldResemble, expectedErr)
-		})
+			req := httptest.NewRequest("GET", "/datasets/bar/editions/baz/versions/1", http.NoBody)
+			req.Header.Set("Collection-Id", mockCollectionId)
+			req.Header.Set("X-Florence-Token", mockUserAuthToken)
 
-		Convey("returns correct values", func() {
-			datasetID, editionID, versionID, err := getIDsFromURL("https://test.ons.gov.uk/v1/datasets/ds1/editions/ed2/versions/1")
+			Convey("flags a release date that does not match the release", func() {
+				w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, rc), nil)
+
+				So(w.Code, ShouldEqual, http.StatusOK)
+				var body model.EditMetadata
+				So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+				So(body.Release.ID, ShouldEqual, release.ID)
+				So(body.Release.Title, ShouldEqual, release.Title)
+				So(body.Release.ReleaseDateMismatch, ShouldBeTrue)
+				So(body.Release.Warning, ShouldNotBeEmpty)
+				So(rc.LinkedCalls()[0].DatasetID, ShouldEqual, "bar")
+			})
+
+			Convey("returns the metadata with a warning when the release is no longer in the calendar", func() {
+				rc.LinkedFunc = func(ctx context.Context, userAccessToken, datasetID, edition, version string) (releases.Link, releases.Release, error) {
+					return releases.Link{ReleaseID: release.ID}, releases.Release{}, releases.ErrNotFound
+				}
+				w := doTestRequest("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}", req, GetMetadataHandler(mockDatasetClient, mockZebedeeClient, rc), nil)
 
-			So(datasetID, ShouldEqual, "ds1")
-			So(editionID, ShouldEqual, "ed2")
-			So(versionID, ShouldEqual, "1")
-			So(err, ShouldBeNil)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				var body model.EditMetadata
+				So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+				So(body.Release, ShouldResemble, &model.VersionRelease{ID: release.ID, Warning: "the release is no longer in the release calendar"})
+			})
 		})
 	})
 }
@@ -0,0 +1,180 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/csvfile"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetPreview is a handler that wraps getPreview passing in addition arguments
+func GetPreview(dc DatasetAPIClient, fb FileBackend, dl DownloadClient) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getPreview(w, r, dc, fb, dl, accessToken, collectionID)
+	})
+}
+
+// getPreview returns a page of the rows at the start of a csv file of a version, with its detected layout, so that
+// a wrong upload can be spotted before the version is confirmed. The file is the one given in the file query
+// parameter, which must have been uploaded for the version, or else the version's csv distribution. A version whose
+// csv file is not in the file backend, such as a CMD version, has its csv download read from the download service
+func getPreview(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, fb FileBackend, dl DownloadClient, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	offset, limit, err := pagination(req)
+	if err == nil && offset+limit > csvfile.MaxPreviewRows {
+		err = fmt.Errorf("the preview only covers the first %d rows of a file", csvfile.MaxPreviewRows)
+	}
+	if err != nil {
+		log.Error(ctx, "getPreview endpoint: invalid pagination", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info(ctx, "calling get preview", log.Data(logInfo))
+
+	var r io.ReadCloser
+	path := req.URL.Query().Get("file")
+	if path != "" {
+		path, err = upload.CleanPath(path)
+		if err == nil && !strings.HasPrefix(path, upload.VersionPrefix(datasetID, edition, version)) {
+			err = fmt.Errorf("%s was not uploaded for this version", path)
+		}
+		if err != nil {
+			log.Error(ctx, "getPreview endpoint: invalid file", err, log.Data(logInfo))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+		if err != nil {
+			log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+			setErrorStatusCode(req, w, err, datasetID)
+			return
+		}
+
+		var ok bool
+		if path, ok = csvDistributionPath(v); !ok {
+			href, ok := csvDownloadHRef(v)
+			if !ok {
+				log.Error(ctx, "getPreview endpoint: version has no csv file", nil, log.Data(logInfo))
+				http.Error(w, "the version has no csv file", http.StatusNotFound)
+				return
+			}
+			if r, err = dl.Open(ctx, userAccessToken, collectionID, href); err != nil {
+				log.Error(ctx, "error reading csv download", err, log.Data(logInfo))
+				setErrorStatusCode(req, w, err, datasetID)
+				return
+			}
+			path = href
+		}
+	}
+	logInfo["path"] = path
+
+	if r == nil {
+		r, err = fb.Open(ctx, path)
+	}
+	if errors.Is(err, upload.ErrNotFound) {
+		log.Error(ctx, "getPreview endpoint: file not found", err, log.Data(logInfo))
+		http.Error(w, fmt.Sprintf("no file has been uploaded to %s", path), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error opening file", err, log.Data(logInfo))
+		http.Error(w, "error opening file", http.StatusInternalServerError)
+		return
+	}
+	defer r.Close()
+
+	preview, err := csvfile.Preview(r, path, offset, limit)
+	if errors.Is(err, csvfile.ErrEmpty) {
+		log.Error(ctx, "getPreview endpoint: empty file", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error reading file", err, log.Data(logInfo))
+		http.Error(w, "error reading file", http.StatusInternalServerError)
+		return
+	}
+
+	b, err := json.Marshal(preview)
+	if err != nil {
+		log.Error(ctx, "error marshalling preview to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling preview to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get preview: request successful", log.Data(logInfo))
+}
+
+// csvDistributionPath returns the file backend path of the csv distribution of a version. Distributions downloaded
+// from elsewhere have an absolute url, and are found by csvDownloadHRef instead
+func csvDistributionPath(v datasetApiModels.Version) (string, bool) {
+	if v.Distributions == nil {
+		return "", false
+	}
+	for _, d := range *v.Distributions {
+		if d.Format == datasetApiModels.DistributionFormatCSV && !strings.Contains(d.DownloadURL, "://") {
+			path, err := upload.CleanPath(d.DownloadURL)
+			return path, err == nil
+		}
+	}
+	return "", false
+}
+
+// csvDownloadHRef returns the url of the csv download of a version that is not in the file backend: the csv
+// download of a CMD version, or else a csv distribution downloaded from elsewhere
+func csvDownloadHRef(v datasetApiModels.Version) (string, bool) {
+	if v.Downloads != nil && v.Downloads.CSV != nil && v.Downloads.CSV.HRef != "" {
+		return v.Downloads.CSV.HRef, true
+	}
+	if v.Distributions == nil {
+		return "", false
+	}
+	for _, d := range *v.Distributions {
+		if d.Format == datasetApiModels.DistributionFormatCSV && strings.Contains(d.DownloadURL, "://") {
+			return d.DownloadURL, true
+		}
+	}
+	return "", false
+}
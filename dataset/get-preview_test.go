@@ -0,0 +1,170 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetPreview(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/preview"
+	const content = "V4_0,mmm-yy,Time,uk-only,Geography\n88.1,Jan-20,Jan-20,K02000001,United Kingdom\n88.4,Feb-20,Feb-20,K02000001,United Kingdom\n"
+
+	newFileBackend := func() *FileBackendMock {
+		return &FileBackendMock{
+			OpenFunc: func(ctx context.Context, path string) (io.ReadCloser, error) {
+				if path != "cpih01/time-series/2/cpih.csv" {
+					return nil, upload.ErrNotFound
+				}
+				return io.NopCloser(strings.NewReader(content)), nil
+			},
+		}
+	}
+
+	newDownloadClient := func() *DownloadClientMock {
+		return &DownloadClientMock{
+			OpenFunc: func(ctx context.Context, userAccessToken, collectionID, href string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(content)), nil
+			},
+		}
+	}
+
+	newRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/datasets/cpih01/editions/time-series/versions/2/preview"+query, http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test getPreview", t, func() {
+		Convey("previews the csv distribution of the version", func() {
+			fb := newFileBackend()
+			w := doTestRequest(target, newRequest("?limit=1"), GetPreview(newVersionFilesDatasetClient(), fb, newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(fb.OpenCalls()[0].Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+
+			var p model.Preview
+			So(json.Unmarshal(w.Body.Bytes(), &p), ShouldBeNil)
+			So(p.HasHeader, ShouldBeTrue)
+			So(p.V4.Dimensions, ShouldResemble, []model.V4Dimension{{CodeList: "mmm-yy", Name: "Time"}, {CodeList: "uk-only", Name: "Geography"}})
+			So(p.Rows, ShouldResemble, [][]string{{"88.1", "Jan-20", "Jan-20", "K02000001", "United Kingdom"}})
+			So(p.HasMore, ShouldBeTrue)
+		})
+
+		Convey("previews a file uploaded for the version without reading the version", func() {
+			dc := newVersionFilesDatasetClient()
+			w := doTestRequest(target, newRequest("?file=/cpih01/time-series/2/cpih.csv"), GetPreview(dc, newFileBackend(), newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(dc.GetVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 for a file uploaded for another version", func() {
+			fb := newFileBackend()
+			w := doTestRequest(target, newRequest("?file=cpih01/time-series/1/cpih.csv"), GetPreview(newVersionFilesDatasetClient(), fb, newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(fb.OpenCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 for a page beyond the rows a preview covers", func() {
+			w := doTestRequest(target, newRequest("?offset=9990&limit=20"), GetPreview(newVersionFilesDatasetClient(), newFileBackend(), newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("previews the csv download of a version whose file is not in the file backend", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Downloads: &datasetApiModels.DownloadList{
+					CSV: &datasetApiModels.DownloadObject{HRef: "https://download.ons.gov.uk/downloads/datasets/cpih01/editions/time-series/versions/2.csv"},
+				}}, nil
+			}
+			fb := newFileBackend()
+			dl := newDownloadClient()
+			w := doTestRequest(target, newRequest(""), GetPreview(dc, fb, dl), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(fb.OpenCalls(), ShouldBeEmpty)
+			So(dl.OpenCalls()[0].Href, ShouldEqual, "https://download.ons.gov.uk/downloads/datasets/cpih01/editions/time-series/versions/2.csv")
+			So(dl.OpenCalls()[0].UserAccessToken, ShouldEqual, "testuser")
+			So(dl.OpenCalls()[0].CollectionID, ShouldEqual, "testcollection")
+
+			var p model.Preview
+			So(json.Unmarshal(w.Body.Bytes(), &p), ShouldBeNil)
+			So(p.Rows, ShouldHaveLength, 2)
+		})
+
+		Convey("previews a csv distribution downloaded from elsewhere through the download service", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Distributions: &[]datasetApiModels.Distribution{
+					{Format: datasetApiModels.DistributionFormatXLSX, DownloadURL: "/cpih01/time-series/2/cpih.xlsx"},
+					{Format: datasetApiModels.DistributionFormatCSV, DownloadURL: "https://download.ons.gov.uk/cpih.csv"},
+				}}, nil
+			}
+			dl := newDownloadClient()
+			w := doTestRequest(target, newRequest(""), GetPreview(dc, newFileBackend(), dl), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(dl.OpenCalls()[0].Href, ShouldEqual, "https://download.ons.gov.uk/cpih.csv")
+		})
+
+		Convey("returns 404 when the download service has no csv download", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Downloads: &datasetApiModels.DownloadList{CSV: &datasetApiModels.DownloadObject{HRef: "https://download.ons.gov.uk/cpih.csv"}}}, nil
+			}
+			dl := newDownloadClient()
+			dl.OpenFunc = func(ctx context.Context, userAccessToken, collectionID, href string) (io.ReadCloser, error) {
+				return nil, &testCliError{}
+			}
+			w := doTestRequest(target, newRequest(""), GetPreview(dc, newFileBackend(), dl), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("returns 404 when the version has no csv file", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Distributions: &[]datasetApiModels.Distribution{
+					{Format: datasetApiModels.DistributionFormatXLSX, DownloadURL: "/cpih01/time-series/2/cpih.xlsx"},
+				}}, nil
+			}
+			w := doTestRequest(target, newRequest(""), GetPreview(dc, newFileBackend(), newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("returns 404 when the file has not been uploaded", func() {
+			w := doTestRequest(target, newRequest("?file=cpih01/time-series/2/missing.csv"), GetPreview(newVersionFilesDatasetClient(), newFileBackend(), newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("returns 500 when the file cannot be opened", func() {
+			fb := newFileBackend()
+			fb.OpenFunc = func(ctx context.Context, path string) (io.ReadCloser, error) {
+				return nil, errors.New("backend error")
+			}
+			w := doTestRequest(target, newRequest(""), GetPreview(newVersionFilesDatasetClient(), fb, newDownloadClient()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package dataset
+
+import (
+	"encoding/json"
+	"net/http"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetVersionSummary is a handler that wraps getVersionSummary passing in addition arguments
+func GetVersionSummary(dc DatasetAPIClient) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getVersionSummary(w, r, dc, accessToken, collectionID)
+	})
+}
+
+// getVersionSummary returns the metadata of a version as the simple list view model used by the read only preview
+// screen in Florence
+func getVersionSummary(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	log.Info(ctx, "calling get version summary", log.Data(logInfo))
+
+	d, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	if err != nil {
+		log.Error(ctx, "failed Get dataset details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	// the preview shows the metadata being edited, so the next document is used if there is one
+	ds := d.Current
+	if d.Next != nil {
+		ds = d.Next
+	}
+	if ds == nil {
+		log.Error(ctx, "dataset has no current or next document", nil, log.Data(logInfo))
+		http.Error(w, "dataset has no metadata", http.StatusNotFound)
+		return
+	}
+
+	v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	summary := mapper.EditDatasetVersionMetaData(*ds, v)
+	if len(summary.Warnings) > 0 {
+		log.Warn(ctx, "version summary has fields that could not be mapped", log.Data{"warnings": summary.Warnings, "datasetID": datasetID})
+	}
+
+	b, err := json.Marshal(summary)
+	if err != nil {
+		log.Error(ctx, "error marshalling version summary to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling version summary to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(b)
+	if err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get version summary: request successful", log.Data(logInfo))
+}
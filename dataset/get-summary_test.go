@@ -0,0 +1,107 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetVersionSummary(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/summary"
+	const url = "/datasets/cpih01/editions/time-series/versions/2/summary"
+
+	newDatasetClient := func() *DatasetAPIClientMock {
+		return &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID:      datasetID,
+					Current: &datasetApiModels.Dataset{ID: datasetID, Title: "published title"},
+					Next: &datasetApiModels.Dataset{
+						ID:       datasetID,
+						Title:    "edited title",
+						Contacts: []datasetApiModels.ContactDetails{{Name: "Prices team"}, {Name: "Housing team"}},
+					},
+				}, nil
+			},
+			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{
+					ID:           "version-2",
+					Version:      2,
+					CollectionID: "testcollection",
+					Alerts:       &[]datasetApiModels.Alert{{Date: "2020-11-07T00:00:00.000Z", Description: "Corrected weights", Type: "correction"}},
+				}, nil
+			},
+		}
+	}
+
+	serve := func(dc DatasetAPIClient) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		router := mux.NewRouter()
+		router.Path(target).HandlerFunc(GetVersionSummary(dc)).Methods(http.MethodGet)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Convey("test getVersionSummary", t, func() {
+		Convey("returns the simple list view model of the edited metadata", func() {
+			dc := newDatasetClient()
+			w := serve(dc)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			var summary model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &summary), ShouldBeNil)
+			So(summary.InstanceID, ShouldEqual, "version-2")
+			So(summary.Collection, ShouldEqual, "testcollection")
+			So(summary.MetaData.Title, ShouldEqual, "edited title")
+			So(summary.MetaData.Contacts, ShouldHaveLength, 2)
+			So(summary.MetaData.Notices[0].SimpleListHeading, ShouldEqual, "correction (07 Nov 2020)")
+
+			So(dc.GetVersionCalls()[0].Headers, ShouldResemble, datasetApiSdk.Headers{CollectionID: "testcollection", AccessToken: "testuser"})
+		})
+
+		Convey("returns 404 if the dataset has no documents", func() {
+			dc := newDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID}, nil
+			}
+
+			w := serve(dc)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(dc.GetVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns the summary with a warning for an alert that cannot be mapped", func() {
+			dc := newDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{Alerts: &[]datasetApiModels.Alert{{Date: "yesterday", Type: "alert"}}}, nil
+			}
+
+			w := serve(dc)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var summary model.EditVersionMetaData
+			So(json.Unmarshal(w.Body.Bytes(), &summary), ShouldBeNil)
+			So(summary.MetaData.Title, ShouldEqual, "edited title")
+			So(summary.Warnings, ShouldHaveLength, 1)
+			So(summary.Warnings[0].Path, ShouldEqual, "/alerts/0/date")
+		})
+	})
+}
@@ -4,14 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
 // GetTopics returns a mapped list of topics
 func GetTopics(bc BabbageClient) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
 		getTopics(w, r, bc, accessToken, collectionID, lang)
 	})
 }
@@ -19,7 +18,7 @@ func GetTopics(bc BabbageClient) http.HandlerFunc {
 func getTopics(w http.ResponseWriter, req *http.Request, bc BabbageClient, userAccessToken, collectionID, lang string) {
 	ctx := req.Context()
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -0,0 +1,62 @@
+package dataset
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetVersionFileChunk is a handler that wraps getVersionFileChunk passing in addition arguments
+func GetVersionFileChunk(cu ChunkUploader) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getVersionFileChunk(w, r, cu, accessToken, collectionID)
+	})
+}
+
+// getVersionFileChunk tells Resumable.js whether a chunk of an upload has already been received. It responds 200 if
+// it has, and 204 if the chunk needs to be sent
+func getVersionFileChunk(w http.ResponseWriter, req *http.Request, cu ChunkUploader, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	logInfo := map[string]interface{}{
+		"datasetID":    vars["datasetID"],
+		"edition":      vars["editionID"],
+		"version":      vars["versionID"],
+		"collectionID": collectionID,
+	}
+
+	chunk, err := parseChunk(req)
+	if err != nil {
+		log.Error(ctx, "getVersionFileChunk endpoint: invalid chunk", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logInfo["identifier"] = chunk.Identifier
+	logInfo["chunk"] = chunk.Number
+
+	path := upload.VersionPath(vars["datasetID"], vars["editionID"], vars["versionID"], chunk.Filename)
+	logInfo["path"] = path
+
+	received, err := cu.HasChunk(ctx, path, chunk)
+	if err != nil {
+		log.Error(ctx, "error checking for chunk", err, log.Data(logInfo))
+		writeChunkError(w, req, err, vars["datasetID"])
+		return
+	}
+
+	if !received {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,63 @@
+package dataset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetVersionFileChunk(t *testing.T) {
+	t.Parallel()
+
+	serve := func(cu ChunkUploader, fields map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/datasets/cpih01/editions/time-series/versions/2/files/chunks", http.NoBody)
+		q := req.URL.Query()
+		for k, v := range fields {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return doTestRequest(versionFileChunksTarget, req, GetVersionFileChunk(cu), nil)
+	}
+
+	Convey("test getVersionFileChunk", t, func() {
+		received := map[int]bool{1: true}
+		cu := &ChunkUploaderMock{
+			HasChunkFunc: func(ctx context.Context, path string, c upload.Chunk) (bool, error) {
+				return received[c.Number], nil
+			},
+		}
+
+		Convey("returns 200 for a chunk that has been received", func() {
+			w := serve(cu, chunkFields())
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(cu.HasChunkCalls()[0].C.Identifier, ShouldEqual, "10-cpihcsv")
+			So(cu.HasChunkCalls()[0].Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+		})
+
+		Convey("returns 204 for a chunk that needs to be sent", func() {
+			fields := chunkFields()
+			fields["resumableChunkNumber"] = strconv.Itoa(2)
+			w := serve(cu, fields)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("returns 400 for an invalid chunk", func() {
+			fields := chunkFields()
+			fields["resumableChunkNumber"] = "first"
+			w := serve(cu, fields)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(cu.HasChunkCalls(), ShouldBeEmpty)
+		})
+	})
+}
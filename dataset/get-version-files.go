@@ -0,0 +1,85 @@
+package dataset
+
+import (
+	"encoding/json"
+	"net/http"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetVersionFiles is a handler that wraps getVersionFiles passing in addition arguments
+func GetVersionFiles(dc DatasetAPIClient, fb FileBackend) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		getVersionFiles(w, r, dc, fb, accessToken, collectionID)
+	})
+}
+
+// getVersionFiles returns the files uploaded for a version with the state of their upload and virus scan, and the
+// distribution that each one is registered as
+func getVersionFiles(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, fb FileBackend, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	log.Info(ctx, "calling get version files", log.Data(logInfo))
+
+	v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	files, err := fb.List(ctx, upload.VersionPrefix(datasetID, edition, version))
+	if err != nil {
+		log.Error(ctx, "error listing version files", err, log.Data(logInfo))
+		http.Error(w, "error listing version files", http.StatusInternalServerError)
+		return
+	}
+
+	versionFiles := make([]VersionFile, 0, len(files))
+	for _, f := range files {
+		versionFiles = append(versionFiles, VersionFile{File: f, Distribution: mapper.FindDistribution(v.Distributions, f)})
+	}
+
+	b, err := json.Marshal(versionFiles)
+	if err != nil {
+		log.Error(ctx, "error marshalling version files to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling version files to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "get version files: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,77 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetVersionFiles(t *testing.T) {
+	t.Parallel()
+
+	newFileBackend := func() *FileBackendMock {
+		return &FileBackendMock{
+			ListFunc: func(ctx context.Context, prefix string) ([]upload.File, error) {
+				return []upload.File{
+					{Path: prefix + "cpih.csv", State: upload.StateUploaded, ScanState: upload.ScanClean},
+					{Path: prefix + "cpih.xlsx", State: upload.StateCreated, ScanState: upload.ScanPending},
+				}, nil
+			},
+		}
+	}
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/datasets/cpih01/editions/time-series/versions/2/files", http.NoBody)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test getVersionFiles", t, func() {
+		Convey("lists the files of the version with the distribution each one is registered as", func() {
+			fb := newFileBackend()
+			w := doTestRequest(versionFilesTarget, newRequest(), GetVersionFiles(newVersionFilesDatasetClient(), fb), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(fb.ListCalls()[0].Prefix, ShouldEqual, "cpih01/time-series/2/")
+
+			var files []VersionFile
+			So(json.Unmarshal(w.Body.Bytes(), &files), ShouldBeNil)
+			So(files, ShouldHaveLength, 2)
+			So(files[0].Distribution.Title, ShouldEqual, "CPIH csv")
+			So(files[1].State, ShouldEqual, upload.StateCreated)
+			So(files[1].Distribution, ShouldBeNil)
+		})
+
+		Convey("returns 404 when the version does not exist", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{}, &testCliError{}
+			}
+			fb := newFileBackend()
+			w := doTestRequest(versionFilesTarget, newRequest(), GetVersionFiles(dc, fb), nil)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(fb.ListCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 500 when the files cannot be listed", func() {
+			fb := newFileBackend()
+			fb.ListFunc = func(ctx context.Context, prefix string) ([]upload.File, error) {
+				return nil, errors.New("backend error")
+			}
+			w := doTestRequest(versionFilesTarget, newRequest(), GetVersionFiles(newVersionFilesDatasetClient(), fb), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
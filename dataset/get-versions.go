@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
@@ -14,7 +15,7 @@ import (
 
 // GetVersions returns a mapped list of all versions
 func GetVersions(dc DatasetAPIClient, batchSize, maxWorkers int) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
 		getVersions(w, r, dc, accessToken, collectionID, lang, batchSize, maxWorkers)
 	})
 }
@@ -31,7 +32,7 @@ func getVersions(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 		"edition":   editionID,
 	}
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -45,26 +46,47 @@ func getVersions(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 		AccessToken:  userAccessToken,
 	}
 
-	dataset, err := dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
-	if err != nil {
-		errMsg := fmt.Sprintf("error getting dataset from dataset API: %v", err.Error())
-		log.Error(ctx, "error getting dataset from dataset API", err, log.Data(logInfo))
+	// the dataset, edition and versions, which include the status of each version's downloads, are independent of
+	// each other so they are fetched at the same time
+	var (
+		wg                                 sync.WaitGroup
+		dataset                            datasetApiModels.DatasetUpdate
+		edition                            datasetApiModels.Edition
+		versions                           datasetApiSdk.VersionsList
+		datasetErr, editionErr, versionErr error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		dataset, datasetErr = dc.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	}()
+	go func() {
+		defer wg.Done()
+		edition, editionErr = dc.GetEdition(ctx, headers, datasetID, editionID)
+	}()
+	go func() {
+		defer wg.Done()
+		versions, versionErr = dc.GetVersionsInBatches(ctx, headers, datasetID, editionID, batchSize, maxWorkers)
+	}()
+	wg.Wait()
+
+	if datasetErr != nil {
+		errMsg := fmt.Sprintf("error getting dataset from dataset API: %v", datasetErr.Error())
+		log.Error(ctx, "error getting dataset from dataset API", datasetErr, log.Data(logInfo))
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
 
-	edition, err := dc.GetEdition(ctx, headers, datasetID, editionID)
-	if err != nil {
-		errMsg := fmt.Sprintf("error getting edition from dataset API: %v", err.Error())
-		log.Error(ctx, "error getting edition from dataset API", err, log.Data(logInfo))
+	if editionErr != nil {
+		errMsg := fmt.Sprintf("error getting edition from dataset API: %v", editionErr.Error())
+		log.Error(ctx, "error getting edition from dataset API", editionErr, log.Data(logInfo))
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
 
-	versions, err := dc.GetVersionsInBatches(ctx, headers, datasetID, editionID, batchSize, maxWorkers)
-	if err != nil {
-		errMsg := fmt.Sprintf("error getting all versions from dataset API: %v", err.Error())
-		log.Error(ctx, "error getting all versions from dataset API", err, log.Data(logInfo))
+	if versionErr != nil {
+		errMsg := fmt.Sprintf("error getting all versions from dataset API: %v", versionErr.Error())
+		log.Error(ctx, "error getting all versions from dataset API", versionErr, log.Data(logInfo))
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
@@ -42,10 +42,13 @@ func TestUnitGetVersions(t *testing.T) {
 		{
 			ID:      "version-2",
 			Version: 2,
+			Downloads: &datasetApiModels.DownloadList{
+				CSV: &datasetApiModels.DownloadObject{Private: "s3://private/version-2.csv", Size: "2048"},
+			},
 		},
 	}
 
-	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"edition_name\":\"edition-1\",\"versions\":[{\"id\":\"version-2\",\"title\":\"Version: 2\",\"version\":2,\"release_date\":\"\",\"state\":\"\"},{\"id\":\"version-1\",\"title\":\"Version: 1\",\"version\":1,\"release_date\":\"\",\"state\":\"\"}]}"
+	expectedSuccessResponse := "{\"dataset_name\":\"Test title\",\"edition_name\":\"edition-1\",\"versions\":[{\"id\":\"version-2\",\"title\":\"Version: 2\",\"version\":2,\"release_date\":\"\",\"state\":\"\",\"downloads\":[{\"format\":\"csv\",\"available\":true,\"size\":2048,\"private\":true,\"public\":false},{\"format\":\"xlsx\",\"available\":false,\"private\":false,\"public\":false},{\"format\":\"csvw\",\"available\":false,\"private\":false,\"public\":false},{\"format\":\"txt\",\"available\":false,\"private\":false,\"public\":false}]},{\"id\":\"version-1\",\"title\":\"Version: 1\",\"version\":1,\"release_date\":\"\",\"state\":\"\",\"downloads\":[{\"format\":\"csv\",\"available\":false,\"private\":false,\"public\":false},{\"format\":\"xlsx\",\"available\":false,\"private\":false,\"public\":false},{\"format\":\"csvw\",\"available\":false,\"private\":false,\"public\":false},{\"format\":\"txt\",\"available\":false,\"private\":false,\"public\":false}]}]}"
 
 	Convey("test getAllVersions", t, func() {
 		mockDatasetClient := &DatasetAPIClientMock{
@@ -155,5 +158,33 @@ func TestUnitGetVersions(t *testing.T) {
 				So(response, ShouldResemble, "error getting all versions from dataset API: test dataset API error\n")
 			})
 		})
+
+		Convey("handles an edition error while the other calls are made at the same time", func() {
+			mockDatasetClient := &DatasetAPIClientMock{
+				GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return mockedDatasetResponse, nil
+				},
+				GetEditionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string) (datasetApiModels.Edition, error) {
+					return datasetApiModels.Edition{}, errors.New("test dataset API error")
+				},
+				GetVersionsInBatchesFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, batchSize int, maxWorkers int) (datasetApiSdk.VersionsList, error) {
+					return datasetApiSdk.VersionsList{Items: mockedVersionsResponse}, nil
+				},
+			}
+
+			reqURL := fmt.Sprintf("/datasets/%v/editions/%v/versions", datasetID, editionID)
+			req := httptest.NewRequest("GET", reqURL, http.NoBody)
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			rec := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.Path(reqURL).HandlerFunc(GetVersions(mockDatasetClient, verionsBatchSize, versionsMaxWorkers))
+			router.ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+			So(rec.Body.String(), ShouldResemble, "error getting edition from dataset API: test dataset API error\n")
+			So(mockDatasetClient.GetDatasetCurrentAndNextCalls(), ShouldHaveLength, 1)
+			So(mockDatasetClient.GetVersionsInBatchesCalls(), ShouldHaveLength, 1)
+		})
 	})
 }
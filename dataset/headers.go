@@ -1,11 +1,33 @@
 package dataset
 
-import "errors"
+import (
+	"errors"
+	"net/http"
 
-func checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID string) error {
+	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+)
+
+// controllerHandler wraps dphandlers.ControllerHandler so that a request made by an authorised service is
+// handled with the service's token in place of a user access token
+func controllerHandler(f dphandlers.ControllerHandlerFunc) http.HandlerFunc {
+	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		if caller, ok := authorisation.ServiceCallerFromContext(r.Context()); ok && accessToken == "" {
+			accessToken = caller.Token
+		}
+		f(w, r, lang, collectionID, accessToken)
+	})
+}
+
+// checkAccessTokenAndCollectionHeaders returns an error if the access token or collection ID is missing. A
+// service may read datasets without a collection ID, but must give one to make changes.
+func checkAccessTokenAndCollectionHeaders(req *http.Request, userAccessToken, collectionID string) error {
 	if userAccessToken == "" {
 		return errors.New("no user access token header set")
 	}
+	if _, ok := authorisation.ServiceCallerFromContext(req.Context()); ok && req.Method == http.MethodGet {
+		return nil
+	}
 	if collectionID == "" {
 		return errors.New("no collection ID header set")
 	}
@@ -0,0 +1,275 @@
+package dataset
+
+import (
+	"context"
+	"io"
+	"time"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// UpstreamObserver records the outcome and latency of calls to upstream services
+type UpstreamObserver interface {
+	ObserveUpstream(upstream, method string, start time.Time, err error)
+	ObserveBatch(operation string, start time.Time, items int, err error)
+}
+
+var tracer = otel.Tracer("github.com/ONSdigital/dp-publishing-dataset-controller/dataset")
+
+// upstreamCall is a call to an upstream service that is being timed and traced
+type upstreamCall struct {
+	upstream string
+	method   string
+	start    time.Time
+	span     trace.Span
+	observer UpstreamObserver
+}
+
+// startUpstreamCall starts a client span for a call to an upstream service. The returned context carries the
+// span so that the trace context is passed on in the outgoing request.
+func startUpstreamCall(ctx context.Context, o UpstreamObserver, upstream, method string) (context.Context, *upstreamCall) {
+	ctx, span := tracer.Start(ctx, upstream+" "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("upstream", upstream), attribute.String("upstream.method", method)),
+	)
+
+	return ctx, &upstreamCall{upstream: upstream, method: method, start: time.Now(), span: span, observer: o}
+}
+
+// end records the outcome of the call and ends its span
+func (c *upstreamCall) end(err error) {
+	c.observer.ObserveUpstream(c.upstream, c.method, c.start, err)
+	if err != nil {
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+}
+
+// endBatch records the outcome and number of items fetched by a batched call and ends its span
+func (c *upstreamCall) endBatch(items int, err error) {
+	c.observer.ObserveBatch(c.method, c.start, items, err)
+	c.span.SetAttributes(attribute.Int("batch.items", items))
+	c.end(err)
+}
+
+// InstrumentedDatasetAPIClient wraps a DatasetAPIClient, recording metrics and a span for every call made to the dataset API
+type InstrumentedDatasetAPIClient struct {
+	client   DatasetAPIClient
+	observer UpstreamObserver
+}
+
+// NewInstrumentedDatasetAPIClient returns a DatasetAPIClient that records metrics and spans for the calls made through dc
+func NewInstrumentedDatasetAPIClient(dc DatasetAPIClient, o UpstreamObserver) *InstrumentedDatasetAPIClient {
+	return &InstrumentedDatasetAPIClient{client: dc, observer: o}
+}
+
+func (c *InstrumentedDatasetAPIClient) start(ctx context.Context, method string) (context.Context, *upstreamCall) {
+	return startUpstreamCall(ctx, c.observer, metrics.UpstreamDatasetAPI, method)
+}
+
+func (c *InstrumentedDatasetAPIClient) GetDatasetsInBatches(ctx context.Context, headers datasetApiSdk.Headers, batchSize, maxWorkers int) (datasetApiSdk.DatasetsList, error) {
+	ctx, call := c.start(ctx, "GetDatasetsInBatches")
+	datasets, err := c.client.GetDatasetsInBatches(ctx, headers, batchSize, maxWorkers)
+	call.endBatch(len(datasets.Items), err)
+	return datasets, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetEdition(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string) (datasetApiModels.Edition, error) {
+	ctx, call := c.start(ctx, "GetEdition")
+	e, err := c.client.GetEdition(ctx, headers, datasetID, edition)
+	call.end(err)
+	return e, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetEditions(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.EditionsList, error) {
+	ctx, call := c.start(ctx, "GetEditions")
+	editions, err := c.client.GetEditions(ctx, headers, datasetID, q)
+	call.end(err)
+	return editions, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetDatasetCurrentAndNext(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+	ctx, call := c.start(ctx, "GetDatasetCurrentAndNext")
+	d, err := c.client.GetDatasetCurrentAndNext(ctx, headers, datasetID)
+	call.end(err)
+	return d, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetVersionWithHeaders(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+	ctx, call := c.start(ctx, "GetVersionWithHeaders")
+	v, h, err := c.client.GetVersionWithHeaders(ctx, headers, datasetID, edition, version)
+	call.end(err)
+	return v, h, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetVersion(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+	ctx, call := c.start(ctx, "GetVersion")
+	v, err := c.client.GetVersion(ctx, headers, datasetID, edition, version)
+	call.end(err)
+	return v, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetVersionDimensionOptions(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+	ctx, call := c.start(ctx, "GetVersionDimensionOptions")
+	options, err := c.client.GetVersionDimensionOptions(ctx, headers, datasetID, editionID, versionID, dimensionID, q)
+	call.end(err)
+	return options, err
+}
+
+func (c *InstrumentedDatasetAPIClient) GetVersionsInBatches(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string, batchSize, maxWorkers int) (datasetApiSdk.VersionsList, error) {
+	ctx, call := c.start(ctx, "GetVersionsInBatches")
+	versions, err := c.client.GetVersionsInBatches(ctx, headers, datasetID, edition, batchSize, maxWorkers)
+	call.endBatch(len(versions.Items), err)
+	return versions, err
+}
+
+func (c *InstrumentedDatasetAPIClient) PutDataset(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
+	ctx, call := c.start(ctx, "PutDataset")
+	err := c.client.PutDataset(ctx, headers, datasetID, d)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedDatasetAPIClient) PutMetadata(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, metadata datasetApiModels.EditableMetadata, versionEtag string) error {
+	ctx, call := c.start(ctx, "PutMetadata")
+	err := c.client.PutMetadata(ctx, headers, datasetID, edition, version, metadata, versionEtag)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedDatasetAPIClient) PutVersion(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error) {
+	ctx, call := c.start(ctx, "PutVersion")
+	v, err := c.client.PutVersion(ctx, headers, datasetID, editionID, versionID, version)
+	call.end(err)
+	return v, err
+}
+
+func (c *InstrumentedDatasetAPIClient) PutVersionState(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, state string) error {
+	ctx, call := c.start(ctx, "PutVersionState")
+	err := c.client.PutVersionState(ctx, headers, datasetID, editionID, versionID, state)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedDatasetAPIClient) PutInstance(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (string, error) {
+	ctx, call := c.start(ctx, "PutInstance")
+	eTag, err := c.client.PutInstance(ctx, headers, instanceID, i, ifMatch)
+	call.end(err)
+	return eTag, err
+}
+
+// InstrumentedZebedeeClient wraps a ZebedeeClient, recording metrics and a span for every call made to zebedee
+type InstrumentedZebedeeClient struct {
+	client   ZebedeeClient
+	observer UpstreamObserver
+}
+
+// NewInstrumentedZebedeeClient returns a ZebedeeClient that records metrics and spans for the calls made through zc
+func NewInstrumentedZebedeeClient(zc ZebedeeClient, o UpstreamObserver) *InstrumentedZebedeeClient {
+	return &InstrumentedZebedeeClient{client: zc, observer: o}
+}
+
+func (c *InstrumentedZebedeeClient) start(ctx context.Context, method string) (context.Context, *upstreamCall) {
+	return startUpstreamCall(ctx, c.observer, metrics.UpstreamZebedee, method)
+}
+
+func (c *InstrumentedZebedeeClient) GetCollection(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+	ctx, call := c.start(ctx, "GetCollection")
+	collection, err := c.client.GetCollection(ctx, userAccessToken, collectionID)
+	call.end(err)
+	return collection, err
+}
+
+func (c *InstrumentedZebedeeClient) PutDatasetInCollection(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+	ctx, call := c.start(ctx, "PutDatasetInCollection")
+	err := c.client.PutDatasetInCollection(ctx, userAccessToken, collectionID, lang, datasetID, state)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedZebedeeClient) PutDatasetVersionInCollection(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+	ctx, call := c.start(ctx, "PutDatasetVersionInCollection")
+	err := c.client.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, lang, datasetID, edition, version, state)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedZebedeeClient) DeleteDatasetFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID string) error {
+	ctx, call := c.start(ctx, "DeleteDatasetFromCollection")
+	err := c.client.DeleteDatasetFromCollection(ctx, userAccessToken, collectionID, datasetID)
+	call.end(err)
+	return err
+}
+
+func (c *InstrumentedZebedeeClient) DeleteDatasetVersionFromCollection(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+	ctx, call := c.start(ctx, "DeleteDatasetVersionFromCollection")
+	err := c.client.DeleteDatasetVersionFromCollection(ctx, userAccessToken, collectionID, datasetID, edition, version)
+	call.end(err)
+	return err
+}
+
+// InstrumentedBabbageClient wraps a BabbageClient, recording metrics and a span for every call made to babbage
+type InstrumentedBabbageClient struct {
+	client   BabbageClient
+	observer UpstreamObserver
+}
+
+// NewInstrumentedBabbageClient returns a BabbageClient that records metrics and spans for the calls made through bc
+func NewInstrumentedBabbageClient(bc BabbageClient, o UpstreamObserver) *InstrumentedBabbageClient {
+	return &InstrumentedBabbageClient{client: bc, observer: o}
+}
+
+func (c *InstrumentedBabbageClient) GetTopics(ctx context.Context, userAccessToken string) (babbageclient.TopicsResult, error) {
+	ctx, call := startUpstreamCall(ctx, c.observer, metrics.UpstreamBabbage, "GetTopics")
+	result, err := c.client.GetTopics(ctx, userAccessToken)
+	call.end(err)
+	return result, err
+}
+
+// InstrumentedDownloadClient wraps a DownloadClient, recording metrics and a span for every call made to the download
+// service
+type InstrumentedDownloadClient struct {
+	client   DownloadClient
+	observer UpstreamObserver
+}
+
+// NewInstrumentedDownloadClient returns a DownloadClient that records metrics and spans for the calls made through dl
+func NewInstrumentedDownloadClient(dl DownloadClient, o UpstreamObserver) *InstrumentedDownloadClient {
+	return &InstrumentedDownloadClient{client: dl, observer: o}
+}
+
+func (c *InstrumentedDownloadClient) Open(ctx context.Context, userAccessToken, collectionID, href string) (io.ReadCloser, error) {
+	ctx, call := startUpstreamCall(ctx, c.observer, metrics.UpstreamDownload, "Open")
+	r, err := c.client.Open(ctx, userAccessToken, collectionID, href)
+	call.end(err)
+	return r, err
+}
+
+// InstrumentedIdentityClient wraps an IdentityClient, recording metrics and a span for every call made to the
+// identity API
+type InstrumentedIdentityClient struct {
+	client   IdentityClient
+	observer UpstreamObserver
+}
+
+// NewInstrumentedIdentityClient returns an IdentityClient that records metrics and spans for the calls made through ic
+func NewInstrumentedIdentityClient(ic IdentityClient, o UpstreamObserver) *InstrumentedIdentityClient {
+	return &InstrumentedIdentityClient{client: ic, observer: o}
+}
+
+func (c *InstrumentedIdentityClient) GetUser(ctx context.Context, userAccessToken, userID string) (users.User, error) {
+	ctx, call := startUpstreamCall(ctx, c.observer, metrics.UpstreamIdentity, "GetUser")
+	u, err := c.client.GetUser(ctx, userAccessToken, userID)
+	call.end(err)
+	return u, err
+}
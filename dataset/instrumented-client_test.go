@@ -0,0 +1,91 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type observation struct {
+	name  string
+	items int
+	err   error
+}
+
+type fakeObserver struct {
+	upstream []observation
+	batches  []observation
+}
+
+func (o *fakeObserver) ObserveUpstream(upstream, method string, start time.Time, err error) {
+	o.upstream = append(o.upstream, observation{name: upstream + "/" + method, err: err})
+}
+
+func (o *fakeObserver) ObserveBatch(operation string, start time.Time, items int, err error) {
+	o.batches = append(o.batches, observation{name: operation, items: items, err: err})
+}
+
+func TestUnitInstrumentedDatasetAPIClient(t *testing.T) {
+	ctx := context.Background()
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	headers := datasetApiSdk.Headers{}
+
+	Convey("Given an instrumented dataset API client", t, func() {
+		testErr := errors.New("test dataset API error")
+		var upstreamCtx context.Context
+		mockDatasetClient := &DatasetAPIClientMock{
+			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				upstreamCtx = ctx
+				return datasetApiModels.Version{}, testErr
+			},
+			GetVersionsInBatchesFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string, batchSize, maxWorkers int) (datasetApiSdk.VersionsList, error) {
+				return datasetApiSdk.VersionsList{Items: []datasetApiModels.Version{{}, {}, {}}}, nil
+			},
+		}
+		o := &fakeObserver{}
+		dc := NewInstrumentedDatasetAPIClient(mockDatasetClient, o)
+
+		Convey("When a call fails", func() {
+			_, err := dc.GetVersion(ctx, headers, "cpih01", "time-series", "1")
+
+			Convey("Then the error is returned and observed", func() {
+				So(err, ShouldEqual, testErr)
+				So(o.upstream, ShouldResemble, []observation{{name: metrics.UpstreamDatasetAPI + "/GetVersion", err: testErr}})
+				So(o.batches, ShouldBeEmpty)
+			})
+
+			Convey("Then a client span is passed upstream and ended with an error status", func() {
+				ended := spans.Ended()
+				last := ended[len(ended)-1]
+				So(last.Name(), ShouldEqual, "dataset-api GetVersion")
+				So(last.SpanKind(), ShouldEqual, trace.SpanKindClient)
+				So(last.Status().Code, ShouldEqual, codes.Error)
+				So(trace.SpanContextFromContext(upstreamCtx), ShouldResemble, last.SpanContext())
+			})
+		})
+
+		Convey("When a batched call succeeds", func() {
+			versions, err := dc.GetVersionsInBatches(ctx, headers, "cpih01", "time-series", 10, 2)
+
+			Convey("Then the call and the number of items fetched are observed", func() {
+				So(err, ShouldBeNil)
+				So(versions.Items, ShouldHaveLength, 3)
+				So(o.upstream, ShouldResemble, []observation{{name: metrics.UpstreamDatasetAPI + "/GetVersionsInBatches"}})
+				So(o.batches, ShouldResemble, []observation{{name: "GetVersionsInBatches", items: 3}})
+			})
+		})
+	})
+}
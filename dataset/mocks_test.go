@@ -5,12 +5,17 @@ package dataset
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
 	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
 )
 
 // Ensure, that DatasetAPIClientMock does implement DatasetAPIClient.
@@ -38,6 +43,9 @@ var _ DatasetAPIClient = &DatasetAPIClientMock{}
 //			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, edition string, version string) (datasetApiModels.Version, error) {
 //				panic("mock out the GetVersion method")
 //			},
+//			GetVersionDimensionOptionsFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+//				panic("mock out the GetVersionDimensionOptions method")
+//			},
 //			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, edition string, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
 //				panic("mock out the GetVersionWithHeaders method")
 //			},
@@ -56,6 +64,9 @@ var _ DatasetAPIClient = &DatasetAPIClientMock{}
 //			PutVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error) {
 //				panic("mock out the PutVersion method")
 //			},
+//			PutVersionStateFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, state string) error {
+//				panic("mock out the PutVersionState method")
+//			},
 //		}
 //
 //		// use mockedDatasetAPIClient in code that requires DatasetAPIClient
@@ -78,6 +89,9 @@ type DatasetAPIClientMock struct {
 	// GetVersionFunc mocks the GetVersion method.
 	GetVersionFunc func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, edition string, version string) (datasetApiModels.Version, error)
 
+	// GetVersionDimensionOptionsFunc mocks the GetVersionDimensionOptions method.
+	GetVersionDimensionOptionsFunc func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error)
+
 	// GetVersionWithHeadersFunc mocks the GetVersionWithHeaders method.
 	GetVersionWithHeadersFunc func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, edition string, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error)
 
@@ -96,6 +110,9 @@ type DatasetAPIClientMock struct {
 	// PutVersionFunc mocks the PutVersion method.
 	PutVersionFunc func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error)
 
+	// PutVersionStateFunc mocks the PutVersionState method.
+	PutVersionStateFunc func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, state string) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetDatasetCurrentAndNext holds details about calls to the GetDatasetCurrentAndNext method.
@@ -153,6 +170,23 @@ type DatasetAPIClientMock struct {
 			// Version is the version argument value.
 			Version string
 		}
+		// GetVersionDimensionOptions holds details about calls to the GetVersionDimensionOptions method.
+		GetVersionDimensionOptions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Headers is the headers argument value.
+			Headers datasetApiSdk.Headers
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// EditionID is the editionID argument value.
+			EditionID string
+			// VersionID is the versionID argument value.
+			VersionID string
+			// DimensionID is the dimensionID argument value.
+			DimensionID string
+			// Q is the q argument value.
+			Q *datasetApiSdk.QueryParams
+		}
 		// GetVersionWithHeaders holds details about calls to the GetVersionWithHeaders method.
 		GetVersionWithHeaders []struct {
 			// Ctx is the ctx argument value.
@@ -237,18 +271,35 @@ type DatasetAPIClientMock struct {
 			// Version is the version argument value.
 			Version datasetApiModels.Version
 		}
+		// PutVersionState holds details about calls to the PutVersionState method.
+		PutVersionState []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Headers is the headers argument value.
+			Headers datasetApiSdk.Headers
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// EditionID is the editionID argument value.
+			EditionID string
+			// VersionID is the versionID argument value.
+			VersionID string
+			// State is the state argument value.
+			State string
+		}
 	}
-	lockGetDatasetCurrentAndNext sync.RWMutex
-	lockGetDatasetsInBatches     sync.RWMutex
-	lockGetEdition               sync.RWMutex
-	lockGetEditions              sync.RWMutex
-	lockGetVersion               sync.RWMutex
-	lockGetVersionWithHeaders    sync.RWMutex
-	lockGetVersionsInBatches     sync.RWMutex
-	lockPutDataset               sync.RWMutex
-	lockPutInstance              sync.RWMutex
-	lockPutMetadata              sync.RWMutex
-	lockPutVersion               sync.RWMutex
+	lockGetDatasetCurrentAndNext   sync.RWMutex
+	lockGetDatasetsInBatches       sync.RWMutex
+	lockGetEdition                 sync.RWMutex
+	lockGetEditions                sync.RWMutex
+	lockGetVersion                 sync.RWMutex
+	lockGetVersionDimensionOptions sync.RWMutex
+	lockGetVersionWithHeaders      sync.RWMutex
+	lockGetVersionsInBatches       sync.RWMutex
+	lockPutDataset                 sync.RWMutex
+	lockPutInstance                sync.RWMutex
+	lockPutMetadata                sync.RWMutex
+	lockPutVersion                 sync.RWMutex
+	lockPutVersionState            sync.RWMutex
 }
 
 // GetDatasetCurrentAndNext calls GetDatasetCurrentAndNextFunc.
@@ -471,6 +522,62 @@ func (mock *DatasetAPIClientMock) GetVersionCalls() []struct {
 	return calls
 }
 
+// GetVersionDimensionOptions calls GetVersionDimensionOptionsFunc.
+func (mock *DatasetAPIClientMock) GetVersionDimensionOptions(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, dimensionID string, q *datasetApiSdk.QueryParams) (datasetApiSdk.VersionDimensionOptionsList, error) {
+	if mock.GetVersionDimensionOptionsFunc == nil {
+		panic("DatasetAPIClientMock.GetVersionDimensionOptionsFunc: method is nil but DatasetAPIClient.GetVersionDimensionOptions was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Headers     datasetApiSdk.Headers
+		DatasetID   string
+		EditionID   string
+		VersionID   string
+		DimensionID string
+		Q           *datasetApiSdk.QueryParams
+	}{
+		Ctx:         ctx,
+		Headers:     headers,
+		DatasetID:   datasetID,
+		EditionID:   editionID,
+		VersionID:   versionID,
+		DimensionID: dimensionID,
+		Q:           q,
+	}
+	mock.lockGetVersionDimensionOptions.Lock()
+	mock.calls.GetVersionDimensionOptions = append(mock.calls.GetVersionDimensionOptions, callInfo)
+	mock.lockGetVersionDimensionOptions.Unlock()
+	return mock.GetVersionDimensionOptionsFunc(ctx, headers, datasetID, editionID, versionID, dimensionID, q)
+}
+
+// GetVersionDimensionOptionsCalls gets all the calls that were made to GetVersionDimensionOptions.
+// Check the length with:
+//
+//	len(mockedDatasetAPIClient.GetVersionDimensionOptionsCalls())
+func (mock *DatasetAPIClientMock) GetVersionDimensionOptionsCalls() []struct {
+	Ctx         context.Context
+	Headers     datasetApiSdk.Headers
+	DatasetID   string
+	EditionID   string
+	VersionID   string
+	DimensionID string
+	Q           *datasetApiSdk.QueryParams
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Headers     datasetApiSdk.Headers
+		DatasetID   string
+		EditionID   string
+		VersionID   string
+		DimensionID string
+		Q           *datasetApiSdk.QueryParams
+	}
+	mock.lockGetVersionDimensionOptions.RLock()
+	calls = mock.calls.GetVersionDimensionOptions
+	mock.lockGetVersionDimensionOptions.RUnlock()
+	return calls
+}
+
 // GetVersionWithHeaders calls GetVersionWithHeadersFunc.
 func (mock *DatasetAPIClientMock) GetVersionWithHeaders(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, edition string, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
 	if mock.GetVersionWithHeadersFunc == nil {
@@ -771,6 +878,58 @@ func (mock *DatasetAPIClientMock) PutVersionCalls() []struct {
 	return calls
 }
 
+// PutVersionState calls PutVersionStateFunc.
+func (mock *DatasetAPIClientMock) PutVersionState(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, editionID string, versionID string, state string) error {
+	if mock.PutVersionStateFunc == nil {
+		panic("DatasetAPIClientMock.PutVersionStateFunc: method is nil but DatasetAPIClient.PutVersionState was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Headers   datasetApiSdk.Headers
+		DatasetID string
+		EditionID string
+		VersionID string
+		State     string
+	}{
+		Ctx:       ctx,
+		Headers:   headers,
+		DatasetID: datasetID,
+		EditionID: editionID,
+		VersionID: versionID,
+		State:     state,
+	}
+	mock.lockPutVersionState.Lock()
+	mock.calls.PutVersionState = append(mock.calls.PutVersionState, callInfo)
+	mock.lockPutVersionState.Unlock()
+	return mock.PutVersionStateFunc(ctx, headers, datasetID, editionID, versionID, state)
+}
+
+// PutVersionStateCalls gets all the calls that were made to PutVersionState.
+// Check the length with:
+//
+//	len(mockedDatasetAPIClient.PutVersionStateCalls())
+func (mock *DatasetAPIClientMock) PutVersionStateCalls() []struct {
+	Ctx       context.Context
+	Headers   datasetApiSdk.Headers
+	DatasetID string
+	EditionID string
+	VersionID string
+	State     string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Headers   datasetApiSdk.Headers
+		DatasetID string
+		EditionID string
+		VersionID string
+		State     string
+	}
+	mock.lockPutVersionState.RLock()
+	calls = mock.calls.PutVersionState
+	mock.lockPutVersionState.RUnlock()
+	return calls
+}
+
 // Ensure, that ZebedeeClientMock does implement ZebedeeClient.
 // If this is not the case, regenerate this file with moq.
 var _ ZebedeeClient = &ZebedeeClientMock{}
@@ -781,6 +940,12 @@ var _ ZebedeeClient = &ZebedeeClientMock{}
 //
 //		// make and configure a mocked ZebedeeClient
 //		mockedZebedeeClient := &ZebedeeClientMock{
+//			DeleteDatasetFromCollectionFunc: func(ctx context.Context, userAccessToken string, collectionID string, datasetID string) error {
+//				panic("mock out the DeleteDatasetFromCollection method")
+//			},
+//			DeleteDatasetVersionFromCollectionFunc: func(ctx context.Context, userAccessToken string, collectionID string, datasetID string, edition string, version string) error {
+//				panic("mock out the DeleteDatasetVersionFromCollection method")
+//			},
 //			GetCollectionFunc: func(ctx context.Context, userAccessToken string, collectionID string) (zebedeeclient.Collection, error) {
 //				panic("mock out the GetCollection method")
 //			},
@@ -797,6 +962,12 @@ var _ ZebedeeClient = &ZebedeeClientMock{}
 //
 //	}
 type ZebedeeClientMock struct {
+	// DeleteDatasetFromCollectionFunc mocks the DeleteDatasetFromCollection method.
+	DeleteDatasetFromCollectionFunc func(ctx context.Context, userAccessToken string, collectionID string, datasetID string) error
+
+	// DeleteDatasetVersionFromCollectionFunc mocks the DeleteDatasetVersionFromCollection method.
+	DeleteDatasetVersionFromCollectionFunc func(ctx context.Context, userAccessToken string, collectionID string, datasetID string, edition string, version string) error
+
 	// GetCollectionFunc mocks the GetCollection method.
 	GetCollectionFunc func(ctx context.Context, userAccessToken string, collectionID string) (zebedeeclient.Collection, error)
 
@@ -808,6 +979,32 @@ type ZebedeeClientMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// DeleteDatasetFromCollection holds details about calls to the DeleteDatasetFromCollection method.
+		DeleteDatasetFromCollection []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+		}
+		// DeleteDatasetVersionFromCollection holds details about calls to the DeleteDatasetVersionFromCollection method.
+		DeleteDatasetVersionFromCollection []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// Edition is the edition argument value.
+			Edition string
+			// Version is the version argument value.
+			Version string
+		}
 		// GetCollection holds details about calls to the GetCollection method.
 		GetCollection []struct {
 			// Ctx is the ctx argument value.
@@ -852,9 +1049,107 @@ type ZebedeeClientMock struct {
 			State string
 		}
 	}
-	lockGetCollection                 sync.RWMutex
-	lockPutDatasetInCollection        sync.RWMutex
-	lockPutDatasetVersionInCollection sync.RWMutex
+	lockDeleteDatasetFromCollection        sync.RWMutex
+	lockDeleteDatasetVersionFromCollection sync.RWMutex
+	lockGetCollection                      sync.RWMutex
+	lockPutDatasetInCollection             sync.RWMutex
+	lockPutDatasetVersionInCollection      sync.RWMutex
+}
+
+// DeleteDatasetFromCollection calls DeleteDatasetFromCollectionFunc.
+func (mock *ZebedeeClientMock) DeleteDatasetFromCollection(ctx context.Context, userAccessToken string, collectionID string, datasetID string) error {
+	if mock.DeleteDatasetFromCollectionFunc == nil {
+		panic("ZebedeeClientMock.DeleteDatasetFromCollectionFunc: method is nil but ZebedeeClient.DeleteDatasetFromCollection was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		DatasetID       string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		CollectionID:    collectionID,
+		DatasetID:       datasetID,
+	}
+	mock.lockDeleteDatasetFromCollection.Lock()
+	mock.calls.DeleteDatasetFromCollection = append(mock.calls.DeleteDatasetFromCollection, callInfo)
+	mock.lockDeleteDatasetFromCollection.Unlock()
+	return mock.DeleteDatasetFromCollectionFunc(ctx, userAccessToken, collectionID, datasetID)
+}
+
+// DeleteDatasetFromCollectionCalls gets all the calls that were made to DeleteDatasetFromCollection.
+// Check the length with:
+//
+//	len(mockedZebedeeClient.DeleteDatasetFromCollectionCalls())
+func (mock *ZebedeeClientMock) DeleteDatasetFromCollectionCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	CollectionID    string
+	DatasetID       string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		DatasetID       string
+	}
+	mock.lockDeleteDatasetFromCollection.RLock()
+	calls = mock.calls.DeleteDatasetFromCollection
+	mock.lockDeleteDatasetFromCollection.RUnlock()
+	return calls
+}
+
+// DeleteDatasetVersionFromCollection calls DeleteDatasetVersionFromCollectionFunc.
+func (mock *ZebedeeClientMock) DeleteDatasetVersionFromCollection(ctx context.Context, userAccessToken string, collectionID string, datasetID string, edition string, version string) error {
+	if mock.DeleteDatasetVersionFromCollectionFunc == nil {
+		panic("ZebedeeClientMock.DeleteDatasetVersionFromCollectionFunc: method is nil but ZebedeeClient.DeleteDatasetVersionFromCollection was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		DatasetID       string
+		Edition         string
+		Version         string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		CollectionID:    collectionID,
+		DatasetID:       datasetID,
+		Edition:         edition,
+		Version:         version,
+	}
+	mock.lockDeleteDatasetVersionFromCollection.Lock()
+	mock.calls.DeleteDatasetVersionFromCollection = append(mock.calls.DeleteDatasetVersionFromCollection, callInfo)
+	mock.lockDeleteDatasetVersionFromCollection.Unlock()
+	return mock.DeleteDatasetVersionFromCollectionFunc(ctx, userAccessToken, collectionID, datasetID, edition, version)
+}
+
+// DeleteDatasetVersionFromCollectionCalls gets all the calls that were made to DeleteDatasetVersionFromCollection.
+// Check the length with:
+//
+//	len(mockedZebedeeClient.DeleteDatasetVersionFromCollectionCalls())
+func (mock *ZebedeeClientMock) DeleteDatasetVersionFromCollectionCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	CollectionID    string
+	DatasetID       string
+	Edition         string
+	Version         string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		DatasetID       string
+		Edition         string
+		Version         string
+	}
+	mock.lockDeleteDatasetVersionFromCollection.RLock()
+	calls = mock.calls.DeleteDatasetVersionFromCollection
+	mock.lockDeleteDatasetVersionFromCollection.RUnlock()
+	return calls
 }
 
 // GetCollection calls GetCollectionFunc.
@@ -1080,3 +1375,893 @@ func (mock *BabbageClientMock) GetTopicsCalls() []struct {
 	mock.lockGetTopics.RUnlock()
 	return calls
 }
+
+// Ensure, that AuditRecorderMock does implement AuditRecorder.
+// If this is not the case, regenerate this file with moq.
+var _ AuditRecorder = &AuditRecorderMock{}
+
+// AuditRecorderMock is a mock implementation of AuditRecorder.
+//
+//	func TestSomethingThatUsesAuditRecorder(t *testing.T) {
+//
+//		// make and configure a mocked AuditRecorder
+//		mockedAuditRecorder := &AuditRecorderMock{
+//			HistoryFunc: func(ctx context.Context, datasetID string) ([]audit.Event, error) {
+//				panic("mock out the History method")
+//			},
+//			RecordFunc: func(ctx context.Context, e audit.Event)  {
+//				panic("mock out the Record method")
+//			},
+//		}
+//
+//		// use mockedAuditRecorder in code that requires AuditRecorder
+//		// and then make assertions.
+//
+//	}
+type AuditRecorderMock struct {
+	// HistoryFunc mocks the History method.
+	HistoryFunc func(ctx context.Context, datasetID string) ([]audit.Event, error)
+
+	// RecordFunc mocks the Record method.
+	RecordFunc func(ctx context.Context, e audit.Event)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// History holds details about calls to the History method.
+		History []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+		}
+		// Record holds details about calls to the Record method.
+		Record []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E audit.Event
+		}
+	}
+	lockHistory sync.RWMutex
+	lockRecord  sync.RWMutex
+}
+
+// History calls HistoryFunc.
+func (mock *AuditRecorderMock) History(ctx context.Context, datasetID string) ([]audit.Event, error) {
+	if mock.HistoryFunc == nil {
+		panic("AuditRecorderMock.HistoryFunc: method is nil but AuditRecorder.History was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DatasetID string
+	}{
+		Ctx:       ctx,
+		DatasetID: datasetID,
+	}
+	mock.lockHistory.Lock()
+	mock.calls.History = append(mock.calls.History, callInfo)
+	mock.lockHistory.Unlock()
+	return mock.HistoryFunc(ctx, datasetID)
+}
+
+// HistoryCalls gets all the calls that were made to History.
+// Check the length with:
+//
+//	len(mockedAuditRecorder.HistoryCalls())
+func (mock *AuditRecorderMock) HistoryCalls() []struct {
+	Ctx       context.Context
+	DatasetID string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DatasetID string
+	}
+	mock.lockHistory.RLock()
+	calls = mock.calls.History
+	mock.lockHistory.RUnlock()
+	return calls
+}
+
+// Record calls RecordFunc.
+func (mock *AuditRecorderMock) Record(ctx context.Context, e audit.Event) {
+	if mock.RecordFunc == nil {
+		panic("AuditRecorderMock.RecordFunc: method is nil but AuditRecorder.Record was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		E   audit.Event
+	}{
+		Ctx: ctx,
+		E:   e,
+	}
+	mock.lockRecord.Lock()
+	mock.calls.Record = append(mock.calls.Record, callInfo)
+	mock.lockRecord.Unlock()
+	mock.RecordFunc(ctx, e)
+}
+
+// RecordCalls gets all the calls that were made to Record.
+// Check the length with:
+//
+//	len(mockedAuditRecorder.RecordCalls())
+func (mock *AuditRecorderMock) RecordCalls() []struct {
+	Ctx context.Context
+	E   audit.Event
+} {
+	var calls []struct {
+		Ctx context.Context
+		E   audit.Event
+	}
+	mock.lockRecord.RLock()
+	calls = mock.calls.Record
+	mock.lockRecord.RUnlock()
+	return calls
+}
+
+// Ensure, that FileBackendMock does implement FileBackend.
+// If this is not the case, regenerate this file with moq.
+var _ FileBackend = &FileBackendMock{}
+
+// FileBackendMock is a mock implementation of FileBackend.
+//
+//	func TestSomethingThatUsesFileBackend(t *testing.T) {
+//
+//		// make and configure a mocked FileBackend
+//		mockedFileBackend := &FileBackendMock{
+//			GetFunc: func(ctx context.Context, path string) (upload.File, error) {
+//				panic("mock out the Get method")
+//			},
+//			ListFunc: func(ctx context.Context, prefix string) ([]upload.File, error) {
+//				panic("mock out the List method")
+//			},
+//			OpenFunc: func(ctx context.Context, path string) (io.ReadCloser, error) {
+//				panic("mock out the Open method")
+//			},
+//			UploadFunc: func(ctx context.Context, path string, contentType string, r io.Reader) (upload.File, error) {
+//				panic("mock out the Upload method")
+//			},
+//		}
+//
+//		// use mockedFileBackend in code that requires FileBackend
+//		// and then make assertions.
+//
+//	}
+type FileBackendMock struct {
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, path string) (upload.File, error)
+
+	// ListFunc mocks the List method.
+	ListFunc func(ctx context.Context, prefix string) ([]upload.File, error)
+
+	// OpenFunc mocks the Open method.
+	OpenFunc func(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// UploadFunc mocks the Upload method.
+	UploadFunc func(ctx context.Context, path string, contentType string, r io.Reader) (upload.File, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Path is the path argument value.
+			Path string
+		}
+		// List holds details about calls to the List method.
+		List []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Prefix is the prefix argument value.
+			Prefix string
+		}
+		// Open holds details about calls to the Open method.
+		Open []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Path is the path argument value.
+			Path string
+		}
+		// Upload holds details about calls to the Upload method.
+		Upload []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Path is the path argument value.
+			Path string
+			// ContentType is the contentType argument value.
+			ContentType string
+			// R is the r argument value.
+			R io.Reader
+		}
+	}
+	lockGet    sync.RWMutex
+	lockList   sync.RWMutex
+	lockOpen   sync.RWMutex
+	lockUpload sync.RWMutex
+}
+
+// Get calls GetFunc.
+func (mock *FileBackendMock) Get(ctx context.Context, path string) (upload.File, error) {
+	if mock.GetFunc == nil {
+		panic("FileBackendMock.GetFunc: method is nil but FileBackend.Get was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Path string
+	}{
+		Ctx:  ctx,
+		Path: path,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, path)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedFileBackend.GetCalls())
+func (mock *FileBackendMock) GetCalls() []struct {
+	Ctx  context.Context
+	Path string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Path string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *FileBackendMock) List(ctx context.Context, prefix string) ([]upload.File, error) {
+	if mock.ListFunc == nil {
+		panic("FileBackendMock.ListFunc: method is nil but FileBackend.List was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Prefix string
+	}{
+		Ctx:    ctx,
+		Prefix: prefix,
+	}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	return mock.ListFunc(ctx, prefix)
+}
+
+// ListCalls gets all the calls that were made to List.
+// Check the length with:
+//
+//	len(mockedFileBackend.ListCalls())
+func (mock *FileBackendMock) ListCalls() []struct {
+	Ctx    context.Context
+	Prefix string
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Prefix string
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// Open calls OpenFunc.
+func (mock *FileBackendMock) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	if mock.OpenFunc == nil {
+		panic("FileBackendMock.OpenFunc: method is nil but FileBackend.Open was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Path string
+	}{
+		Ctx:  ctx,
+		Path: path,
+	}
+	mock.lockOpen.Lock()
+	mock.calls.Open = append(mock.calls.Open, callInfo)
+	mock.lockOpen.Unlock()
+	return mock.OpenFunc(ctx, path)
+}
+
+// OpenCalls gets all the calls that were made to Open.
+// Check the length with:
+//
+//	len(mockedFileBackend.OpenCalls())
+func (mock *FileBackendMock) OpenCalls() []struct {
+	Ctx  context.Context
+	Path string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Path string
+	}
+	mock.lockOpen.RLock()
+	calls = mock.calls.Open
+	mock.lockOpen.RUnlock()
+	return calls
+}
+
+// Upload calls UploadFunc.
+func (mock *FileBackendMock) Upload(ctx context.Context, path string, contentType string, r io.Reader) (upload.File, error) {
+	if mock.UploadFunc == nil {
+		panic("FileBackendMock.UploadFunc: method is nil but FileBackend.Upload was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Path        string
+		ContentType string
+		R           io.Reader
+	}{
+		Ctx:         ctx,
+		Path:        path,
+		ContentType: contentType,
+		R:           r,
+	}
+	mock.lockUpload.Lock()
+	mock.calls.Upload = append(mock.calls.Upload, callInfo)
+	mock.lockUpload.Unlock()
+	return mock.UploadFunc(ctx, path, contentType, r)
+}
+
+// UploadCalls gets all the calls that were made to Upload.
+// Check the length with:
+//
+//	len(mockedFileBackend.UploadCalls())
+func (mock *FileBackendMock) UploadCalls() []struct {
+	Ctx         context.Context
+	Path        string
+	ContentType string
+	R           io.Reader
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Path        string
+		ContentType string
+		R           io.Reader
+	}
+	mock.lockUpload.RLock()
+	calls = mock.calls.Upload
+	mock.lockUpload.RUnlock()
+	return calls
+}
+
+// Ensure, that ChunkUploaderMock does implement ChunkUploader.
+// If this is not the case, regenerate this file with moq.
+var _ ChunkUploader = &ChunkUploaderMock{}
+
+// ChunkUploaderMock is a mock implementation of ChunkUploader.
+//
+//	func TestSomethingThatUsesChunkUploader(t *testing.T) {
+//
+//		// make and configure a mocked ChunkUploader
+//		mockedChunkUploader := &ChunkUploaderMock{
+//			HasChunkFunc: func(ctx context.Context, path string, c upload.Chunk) (bool, error) {
+//				panic("mock out the HasChunk method")
+//			},
+//			WriteChunkFunc: func(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error) {
+//				panic("mock out the WriteChunk method")
+//			},
+//		}
+//
+//		// use mockedChunkUploader in code that requires ChunkUploader
+//		// and then make assertions.
+//
+//	}
+type ChunkUploaderMock struct {
+	// HasChunkFunc mocks the HasChunk method.
+	HasChunkFunc func(ctx context.Context, path string, c upload.Chunk) (bool, error)
+
+	// WriteChunkFunc mocks the WriteChunk method.
+	WriteChunkFunc func(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// HasChunk holds details about calls to the HasChunk method.
+		HasChunk []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Path is the path argument value.
+			Path string
+			// C is the c argument value.
+			C upload.Chunk
+		}
+		// WriteChunk holds details about calls to the WriteChunk method.
+		WriteChunk []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Path is the path argument value.
+			Path string
+			// C is the c argument value.
+			C upload.Chunk
+			// Body is the body argument value.
+			Body io.Reader
+			// Check is the check argument value.
+			Check upload.CheckFunc
+		}
+	}
+	lockHasChunk   sync.RWMutex
+	lockWriteChunk sync.RWMutex
+}
+
+// HasChunk calls HasChunkFunc.
+func (mock *ChunkUploaderMock) HasChunk(ctx context.Context, path string, c upload.Chunk) (bool, error) {
+	if mock.HasChunkFunc == nil {
+		panic("ChunkUploaderMock.HasChunkFunc: method is nil but ChunkUploader.HasChunk was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Path string
+		C    upload.Chunk
+	}{
+		Ctx:  ctx,
+		Path: path,
+		C:    c,
+	}
+	mock.lockHasChunk.Lock()
+	mock.calls.HasChunk = append(mock.calls.HasChunk, callInfo)
+	mock.lockHasChunk.Unlock()
+	return mock.HasChunkFunc(ctx, path, c)
+}
+
+// HasChunkCalls gets all the calls that were made to HasChunk.
+// Check the length with:
+//
+//	len(mockedChunkUploader.HasChunkCalls())
+func (mock *ChunkUploaderMock) HasChunkCalls() []struct {
+	Ctx  context.Context
+	Path string
+	C    upload.Chunk
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Path string
+		C    upload.Chunk
+	}
+	mock.lockHasChunk.RLock()
+	calls = mock.calls.HasChunk
+	mock.lockHasChunk.RUnlock()
+	return calls
+}
+
+// WriteChunk calls WriteChunkFunc.
+func (mock *ChunkUploaderMock) WriteChunk(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error) {
+	if mock.WriteChunkFunc == nil {
+		panic("ChunkUploaderMock.WriteChunkFunc: method is nil but ChunkUploader.WriteChunk was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Path  string
+		C     upload.Chunk
+		Body  io.Reader
+		Check upload.CheckFunc
+	}{
+		Ctx:   ctx,
+		Path:  path,
+		C:     c,
+		Body:  body,
+		Check: check,
+	}
+	mock.lockWriteChunk.Lock()
+	mock.calls.WriteChunk = append(mock.calls.WriteChunk, callInfo)
+	mock.lockWriteChunk.Unlock()
+	return mock.WriteChunkFunc(ctx, path, c, body, check)
+}
+
+// WriteChunkCalls gets all the calls that were made to WriteChunk.
+// Check the length with:
+//
+//	len(mockedChunkUploader.WriteChunkCalls())
+func (mock *ChunkUploaderMock) WriteChunkCalls() []struct {
+	Ctx   context.Context
+	Path  string
+	C     upload.Chunk
+	Body  io.Reader
+	Check upload.CheckFunc
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Path  string
+		C     upload.Chunk
+		Body  io.Reader
+		Check upload.CheckFunc
+	}
+	mock.lockWriteChunk.RLock()
+	calls = mock.calls.WriteChunk
+	mock.lockWriteChunk.RUnlock()
+	return calls
+}
+
+// Ensure, that ReleaseCalendarMock does implement ReleaseCalendar.
+// If this is not the case, regenerate this file with moq.
+var _ ReleaseCalendar = &ReleaseCalendarMock{}
+
+// ReleaseCalendarMock is a mock implementation of ReleaseCalendar.
+//
+//	func TestSomethingThatUsesReleaseCalendar(t *testing.T) {
+//
+//		// make and configure a mocked ReleaseCalendar
+//		mockedReleaseCalendar := &ReleaseCalendarMock{
+//			LinkFunc: func(ctx context.Context, userAccessToken string, datasetID string, edition string, version string, releaseID string) (releases.Release, error) {
+//				panic("mock out the Link method")
+//			},
+//			LinkedFunc: func(ctx context.Context, userAccessToken string, datasetID string, edition string, version string) (releases.Link, releases.Release, error) {
+//				panic("mock out the Linked method")
+//			},
+//			UnlinkFunc: func(ctx context.Context, datasetID string, edition string, version string) (releases.Link, error) {
+//				panic("mock out the Unlink method")
+//			},
+//		}
+//
+//		// use mockedReleaseCalendar in code that requires ReleaseCalendar
+//		// and then make assertions.
+//
+//	}
+type ReleaseCalendarMock struct {
+	// LinkFunc mocks the Link method.
+	LinkFunc func(ctx context.Context, userAccessToken string, datasetID string, edition string, version string, releaseID string) (releases.Release, error)
+
+	// LinkedFunc mocks the Linked method.
+	LinkedFunc func(ctx context.Context, userAccessToken string, datasetID string, edition string, version string) (releases.Link, releases.Release, error)
+
+	// UnlinkFunc mocks the Unlink method.
+	UnlinkFunc func(ctx context.Context, datasetID string, edition string, version string) (releases.Link, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Link holds details about calls to the Link method.
+		Link []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// Edition is the edition argument value.
+			Edition string
+			// Version is the version argument value.
+			Version string
+			// ReleaseID is the releaseID argument value.
+			ReleaseID string
+		}
+		// Linked holds details about calls to the Linked method.
+		Linked []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// Edition is the edition argument value.
+			Edition string
+			// Version is the version argument value.
+			Version string
+		}
+		// Unlink holds details about calls to the Unlink method.
+		Unlink []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// DatasetID is the datasetID argument value.
+			DatasetID string
+			// Edition is the edition argument value.
+			Edition string
+			// Version is the version argument value.
+			Version string
+		}
+	}
+	lockLink   sync.RWMutex
+	lockLinked sync.RWMutex
+	lockUnlink sync.RWMutex
+}
+
+// Link calls LinkFunc.
+func (mock *ReleaseCalendarMock) Link(ctx context.Context, userAccessToken string, datasetID string, edition string, version string, releaseID string) (releases.Release, error) {
+	if mock.LinkFunc == nil {
+		panic("ReleaseCalendarMock.LinkFunc: method is nil but ReleaseCalendar.Link was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		DatasetID       string
+		Edition         string
+		Version         string
+		ReleaseID       string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		DatasetID:       datasetID,
+		Edition:         edition,
+		Version:         version,
+		ReleaseID:       releaseID,
+	}
+	mock.lockLink.Lock()
+	mock.calls.Link = append(mock.calls.Link, callInfo)
+	mock.lockLink.Unlock()
+	return mock.LinkFunc(ctx, userAccessToken, datasetID, edition, version, releaseID)
+}
+
+// LinkCalls gets all the calls that were made to Link.
+// Check the length with:
+//
+//	len(mockedReleaseCalendar.LinkCalls())
+func (mock *ReleaseCalendarMock) LinkCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	DatasetID       string
+	Edition         string
+	Version         string
+	ReleaseID       string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		DatasetID       string
+		Edition         string
+		Version         string
+		ReleaseID       string
+	}
+	mock.lockLink.RLock()
+	calls = mock.calls.Link
+	mock.lockLink.RUnlock()
+	return calls
+}
+
+// Linked calls LinkedFunc.
+func (mock *ReleaseCalendarMock) Linked(ctx context.Context, userAccessToken string, datasetID string, edition string, version string) (releases.Link, releases.Release, error) {
+	if mock.LinkedFunc == nil {
+		panic("ReleaseCalendarMock.LinkedFunc: method is nil but ReleaseCalendar.Linked was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		DatasetID       string
+		Edition         string
+		Version         string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		DatasetID:       datasetID,
+		Edition:         edition,
+		Version:         version,
+	}
+	mock.lockLinked.Lock()
+	mock.calls.Linked = append(mock.calls.Linked, callInfo)
+	mock.lockLinked.Unlock()
+	return mock.LinkedFunc(ctx, userAccessToken, datasetID, edition, version)
+}
+
+// LinkedCalls gets all the calls that were made to Linked.
+// Check the length with:
+//
+//	len(mockedReleaseCalendar.LinkedCalls())
+func (mock *ReleaseCalendarMock) LinkedCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	DatasetID       string
+	Edition         string
+	Version         string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		DatasetID       string
+		Edition         string
+		Version         string
+	}
+	mock.lockLinked.RLock()
+	calls = mock.calls.Linked
+	mock.lockLinked.RUnlock()
+	return calls
+}
+
+// Unlink calls UnlinkFunc.
+func (mock *ReleaseCalendarMock) Unlink(ctx context.Context, datasetID string, edition string, version string) (releases.Link, error) {
+	if mock.UnlinkFunc == nil {
+		panic("ReleaseCalendarMock.UnlinkFunc: method is nil but ReleaseCalendar.Unlink was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		DatasetID string
+		Edition   string
+		Version   string
+	}{
+		Ctx:       ctx,
+		DatasetID: datasetID,
+		Edition:   edition,
+		Version:   version,
+	}
+	mock.lockUnlink.Lock()
+	mock.calls.Unlink = append(mock.calls.Unlink, callInfo)
+	mock.lockUnlink.Unlock()
+	return mock.UnlinkFunc(ctx, datasetID, edition, version)
+}
+
+// UnlinkCalls gets all the calls that were made to Unlink.
+// Check the length with:
+//
+//	len(mockedReleaseCalendar.UnlinkCalls())
+func (mock *ReleaseCalendarMock) UnlinkCalls() []struct {
+	Ctx       context.Context
+	DatasetID string
+	Edition   string
+	Version   string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		DatasetID string
+		Edition   string
+		Version   string
+	}
+	mock.lockUnlink.RLock()
+	calls = mock.calls.Unlink
+	mock.lockUnlink.RUnlock()
+	return calls
+}
+
+// Ensure, that DownloadClientMock does implement DownloadClient.
+// If this is not the case, regenerate this file with moq.
+var _ DownloadClient = &DownloadClientMock{}
+
+// DownloadClientMock is a mock implementation of DownloadClient.
+//
+//	func TestSomethingThatUsesDownloadClient(t *testing.T) {
+//
+//		// make and configure a mocked DownloadClient
+//		mockedDownloadClient := &DownloadClientMock{
+//			OpenFunc: func(ctx context.Context, userAccessToken string, collectionID string, href string) (io.ReadCloser, error) {
+//				panic("mock out the Open method")
+//			},
+//		}
+//
+//		// use mockedDownloadClient in code that requires DownloadClient
+//		// and then make assertions.
+//
+//	}
+type DownloadClientMock struct {
+	// OpenFunc mocks the Open method.
+	OpenFunc func(ctx context.Context, userAccessToken string, collectionID string, href string) (io.ReadCloser, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Open holds details about calls to the Open method.
+		Open []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// Href is the href argument value.
+			Href string
+		}
+	}
+	lockOpen sync.RWMutex
+}
+
+// Open calls OpenFunc.
+func (mock *DownloadClientMock) Open(ctx context.Context, userAccessToken string, collectionID string, href string) (io.ReadCloser, error) {
+	if mock.OpenFunc == nil {
+		panic("DownloadClientMock.OpenFunc: method is nil but DownloadClient.Open was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		Href            string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		CollectionID:    collectionID,
+		Href:            href,
+	}
+	mock.lockOpen.Lock()
+	mock.calls.Open = append(mock.calls.Open, callInfo)
+	mock.lockOpen.Unlock()
+	return mock.OpenFunc(ctx, userAccessToken, collectionID, href)
+}
+
+// OpenCalls gets all the calls that were made to Open.
+// Check the length with:
+//
+//	len(mockedDownloadClient.OpenCalls())
+func (mock *DownloadClientMock) OpenCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	CollectionID    string
+	Href            string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		CollectionID    string
+		Href            string
+	}
+	mock.lockOpen.RLock()
+	calls = mock.calls.Open
+	mock.lockOpen.RUnlock()
+	return calls
+}
+
+// Ensure, that IdentityClientMock does implement IdentityClient.
+// If this is not the case, regenerate this file with moq.
+var _ IdentityClient = &IdentityClientMock{}
+
+// IdentityClientMock is a mock implementation of IdentityClient.
+//
+//	func TestSomethingThatUsesIdentityClient(t *testing.T) {
+//
+//		// make and configure a mocked IdentityClient
+//		mockedIdentityClient := &IdentityClientMock{
+//			GetUserFunc: func(ctx context.Context, userAccessToken string, userID string) (users.User, error) {
+//				panic("mock out the GetUser method")
+//			},
+//		}
+//
+//		// use mockedIdentityClient in code that requires IdentityClient
+//		// and then make assertions.
+//
+//	}
+type IdentityClientMock struct {
+	// GetUserFunc mocks the GetUser method.
+	GetUserFunc func(ctx context.Context, userAccessToken string, userID string) (users.User, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetUser holds details about calls to the GetUser method.
+		GetUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserAccessToken is the userAccessToken argument value.
+			UserAccessToken string
+			// UserID is the userID argument value.
+			UserID string
+		}
+	}
+	lockGetUser sync.RWMutex
+}
+
+// GetUser calls GetUserFunc.
+func (mock *IdentityClientMock) GetUser(ctx context.Context, userAccessToken string, userID string) (users.User, error) {
+	if mock.GetUserFunc == nil {
+		panic("IdentityClientMock.GetUserFunc: method is nil but IdentityClient.GetUser was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		UserAccessToken string
+		UserID          string
+	}{
+		Ctx:             ctx,
+		UserAccessToken: userAccessToken,
+		UserID:          userID,
+	}
+	mock.lockGetUser.Lock()
+	mock.calls.GetUser = append(mock.calls.GetUser, callInfo)
+	mock.lockGetUser.Unlock()
+	return mock.GetUserFunc(ctx, userAccessToken, userID)
+}
+
+// GetUserCalls gets all the calls that were made to GetUser.
+// Check the length with:
+//
+//	len(mockedIdentityClient.GetUserCalls())
+func (mock *IdentityClientMock) GetUserCalls() []struct {
+	Ctx             context.Context
+	UserAccessToken string
+	UserID          string
+} {
+	var calls []struct {
+		Ctx             context.Context
+		UserAccessToken string
+		UserID          string
+	}
+	mock.lockGetUser.RLock()
+	calls = mock.calls.GetUser
+	mock.lockGetUser.RUnlock()
+	return calls
+}
@@ -0,0 +1,258 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const csvContentType = "text/csv"
+
+// ImportMetadata is a handler that wraps importMetadata passing in addition arguments
+func ImportMetadata(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		importMetadata(w, r, dc, zc, ar, accessToken, collectionID)
+	})
+}
+
+// importMetadata reads a json or csv file of metadata fields, applies it to the editable metadata of a version and
+// returns the changes it makes. The changes are only saved if the commit query parameter is true, in which case
+// they are written the same way as putEditableMetadata writes them, keeping the dataset and version in their
+// current collection states
+func importMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	commit := req.URL.Query().Get("commit") == "true"
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+		"commit":       commit,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "importMetadata endpoint: error reading body", err, log.Data(logInfo))
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var imported model.MetadataImport
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch contentType {
+	case csvContentType:
+		imported, err = mapper.MetadataImportFromCSV(bytes.NewReader(b))
+	case "", "application/json":
+		err = json.Unmarshal(b, &imported)
+	default:
+		err = fmt.Errorf("content type must be application/json or %s", csvContentType)
+		log.Error(ctx, "unsupported import content type", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "importMetadata endpoint: error reading metadata file", err, log.Data(logInfo))
+		http.Error(w, "error reading metadata file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// a preview does not change the version, so only a commit checks the collection lock
+	var l lockedVersion
+	if commit {
+		var ok bool
+		if l, ok = checkCollectionLock(w, req, dc, headers, datasetID, edition, version); !ok {
+			return
+		}
+	} else {
+		if l, err = getVersionForEdit(ctx, dc, headers, datasetID, edition, version); err != nil {
+			log.Error(ctx, "failed Get dataset and version details", err, log.Data(logInfo))
+			setErrorStatusCode(req, w, err, datasetID)
+			return
+		}
+	}
+
+	// imports edit the metadata being edited, so the next document is used if there is one
+	ds := l.Dataset.Current
+	if l.Dataset.Next != nil {
+		ds = l.Dataset.Next
+	}
+	if ds == nil {
+		log.Error(ctx, "dataset has no current or next document", nil, log.Data(logInfo))
+		http.Error(w, "dataset has no metadata", http.StatusNotFound)
+		return
+	}
+	v := l.Version
+
+	if fieldErrors := validateMetadataImport(imported, v.Dimensions); len(fieldErrors) > 0 {
+		log.Error(ctx, "metadata file is not valid", nil, log.Data{"errors": fieldErrors, "datasetID": datasetID})
+		writeFieldErrors(w, req, "metadata file is not valid", fieldErrors)
+		return
+	}
+
+	before := mapper.PutMetadata(model.EditMetadata{Dataset: *ds, Version: v})
+	after := mapper.ImportMetadata(before, imported)
+
+	changes, err := audit.Diff(before, after, "last_updated")
+	if err != nil {
+		log.Error(ctx, "error comparing metadata", err, log.Data(logInfo))
+		http.Error(w, "error comparing metadata", http.StatusInternalServerError)
+		return
+	}
+
+	if commit {
+		c, err := zc.GetCollection(ctx, userAccessToken, collectionID)
+		if err != nil {
+			log.Error(ctx, "error getting collection", err, log.Data(logInfo))
+			setErrorStatusCode(req, w, err, datasetID)
+			return
+		}
+		datasetState, ok := datasetCollectionState(c, datasetID)
+		if !ok {
+			datasetState = workflow.CollectionStateInProgress
+		}
+		versionState := versionCollectionState(c, datasetID, edition, version)
+
+		if !writeEditableMetadata(w, req, dc, zc, headers, datasetID, edition, version, after, l.VersionETag, datasetState, versionState, logInfo) {
+			return
+		}
+
+		recordAuditDiff(ctx, ar, newAuditEvent(ctx, "import-metadata", userAccessToken, collectionID, datasetID, edition, version), before, after)
+	}
+
+	responseBody, err := json.Marshal(MetadataImportPreview{
+		Committed: commit,
+		Changes:   changes,
+		Metadata:  after,
+	})
+	if err != nil {
+		log.Error(ctx, "error marshalling response", err, log.Data(logInfo))
+		http.Error(w, "error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(responseBody); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "import metadata: request successful", log.Data(logInfo))
+}
+
+// validateMetadataImport checks the fields given in a metadata import, returning an error for each invalid field
+// using the field's json pointer in the import
+func validateMetadataImport(m model.MetadataImport, dimensions []datasetApiModels.Dimension) []model.FieldError {
+	var fieldErrors []model.FieldError
+	add := func(path, message string) {
+		fieldErrors = append(fieldErrors, model.FieldError{Path: path, Message: message})
+	}
+
+	if m.Title != nil && strings.TrimSpace(*m.Title) == "" {
+		add("/title", "must not be empty")
+	}
+
+	for i, keyword := range m.Keywords {
+		if strings.TrimSpace(keyword) == "" {
+			add(fmt.Sprintf("/keywords/%d", i), "must not be empty")
+		}
+	}
+
+	for i, c := range m.Contacts {
+		if c.Name == "" && c.Email == "" && c.Telephone == "" {
+			add(fmt.Sprintf("/contacts/%d", i), "must have a name, email or telephone")
+		}
+		if c.Email != "" {
+			if _, err := mail.ParseAddress(c.Email); err != nil {
+				add(fmt.Sprintf("/contacts/%d/email", i), "must be a valid email address")
+			}
+		}
+	}
+
+	for i, n := range m.UsageNotes {
+		if n.Title == "" {
+			add(fmt.Sprintf("/usage_notes/%d/title", i), "must not be empty")
+		}
+		if n.Note == "" {
+			add(fmt.Sprintf("/usage_notes/%d/note", i), "must not be empty")
+		}
+	}
+
+	for i, a := range m.Alerts {
+		if _, err := mapper.ParseAlertDate(a.Date); err != nil {
+			add(fmt.Sprintf("/alerts/%d/date", i), "must be a date")
+		}
+		if a.Description == "" {
+			add(fmt.Sprintf("/alerts/%d/description", i), "must not be empty")
+		}
+		if !a.Type.IsValid() {
+			add(fmt.Sprintf("/alerts/%d/type", i), "must be one of alert or correction")
+		}
+	}
+
+	for i, imported := range m.Dimensions {
+		if imported.Name == "" || findDimension(dimensions, imported.Name) < 0 {
+			add(fmt.Sprintf("/dimensions/%d/name", i), "must be a dimension of the version")
+		}
+	}
+
+	return fieldErrors
+}
+
+// writeFieldErrors writes a bad request error listing the invalid fields, in the same form as request validation
+// errors
+func writeFieldErrors(w http.ResponseWriter, req *http.Request, message string, fieldErrors []model.FieldError) {
+	writeErrorResponse(w, req, http.StatusBadRequest, message, fieldErrors)
+}
+
+// writeErrorResponse writes an error with the given status, listing the fields that caused it
+func writeErrorResponse(w http.ResponseWriter, req *http.Request, status int, message string, fieldErrors []model.FieldError) {
+	b, err := json.Marshal(model.ErrorResponse{
+		Message:   message,
+		RequestID: request.GetRequestId(req.Context()),
+		Errors:    fieldErrors,
+	})
+	if err != nil {
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(b); err != nil {
+		log.Error(req.Context(), "error writing response", err)
+	}
+}
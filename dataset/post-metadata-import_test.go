@@ -0,0 +1,229 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitImportMetadata(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata/import"
+	const url = "/datasets/cpih01/editions/time-series/versions/2/metadata/import"
+
+	newDatasetClient := func() *DatasetAPIClientMock {
+		return &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID:   datasetID,
+					Next: &datasetApiModels.Dataset{ID: datasetID, Title: "CPIH", Keywords: []string{"inflation"}},
+				}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{
+					CollectionID: "testcollection",
+					Dimensions:   []datasetApiModels.Dimension{{ID: "aggregate", Name: "aggregate", Label: "Aggregate"}},
+				}, datasetApiSdk.ResponseHeaders{ETag: "version-etag"}, nil
+			},
+			GetEditionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition string) (datasetApiModels.Edition, error) {
+				return datasetApiModels.Edition{Edition: edition}, nil
+			},
+			GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{CollectionID: "testcollection"}, nil
+			},
+			PutMetadataFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, metadata datasetApiModels.EditableMetadata, versionEtag string) error {
+				return nil
+			},
+		}
+	}
+
+	newZebedeeClient := func() *ZebedeeClientMock {
+		return &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+	}
+
+	serve := func(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, query, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url+query, bytes.NewBufferString(body))
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		req.Header.Set("Content-Type", contentType)
+		router := mux.NewRouter()
+		router.Path(target).HandlerFunc(ImportMetadata(dc, zc, ar)).Methods(http.MethodPost)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	jsonFile := `{"title":"CPIH (2015=100)","keywords":["inflation","prices"],"dimensions":[{"name":"aggregate","label":"Special aggregate"}]}`
+
+	Convey("test importMetadata", t, func() {
+		Convey("previews the changes a json file makes without saving them", func() {
+			dc := newDatasetClient()
+			zc := newZebedeeClient()
+			ar := newMockAuditRecorder()
+			w := serve(dc, zc, ar, "", "application/json", jsonFile)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var preview MetadataImportPreview
+			So(json.Unmarshal(w.Body.Bytes(), &preview), ShouldBeNil)
+			So(preview.Committed, ShouldBeFalse)
+			So(preview.Metadata.Title, ShouldEqual, "CPIH (2015=100)")
+			So(preview.Changes, ShouldHaveLength, 3)
+			So(preview.Changes[0].Field, ShouldEqual, "dimensions")
+			So(preview.Changes[1].Field, ShouldEqual, "keywords")
+			So(preview.Changes[2].Field, ShouldEqual, "title")
+			So(preview.Changes[2].From, ShouldEqual, "CPIH")
+
+			So(dc.PutMetadataCalls(), ShouldBeEmpty)
+			So(zc.PutDatasetInCollectionCalls(), ShouldBeEmpty)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("previews the fields an import clears", func() {
+			dc := newDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{
+					ID:   datasetID,
+					Next: &datasetApiModels.Dataset{ID: datasetID, Title: "CPIH", Description: "Consumer prices", Keywords: []string{"inflation"}},
+				}, nil
+			}
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "", "application/json", `{"description":"","keywords":[]}`)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var preview MetadataImportPreview
+			So(json.Unmarshal(w.Body.Bytes(), &preview), ShouldBeNil)
+			So(preview.Changes, ShouldResemble, []audit.Change{
+				{Field: "description", From: "Consumer prices", To: nil},
+				{Field: "keywords", From: []interface{}{"inflation"}, To: nil},
+			})
+			So(preview.Metadata.Description, ShouldBeEmpty)
+			So(preview.Metadata.Keywords, ShouldBeEmpty)
+		})
+
+		Convey("reads a csv file", func() {
+			dc := newDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "", "text/csv; charset=utf-8", "field,value\ntitle,CPIH (2015=100)\n")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var preview MetadataImportPreview
+			So(json.Unmarshal(w.Body.Bytes(), &preview), ShouldBeNil)
+			So(preview.Changes, ShouldHaveLength, 1)
+			So(preview.Metadata.Keywords, ShouldResemble, []string{"inflation"})
+		})
+
+		Convey("saves the changes through the dataset api if commit is true", func() {
+			dc := newDatasetClient()
+			zc := newZebedeeClient()
+			ar := newMockAuditRecorder()
+			w := serve(dc, zc, ar, "?commit=true", "application/json", jsonFile)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var preview MetadataImportPreview
+			So(json.Unmarshal(w.Body.Bytes(), &preview), ShouldBeNil)
+			So(preview.Committed, ShouldBeTrue)
+
+			So(dc.PutMetadataCalls(), ShouldHaveLength, 1)
+			So(dc.PutMetadataCalls()[0].VersionEtag, ShouldEqual, "version-etag")
+			So(dc.PutMetadataCalls()[0].Metadata.Title, ShouldEqual, "CPIH (2015=100)")
+			So(dc.PutMetadataCalls()[0].Metadata.Dimensions[0].Label, ShouldEqual, "Special aggregate")
+			So(zc.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+			So(zc.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+
+			So(ar.RecordCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "import-metadata")
+			So(ar.RecordCalls()[0].E.Changes, ShouldHaveLength, 3)
+		})
+
+		Convey("keeps the current collection states and reads the dataset and version once", func() {
+			dc := newDatasetClient()
+			zc := newZebedeeClient()
+			zc.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{
+					ID:              collectionID,
+					Datasets:        []zebedeeclient.CollectionItem{{ID: "cpih01", State: "Complete"}},
+					DatasetVersions: []zebedeeclient.CollectionItem{{ID: "cpih01", Edition: "time-series", Version: "2", State: "Reviewed"}},
+				}, nil
+			}
+			w := serve(dc, zc, newMockAuditRecorder(), "?commit=true", "application/json", jsonFile)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(zc.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "Complete")
+			So(zc.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Reviewed")
+			So(dc.GetDatasetCurrentAndNextCalls(), ShouldHaveLength, 1)
+			So(dc.GetVersionWithHeadersCalls(), ShouldHaveLength, 1)
+		})
+
+		Convey("returns 400 listing the invalid fields of the file", func() {
+			dc := newDatasetClient()
+			body := "field,value\ntitle,\ncontact.1.email,prices\nalert.1.date,yesterday\nalert.1.type,notice\ndimension.geography.label,Geography\n"
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "?commit=true", "text/csv", body)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+			var resp model.ErrorResponse
+			So(json.Unmarshal(w.Body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Message, ShouldEqual, "metadata file is not valid")
+			So(resp.Errors, ShouldResemble, []model.FieldError{
+				{Path: "/title", Message: "must not be empty"},
+				{Path: "/contacts/0/email", Message: "must be a valid email address"},
+				{Path: "/alerts/0/date", Message: "must be a date"},
+				{Path: "/alerts/0/description", Message: "must not be empty"},
+				{Path: "/alerts/0/type", Message: "must be one of alert or correction"},
+				{Path: "/dimensions/0/name", Message: "must be a dimension of the version"},
+			})
+			So(dc.PutMetadataCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 if the file cannot be read", func() {
+			w := serve(newDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), "", "text/csv", "name,value\n")
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldEqual, "error reading metadata file: csv header must be \"field,value\"\n")
+		})
+
+		Convey("returns 415 if the file is not json or csv", func() {
+			w := serve(newDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), "", "application/xml", "<metadata/>")
+
+			So(w.Code, ShouldEqual, http.StatusUnsupportedMediaType)
+		})
+
+		Convey("returns 500 and records nothing if the metadata cannot be saved", func() {
+			dc := newDatasetClient()
+			dc.PutMetadataFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, metadata datasetApiModels.EditableMetadata, versionEtag string) error {
+				return errors.New("dataset api error")
+			}
+			ar := newMockAuditRecorder()
+			w := serve(dc, newZebedeeClient(), ar, "?commit=true", "application/json", jsonFile)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PostVersionState moves a version to a new state in the publishing workflow, updating both the dataset API
+// and the zebedee collection holding the version
+func PostVersionState(dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postVersionState(w, r, dc, zc, ic, ar, accessToken, collectionID)
+	})
+}
+
+func postVersionState(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID": datasetID,
+		"edition":   edition,
+		"version":   version,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "postVersionState endpoint: error reading body", err, log.Data(logInfo))
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var body model.VersionState
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "postVersionState endpoint: error unmarshalling body", err, log.Data(logInfo))
+		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		return
+	}
+
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+	v := l.Version
+
+	logInfo["from"] = v.State
+	logInfo["to"] = body.State
+
+	if err = workflow.CheckTransition(v.State, body.State); err != nil {
+		log.Error(ctx, "version state transition not allowed", err, log.Data(logInfo))
+		status := http.StatusConflict
+		var unknown workflow.ErrUnknownState
+		if errors.As(err, &unknown) && unknown.State == body.State {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	collectionState := workflow.CollectionState(body.State)
+	if workflow.IsReviewed(collectionState) {
+		if err = checkNotSelfReview(ctx, zc, ic, userAccessToken, collectionID, datasetID, edition, version); err != nil {
+			log.Error(ctx, "review check failed", err, log.Data(logInfo))
+			writeReviewError(w, req, err, "/state")
+			return
+		}
+	}
+
+	err = dc.PutVersionState(ctx, headers, datasetID, edition, version, body.State)
+	if err != nil {
+		log.Error(ctx, "error updating version state", err, log.Data(logInfo))
+		http.Error(w, "error updating version state", http.StatusInternalServerError)
+		return
+	}
+
+	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, collectionState)
+	if err != nil {
+		log.Error(ctx, "error updating version state in collection", err, log.Data(logInfo))
+
+		// keep the dataset API in step with the collection by putting the version back to its previous state
+		if rollbackErr := dc.PutVersionState(ctx, headers, datasetID, edition, version, v.State); rollbackErr != nil {
+			log.Error(ctx, "error rolling back version state", rollbackErr, log.Data(logInfo))
+		}
+
+		http.Error(w, "error updating version state in collection", http.StatusInternalServerError)
+		return
+	}
+
+	event := newAuditEvent(ctx, "update-state", userAccessToken, collectionID, datasetID, edition, version)
+	event.Changes = []audit.Change{{Field: "state", From: v.State, To: body.State}}
+	ar.Record(ctx, event)
+
+	responseBody, err := json.Marshal(model.VersionState{
+		State:           body.State,
+		CollectionState: collectionState,
+	})
+	if err != nil {
+		log.Error(ctx, "error marshalling response", err, log.Data(logInfo))
+		http.Error(w, "error marshalling response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(responseBody); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "post version state: request successful", log.Data(logInfo))
+}
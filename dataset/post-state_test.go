@@ -0,0 +1,143 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPostVersionState(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/state"
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/datasets/test-dataset/editions/test-edition/versions/1/state", bytes.NewBufferString(body))
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", testAccessToken("reviewer@ons.gov.uk"))
+		return req
+	}
+
+	Convey("test postVersionState", t, func() {
+		var versionStates []string
+		mockDatasetClient := &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID, CollectionID: "testcollection"}}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "associated"}, datasetApiSdk.ResponseHeaders{}, nil
+			},
+			PutVersionStateFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, state string) error {
+				versionStates = append(versionStates, state)
+				return nil
+			},
+		}
+
+		auditRecorder := newMockAuditRecorder()
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{
+					ID:              collectionID,
+					DatasetVersions: []zebedeeclient.CollectionItem{{ID: "test-dataset", Edition: "test-edition", Version: "1", LastEditedBy: "editor@ons.gov.uk"}},
+				}, nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+
+		Convey("on success", func() {
+			w := doTestRequest(target, newRequest(`{"state":"approved"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldEqual, `{"state":"approved","collection_state":"Reviewed"}`)
+			So(versionStates, ShouldResemble, []string{"approved"})
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Reviewed")
+		})
+
+		Convey("returns 403 when the caller last edited the version", func() {
+			req := newRequest(`{"state":"approved"}`)
+			req.Header.Set("X-Florence-Token", testAccessToken("editor@ons.gov.uk"))
+			w := doTestRequest(target, req, PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusForbidden)
+			So(w.Body.String(), ShouldContainSubstring, `"code":"self_review"`)
+			So(w.Body.String(), ShouldContainSubstring, `"path":"/state"`)
+			So(mockDatasetClient.PutVersionStateCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 when the transition is not allowed", func() {
+			w := doTestRequest(target, newRequest(`{"state":"published"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(w.Body.String(), ShouldEqual, "version cannot move from state \"associated\" to state \"published\"\n")
+			So(mockDatasetClient.PutVersionStateCalls(), ShouldBeEmpty)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 when the version is held by another collection", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "associated", CollectionID: "othercollection"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest(`{"state":"approved"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(w.Body.String(), ShouldEqual, "dataset is locked by collection othercollection\n")
+			So(mockDatasetClient.PutVersionStateCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when the requested state is unknown", func() {
+			w := doTestRequest(target, newRequest(`{"state":"detached"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(mockDatasetClient.PutVersionStateCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when the body is not valid json", func() {
+			w := doTestRequest(target, newRequest(`{`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldEqual, "error unmarshalling body\n")
+		})
+
+		Convey("returns 500 when the dataset API update fails", func() {
+			mockDatasetClient.PutVersionStateFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID, state string) error {
+				return errors.New("test dataset API error")
+			}
+			w := doTestRequest(target, newRequest(`{"state":"approved"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("rolls back the version state when the collection update fails", func() {
+			mockZebedeeClient.PutDatasetVersionInCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return errors.New("test zebedee error")
+			}
+			w := doTestRequest(target, newRequest(`{"state":"approved"}`), PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Body.String(), ShouldEqual, "error updating version state in collection\n")
+			So(versionStates, ShouldResemble, []string{"approved", "associated"})
+		})
+
+		Convey("errors if no headers are passed", func() {
+			req := httptest.NewRequest("POST", "/datasets/test-dataset/editions/test-edition/versions/1/state", bytes.NewBufferString(`{"state":"approved"}`))
+			w := doTestRequest(target, req, PostVersionState(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldEqual, "no user access token header set\n")
+		})
+	})
+}
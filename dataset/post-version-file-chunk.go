@@ -0,0 +1,194 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/csvfile"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PostVersionFileChunk is a handler that wraps postVersionFileChunk passing in addition arguments
+func PostVersionFileChunk(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, cu ChunkUploader, maxCSVSize int64) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postVersionFileChunk(w, r, dc, zc, ar, fb, cu, maxCSVSize, accessToken, collectionID)
+	})
+}
+
+// postVersionFileChunk receives a chunk of a file sent by Resumable.js. Once the last chunk of the file has been
+// received the file is registered as a distribution of the version, as it is when the file is sent in one request
+func postVersionFileChunk(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, cu ChunkUploader, maxCSVSize int64, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	if err = parseMultipartUpload(req); err != nil {
+		log.Error(ctx, "postVersionFileChunk endpoint: error reading multipart upload", err, log.Data(logInfo))
+		var fileErr fileError
+		if errors.As(err, &fileErr) {
+			http.Error(w, fileErr.Error(), fileErr.status)
+			return
+		}
+		http.Error(w, "error reading multipart upload", http.StatusBadRequest)
+		return
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	chunk, err := parseChunk(req)
+	if err != nil {
+		log.Error(ctx, "postVersionFileChunk endpoint: invalid chunk", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logInfo["identifier"] = chunk.Identifier
+	logInfo["chunk"] = chunk.Number
+
+	part, _, err := req.FormFile("file")
+	if err != nil {
+		log.Error(ctx, "postVersionFileChunk endpoint: no file part", err, log.Data(logInfo))
+		http.Error(w, "the upload must have a file part", http.StatusBadRequest)
+		return
+	}
+	defer part.Close()
+
+	path := upload.VersionPath(datasetID, edition, version, chunk.Filename)
+	logInfo["path"] = path
+
+	// the format is checked with every chunk, so that an upload of a file that cannot be used is stopped straight away
+	distribution, err := mapper.Distribution(upload.File{Path: path}, "")
+	if err != nil {
+		log.Error(ctx, "postVersionFileChunk endpoint: file cannot be a distribution", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	isCSV := distribution.Format == datasetApiModels.DistributionFormatCSV
+
+	// the collection can have changed while the chunks were being sent, so the lock is checked, and a csv file
+	// validated, once the file is complete and before it replaces any file already at its path
+	check := func(ctx context.Context, r io.Reader) error {
+		if _, err := getCollectionLock(ctx, dc, headers, collectionID, datasetID, edition, version); err != nil {
+			return err
+		}
+		if !isCSV {
+			return nil
+		}
+		fieldErrors, err := csvfile.Validate(r, maxCSVSize)
+		if err != nil {
+			return err
+		}
+		if len(fieldErrors) > 0 {
+			return csvFileError(fieldErrors)
+		}
+		return nil
+	}
+
+	file, err := cu.WriteChunk(ctx, path, chunk, part, check)
+	if err != nil {
+		log.Error(ctx, "error writing chunk", err, log.Data(logInfo))
+		writeChunkError(w, req, err, datasetID)
+		return
+	}
+
+	if file.State == upload.StateCreated {
+		b, err := json.Marshal(VersionFile{File: file})
+		if err != nil {
+			log.Error(ctx, "error marshalling version file to json", err, log.Data(logInfo))
+			http.Error(w, "error marshalling version file to json", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if _, err = w.Write(b); err != nil {
+			log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		}
+		return
+	}
+
+	log.Info(ctx, "post version file chunk: upload complete", log.Data(logInfo))
+
+	attachVersionFile(w, req, dc, zc, ar, fb, headers, datasetID, edition, version, file, req.FormValue("title"), maxCSVSize, true, logInfo)
+}
+
+// parseChunk reads the Resumable.js parameters describing a chunk from the query or form of a request
+func parseChunk(req *http.Request) (upload.Chunk, error) {
+	c := upload.Chunk{
+		Identifier:   req.FormValue("resumableIdentifier"),
+		Filename:     req.FormValue("resumableFilename"),
+		ContentType:  req.FormValue("resumableType"),
+		Checksum:     req.FormValue("resumableChunkChecksum"),
+		FileChecksum: req.FormValue("resumableChecksum"),
+	}
+
+	var err error
+	if c.Number, err = strconv.Atoi(req.FormValue("resumableChunkNumber")); err != nil {
+		return c, fmt.Errorf("resumableChunkNumber must be a number")
+	}
+	if c.TotalChunks, err = strconv.Atoi(req.FormValue("resumableTotalChunks")); err != nil {
+		return c, fmt.Errorf("resumableTotalChunks must be a number")
+	}
+	if c.CurrentSize, err = strconv.ParseInt(req.FormValue("resumableCurrentChunkSize"), 10, 64); err != nil {
+		return c, fmt.Errorf("resumableCurrentChunkSize must be a number")
+	}
+	if c.TotalSize, err = strconv.ParseInt(req.FormValue("resumableTotalSize"), 10, 64); err != nil {
+		return c, fmt.Errorf("resumableTotalSize must be a number")
+	}
+
+	return c, nil
+}
+
+// writeChunkError responds to an error storing a chunk or checking the complete file. Resumable.js sends a chunk
+// again after any status other than 400, 404, 409, 415, 500 and 501, so a chunk that does not match its checksum
+// is reported with 422
+func writeChunkError(w http.ResponseWriter, req *http.Request, err error, datasetID string) {
+	var csvErr csvFileError
+	var locked ErrCollectionLocked
+	var cliErr ClientError
+	switch {
+	case errors.As(err, &csvErr):
+		writeFieldErrors(w, req, csvErr.Error(), csvErr)
+	case errors.As(err, &locked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &cliErr):
+		setErrorStatusCode(req, w, err, datasetID)
+	case errors.Is(err, upload.ErrInvalidChunk), errors.Is(err, upload.ErrFileChecksum):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, upload.ErrUploadMismatch):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, upload.ErrChunkChecksum):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, "error storing chunk", http.StatusInternalServerError)
+	}
+}
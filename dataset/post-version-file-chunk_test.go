@@ -0,0 +1,205 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const versionFileChunksTarget = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files/chunks"
+
+// chunkFields returns the Resumable.js parameters of the first of two chunks of cpih.csv
+func chunkFields() map[string]string {
+	return map[string]string{
+		"resumableIdentifier":       "10-cpihcsv",
+		"resumableFilename":         "cpih.csv",
+		"resumableType":             "text/csv",
+		"resumableChunkNumber":      "1",
+		"resumableChunkSize":        "5",
+		"resumableCurrentChunkSize": "5",
+		"resumableTotalSize":        "10",
+		"resumableTotalChunks":      "2",
+	}
+}
+
+func TestUnitPostVersionFileChunk(t *testing.T) {
+	t.Parallel()
+
+	newZebedeeClient := func() *ZebedeeClientMock {
+		return &ZebedeeClientMock{
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+	}
+
+	// newChunkUploader returns a ChunkUploader that completes the upload of content when state is uploaded, passing
+	// it to the check as Resumable does
+	newChunkUploader := func(state, content string) *ChunkUploaderMock {
+		return &ChunkUploaderMock{
+			WriteChunkFunc: func(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error) {
+				if state == upload.StateUploaded && check != nil {
+					if err := check(ctx, strings.NewReader(content)); err != nil {
+						return upload.File{}, err
+					}
+				}
+				return upload.File{Path: path, ContentType: c.ContentType, SizeInBytes: c.TotalSize, State: state, ScanState: upload.ScanSkipped}, nil
+			},
+		}
+	}
+
+	serve := func(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, cu ChunkUploader, fields map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for k, v := range fields {
+			So(mw.WriteField(k, v), ShouldBeNil)
+		}
+		part, err := mw.CreateFormFile("file", "blob")
+		So(err, ShouldBeNil)
+		_, err = part.Write([]byte("a,b\n1"))
+		So(err, ShouldBeNil)
+		So(mw.Close(), ShouldBeNil)
+
+		req := httptest.NewRequest(http.MethodPost, "/datasets/cpih01/editions/time-series/versions/2/files/chunks", &body)
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		router := mux.NewRouter()
+		fb := &FileBackendMock{
+			OpenFunc: func(ctx context.Context, path string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("a,b\n1,2\n")), nil
+			},
+		}
+		router.Path(versionFileChunksTarget).HandlerFunc(PostVersionFileChunk(dc, zc, ar, fb, cu, 1024)).Methods(http.MethodPost)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Convey("test postVersionFileChunk", t, func() {
+		Convey("stores a chunk and returns the file in the created state until the upload is complete", func() {
+			dc := newVersionFilesDatasetClient()
+			cu := newChunkUploader(upload.StateCreated, "")
+			fields := chunkFields()
+			fields["resumableChunkChecksum"] = "ABC"
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), cu, fields)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(cu.WriteChunkCalls(), ShouldHaveLength, 1)
+			call := cu.WriteChunkCalls()[0]
+			So(call.Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+			So(call.C, ShouldResemble, upload.Chunk{
+				Identifier:  "10-cpihcsv",
+				Filename:    "cpih.csv",
+				ContentType: "text/csv",
+				Number:      1,
+				CurrentSize: 5,
+				TotalSize:   10,
+				TotalChunks: 2,
+				Checksum:    "ABC",
+			})
+
+			var vf VersionFile
+			So(json.Unmarshal(w.Body.Bytes(), &vf), ShouldBeNil)
+			So(vf.State, ShouldEqual, upload.StateCreated)
+			So(vf.Distribution, ShouldBeNil)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("registers the file as a distribution once the last chunk is received", func() {
+			dc := newVersionFilesDatasetClient()
+			ar := newMockAuditRecorder()
+			fields := chunkFields()
+			fields["title"] = "CPIH csv"
+			w := serve(dc, newZebedeeClient(), ar, newChunkUploader(upload.StateUploaded, "a,b\n1,2\n"), fields)
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(dc.GetDatasetCurrentAndNextCalls(), ShouldHaveLength, 1)
+			So(dc.PutVersionCalls(), ShouldHaveLength, 1)
+			distributions := *dc.PutVersionCalls()[0].Version.Distributions
+			So(distributions, ShouldHaveLength, 1)
+			So(distributions[0].ByteSize, ShouldEqual, 10)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "post-version-file")
+		})
+
+		Convey("returns 409 when the dataset is locked by another collection once the upload is complete", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{ID: datasetID, Next: &datasetApiModels.Dataset{CollectionID: "othercollection"}}, nil
+			}
+			cu := newChunkUploader(upload.StateUploaded, "a,b\n1,2\n")
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), cu, chunkFields())
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(cu.WriteChunkCalls()[0].Check, ShouldNotBeNil)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns the errors in a csv file that is not valid once the upload is complete", func() {
+			dc := newVersionFilesDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), newChunkUploader(upload.StateUploaded, "a,b\n1\n"), chunkFields())
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			var errs model.ErrorResponse
+			So(json.Unmarshal(w.Body.Bytes(), &errs), ShouldBeNil)
+			So(errs.Errors, ShouldNotBeEmpty)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 without storing the chunk of a file that cannot be a distribution", func() {
+			cu := newChunkUploader(upload.StateCreated, "")
+			fields := chunkFields()
+			fields["resumableFilename"] = "cpih.docx"
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), cu, fields)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(cu.WriteChunkCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when a chunk parameter is missing", func() {
+			cu := newChunkUploader(upload.StateCreated, "")
+			fields := chunkFields()
+			delete(fields, "resumableTotalSize")
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), cu, fields)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(cu.WriteChunkCalls(), ShouldBeEmpty)
+		})
+
+		Convey("reports errors storing the chunk with a status Resumable.js acts on", func() {
+			for err, status := range map[error]int{
+				fmt.Errorf("%w: too large", upload.ErrInvalidChunk): http.StatusBadRequest,
+				upload.ErrFileChecksum:                              http.StatusBadRequest,
+				upload.ErrUploadMismatch:                            http.StatusConflict,
+				upload.ErrChunkChecksum:                             http.StatusUnprocessableEntity,
+				io.ErrUnexpectedEOF:                                 http.StatusInternalServerError,
+			} {
+				cu := newChunkUploader(upload.StateCreated, "")
+				cu.WriteChunkFunc = func(ctx context.Context, path string, c upload.Chunk, body io.Reader, check upload.CheckFunc) (upload.File, error) {
+					return upload.File{}, err
+				}
+				w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), cu, chunkFields())
+
+				So(w.Code, ShouldEqual, status)
+			}
+		})
+	})
+}
@@ -0,0 +1,311 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/csvfile"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	multipartContentType = "multipart/form-data"
+	// multipartMemory is how much of a multipart upload is held in memory, the rest is written to temporary files
+	multipartMemory = 32 << 20
+)
+
+// PostVersionFile is a handler that wraps postVersionFile passing in addition arguments
+func PostVersionFile(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, maxCSVSize int64) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		postVersionFile(w, r, dc, zc, ar, fb, maxCSVSize, accessToken, collectionID)
+	})
+}
+
+// postVersionFile attaches a file to a version of a static dataset, registering it as one of the version's
+// distributions. The file is either sent as a multipart upload, which is stored in the file backend, or is a json
+// reference to a file that has already been uploaded to the backend. Csv files must pass validation of their structure,
+// and be no larger than maxCSVSize bytes
+func postVersionFile(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, maxCSVSize int64, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType != multipartContentType && contentType != "" && contentType != "application/json" {
+		err = fmt.Errorf("content type must be application/json or %s", multipartContentType)
+		log.Error(ctx, "unsupported file content type", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if _, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version); !ok {
+		return
+	}
+
+	var file upload.File
+	var title string
+	if contentType == multipartContentType {
+		file, title, err = uploadVersionFile(req, fb, datasetID, edition, version, maxCSVSize)
+	} else {
+		file, title, err = getReferencedFile(req, fb)
+	}
+	logInfo["path"] = file.Path
+	if err != nil {
+		log.Error(ctx, "postVersionFile endpoint: error getting file", err, log.Data(logInfo))
+		var csvErr csvFileError
+		if errors.As(err, &csvErr) {
+			writeFieldErrors(w, req, csvErr.Error(), csvErr)
+			return
+		}
+		var fileErr fileError
+		if errors.As(err, &fileErr) {
+			http.Error(w, fileErr.Error(), fileErr.status)
+			return
+		}
+		http.Error(w, "error storing file", http.StatusInternalServerError)
+		return
+	}
+
+	// a multipart upload has been validated before it was stored
+	validated := contentType == multipartContentType
+	attachVersionFile(w, req, dc, zc, ar, fb, headers, datasetID, edition, version, file, title, maxCSVSize, validated, logInfo)
+}
+
+// attachVersionFile registers a file that has been uploaded as a distribution of a version, responding with the
+// file and its distribution. A csv file is validated first, unless validated is set, and is not registered if it
+// has any errors
+func attachVersionFile(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, headers datasetApiSdk.Headers, datasetID, edition, version string, file upload.File, title string, maxCSVSize int64, validated bool, logInfo map[string]interface{}) {
+	ctx := req.Context()
+
+	if !file.Ready() {
+		status, msg := http.StatusConflict, "the file has not finished uploading"
+		if file.ScanState == upload.ScanInfected {
+			status, msg = http.StatusBadRequest, "the file failed the virus scan"
+		}
+		log.Error(ctx, "attachVersionFile: file is not ready", nil, log.Data(logInfo))
+		http.Error(w, msg, status)
+		return
+	}
+
+	distribution, err := mapper.Distribution(file, title)
+	if err != nil {
+		log.Error(ctx, "attachVersionFile: file cannot be a distribution", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if distribution.Format == datasetApiModels.DistributionFormatCSV && !validated {
+		fieldErrors, err := validateCSVFile(ctx, fb, file.Path, maxCSVSize)
+		if err != nil {
+			log.Error(ctx, "error validating csv file", err, log.Data(logInfo))
+			http.Error(w, "error validating csv file", http.StatusInternalServerError)
+			return
+		}
+		if len(fieldErrors) > 0 {
+			logInfo["errors"] = fieldErrors
+			log.Error(ctx, "csv file is not valid", nil, log.Data(logInfo))
+			writeFieldErrors(w, req, "csv file is not valid", fieldErrors)
+			return
+		}
+	}
+
+	v, err := dc.GetVersion(ctx, headers, datasetID, edition, version)
+	if err != nil {
+		log.Error(ctx, "failed Get version details", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+
+	distributions := mapper.AddDistribution(v.Distributions, distribution)
+	_, err = dc.PutVersion(ctx, headers, datasetID, edition, version, datasetApiModels.Version{Distributions: &distributions})
+	if err != nil {
+		log.Error(ctx, "error registering distribution", err, log.Data(logInfo))
+		http.Error(w, "error registering distribution", http.StatusInternalServerError)
+		return
+	}
+
+	err = zc.PutDatasetInCollection(ctx, headers.AccessToken, headers.CollectionID, "", datasetID, workflow.CollectionStateInProgress)
+	if err != nil {
+		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
+		http.Error(w, "error adding dataset to collection", http.StatusInternalServerError)
+		return
+	}
+
+	err = zc.PutDatasetVersionInCollection(ctx, headers.AccessToken, headers.CollectionID, "", datasetID, edition, version, workflow.CollectionStateInProgress)
+	if err != nil {
+		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
+		http.Error(w, "error adding version to collection", http.StatusInternalServerError)
+		return
+	}
+
+	var before []datasetApiModels.Distribution
+	if v.Distributions != nil {
+		before = *v.Distributions
+	}
+	recordAuditDiff(ctx, ar, newAuditEvent(ctx, "post-version-file", headers.AccessToken, headers.CollectionID, datasetID, edition, version), before, distributions)
+
+	b, err := json.Marshal(VersionFile{File: file, Distribution: &distribution})
+	if err != nil {
+		log.Error(ctx, "error marshalling version file to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling version file to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "attach version file: request successful", log.Data(logInfo))
+}
+
+// validateCSVFile checks the structure of a csv file in the file backend, returning the errors found in it
+func validateCSVFile(ctx context.Context, fb FileBackend, path string, maxCSVSize int64) ([]model.FieldError, error) {
+	r, err := fb.Open(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return csvfile.Validate(r, maxCSVSize)
+}
+
+// fileError is an error with the file sent in a request, which is reported to the caller with its status
+type fileError struct {
+	status int
+	msg    string
+}
+
+func (e fileError) Error() string {
+	return e.msg
+}
+
+// csvFileError holds the errors found in a csv file sent in a request
+type csvFileError []model.FieldError
+
+func (e csvFileError) Error() string {
+	return "csv file is not valid"
+}
+
+// uploadVersionFile stores the file sent in the file part of a multipart upload, returning it with the title sent in
+// the title part. A csv file is validated before it is stored, so that an invalid file never replaces the file at its
+// path, and a csvFileError is returned if it has any errors
+func uploadVersionFile(req *http.Request, fb FileBackend, datasetID, edition, version string, maxCSVSize int64) (upload.File, string, error) {
+	if err := parseMultipartUpload(req); err != nil {
+		return upload.File{}, "", err
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	part, header, err := req.FormFile("file")
+	if err != nil {
+		return upload.File{}, "", fileError{http.StatusBadRequest, "the upload must have a file part"}
+	}
+	defer part.Close()
+
+	path := upload.VersionPath(datasetID, edition, version, header.Filename)
+
+	// the format is checked before the file is stored, so that a file that cannot be used is not kept
+	distribution, err := mapper.Distribution(upload.File{Path: path}, "")
+	if err != nil {
+		return upload.File{Path: path}, "", fileError{http.StatusBadRequest, err.Error()}
+	}
+
+	if distribution.Format == datasetApiModels.DistributionFormatCSV {
+		fieldErrors, err := csvfile.Validate(part, maxCSVSize)
+		if err != nil {
+			return upload.File{Path: path}, "", err
+		}
+		if len(fieldErrors) > 0 {
+			return upload.File{Path: path}, "", csvFileError(fieldErrors)
+		}
+		if _, err = part.Seek(0, io.SeekStart); err != nil {
+			return upload.File{Path: path}, "", err
+		}
+	}
+
+	file, err := fb.Upload(req.Context(), path, header.Header.Get("Content-Type"), part)
+	if err != nil {
+		return upload.File{Path: path}, "", err
+	}
+
+	return file, req.FormValue("title"), nil
+}
+
+// parseMultipartUpload reads the parts of a multipart upload, returning a fileError if the upload cannot be read or is
+// larger than the validation middleware allows
+func parseMultipartUpload(req *http.Request) error {
+	err := req.ParseMultipartForm(multipartMemory)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fileError{http.StatusRequestEntityTooLarge, fmt.Sprintf("the upload must not be larger than %d bytes", tooLarge.Limit)}
+	}
+	if err != nil {
+		return fileError{http.StatusBadRequest, "error reading multipart upload: " + err.Error()}
+	}
+	return nil
+}
+
+// getReferencedFile reads a json reference to a file that has already been uploaded and gets its state from the
+// file backend
+func getReferencedFile(req *http.Request, fb FileBackend) (upload.File, string, error) {
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return upload.File{}, "", fileError{http.StatusBadRequest, "error reading body"}
+	}
+
+	var ref model.VersionFileReference
+	if err = json.Unmarshal(b, &ref); err != nil {
+		return upload.File{}, "", fileError{http.StatusBadRequest, "error unmarshalling body"}
+	}
+
+	path, err := upload.CleanPath(ref.Path)
+	if err != nil {
+		return upload.File{Path: ref.Path}, "", fileError{http.StatusBadRequest, err.Error()}
+	}
+
+	file, err := fb.Get(req.Context(), path)
+	if errors.Is(err, upload.ErrNotFound) {
+		return upload.File{Path: path}, "", fileError{http.StatusBadRequest, fmt.Sprintf("no file has been uploaded to %s", path)}
+	}
+	if err != nil {
+		return upload.File{Path: path}, "", err
+	}
+
+	return file, ref.Title, nil
+}
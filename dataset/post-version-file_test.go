@@ -0,0 +1,301 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const versionFilesTarget = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files"
+
+func newVersionFilesDatasetClient() *DatasetAPIClientMock {
+	existing := []datasetApiModels.Distribution{{Title: "CPIH csv", Format: datasetApiModels.DistributionFormatCSV, DownloadURL: "/cpih01/time-series/2/cpih.csv"}}
+	return &DatasetAPIClientMock{
+		GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+			return datasetApiModels.DatasetUpdate{ID: datasetID, Next: &datasetApiModels.Dataset{CollectionID: "testcollection"}}, nil
+		},
+		GetVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, error) {
+			return datasetApiModels.Version{ID: "version-2", CollectionID: "testcollection", Distributions: &existing}, nil
+		},
+		GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+			return datasetApiModels.Version{ID: "version-2", CollectionID: "testcollection", Distributions: &existing}, datasetApiSdk.ResponseHeaders{ETag: "version-etag"}, nil
+		},
+		PutVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, update datasetApiModels.Version) (datasetApiModels.Version, error) {
+			return update, nil
+		},
+	}
+}
+
+func TestUnitPostVersionFile(t *testing.T) {
+	t.Parallel()
+
+	newZebedeeClient := func() *ZebedeeClientMock {
+		return &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+	}
+
+	const csvContent = "V4_0,mmm-yy,Time\n88.1,Jan-20,Jan-20\n"
+
+	newFileBackend := func(stored upload.File) *FileBackendMock {
+		return &FileBackendMock{
+			UploadFunc: func(ctx context.Context, path, contentType string, r io.Reader) (upload.File, error) {
+				b, err := io.ReadAll(r)
+				if err != nil {
+					return upload.File{}, err
+				}
+				return upload.File{Path: path, ContentType: contentType, SizeInBytes: int64(len(b)), State: upload.StateUploaded, ScanState: upload.ScanSkipped}, nil
+			},
+			GetFunc: func(ctx context.Context, path string) (upload.File, error) {
+				if path != stored.Path {
+					return upload.File{}, upload.ErrNotFound
+				}
+				return stored, nil
+			},
+			OpenFunc: func(ctx context.Context, path string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(csvContent)), nil
+			},
+		}
+	}
+
+	uploaded := upload.File{Path: "cpih01/time-series/2/cpih.xlsx", SizeInBytes: 2048, State: upload.StateUploaded, ScanState: upload.ScanClean}
+
+	serve := func(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, fb FileBackend, contentType string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/datasets/cpih01/editions/time-series/versions/2/files", bytes.NewReader(body))
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		req.Header.Set("Content-Type", contentType)
+		router := mux.NewRouter()
+		router.Path(versionFilesTarget).HandlerFunc(PostVersionFile(dc, zc, ar, fb, 1024)).Methods(http.MethodPost)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	multipartBody := func(filename, title, content string) (string, []byte) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		if title != "" {
+			So(mw.WriteField("title", title), ShouldBeNil)
+		}
+		part, err := mw.CreateFormFile("file", filename)
+		So(err, ShouldBeNil)
+		_, err = part.Write([]byte(content))
+		So(err, ShouldBeNil)
+		So(mw.Close(), ShouldBeNil)
+		return mw.FormDataContentType(), body.Bytes()
+	}
+
+	Convey("test postVersionFile", t, func() {
+		Convey("stores a multipart upload under the version and adds it to the distributions", func() {
+			dc := newVersionFilesDatasetClient()
+			zc := newZebedeeClient()
+			ar := newMockAuditRecorder()
+			fb := newFileBackend(uploaded)
+			contentType, body := multipartBody("../cpih.sdmx", "CPIH sdmx", "sdmx content")
+			w := serve(dc, zc, ar, fb, contentType, body)
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(fb.UploadCalls(), ShouldHaveLength, 1)
+			So(fb.UploadCalls()[0].Path, ShouldEqual, "cpih01/time-series/2/cpih.sdmx")
+
+			var vf VersionFile
+			So(json.Unmarshal(w.Body.Bytes(), &vf), ShouldBeNil)
+			So(vf.Path, ShouldEqual, "cpih01/time-series/2/cpih.sdmx")
+			So(vf.SizeInBytes, ShouldEqual, 12)
+			So(vf.Distribution.Title, ShouldEqual, "CPIH sdmx")
+			So(vf.Distribution.Format, ShouldEqual, datasetApiModels.DistributionFormatSDMX)
+
+			So(dc.PutVersionCalls(), ShouldHaveLength, 1)
+			distributions := *dc.PutVersionCalls()[0].Version.Distributions
+			So(distributions, ShouldHaveLength, 2)
+			So(distributions[0].Title, ShouldEqual, "CPIH csv")
+			So(distributions[1].DownloadURL, ShouldEqual, "/cpih01/time-series/2/cpih.sdmx")
+
+			So(zc.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+			So(ar.RecordCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "post-version-file")
+		})
+
+		Convey("registers a file that has already been uploaded", func() {
+			dc := newVersionFilesDatasetClient()
+			fb := newFileBackend(uploaded)
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), fb, "application/json", []byte(`{"path":"/cpih01/time-series/2/cpih.xlsx"}`))
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(fb.UploadCalls(), ShouldBeEmpty)
+			distributions := *dc.PutVersionCalls()[0].Version.Distributions
+			So(distributions[1].Title, ShouldEqual, "cpih.xlsx")
+			So(distributions[1].ByteSize, ShouldEqual, 2048)
+		})
+
+		Convey("validates a csv file before registering it", func() {
+			csv := upload.File{Path: "cpih01/time-series/2/cpih.csv", State: upload.StateUploaded, ScanState: upload.ScanClean}
+			dc := newVersionFilesDatasetClient()
+			fb := newFileBackend(csv)
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), fb, "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.csv"}`))
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(fb.OpenCalls()[0].Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+
+			Convey("and returns the errors in it without registering it", func() {
+				dc := newVersionFilesDatasetClient()
+				fb := newFileBackend(csv)
+				fb.OpenFunc = func(ctx context.Context, path string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("V4_0,mmm-yy,Time\n88.1,Jan-20\n")), nil
+				}
+				w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), fb, "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.csv"}`))
+
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				var errResp model.ErrorResponse
+				So(json.Unmarshal(w.Body.Bytes(), &errResp), ShouldBeNil)
+				So(errResp.Errors, ShouldResemble, []model.FieldError{{Path: "/lines/2", Message: "the row has 2 columns but the header has 3"}})
+				So(dc.PutVersionCalls(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("returns 500 when a csv file cannot be read to validate it", func() {
+			csv := upload.File{Path: "cpih01/time-series/2/cpih.csv", State: upload.StateUploaded, ScanState: upload.ScanClean}
+			dc := newVersionFilesDatasetClient()
+			fb := newFileBackend(csv)
+			fb.OpenFunc = func(ctx context.Context, path string) (io.ReadCloser, error) {
+				return nil, errors.New("backend error")
+			}
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), fb, "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.csv"}`))
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when the referenced file has not been uploaded", func() {
+			dc := newVersionFilesDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), newFileBackend(uploaded), "application/json", []byte(`{"path":"cpih01/time-series/2/missing.csv"}`))
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 for a path outside the file backend", func() {
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), newFileBackend(uploaded), "application/json", []byte(`{"path":"../secret.csv"}`))
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("returns 400 for a file that failed the virus scan", func() {
+			infected := uploaded
+			infected.ScanState = upload.ScanInfected
+			dc := newVersionFilesDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), newFileBackend(infected), "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.xlsx"}`))
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 for a file that has not finished uploading", func() {
+			created := uploaded
+			created.State = upload.StateCreated
+			dc := newVersionFilesDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), newFileBackend(created), "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.xlsx"}`))
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("validates a multipart csv upload before storing it", func() {
+			fb := newFileBackend(uploaded)
+			contentType, body := multipartBody("cpih.csv", "CPIH csv", csvContent)
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), fb, contentType, body)
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(fb.UploadCalls(), ShouldHaveLength, 1)
+			So(fb.OpenCalls(), ShouldBeEmpty)
+
+			var vf VersionFile
+			So(json.Unmarshal(w.Body.Bytes(), &vf), ShouldBeNil)
+			So(vf.SizeInBytes, ShouldEqual, len(csvContent))
+		})
+
+		Convey("returns 400 and does not replace the stored file for an invalid multipart csv upload", func() {
+			fb := newFileBackend(uploaded)
+			dc := newVersionFilesDatasetClient()
+			contentType, body := multipartBody("cpih.csv", "CPIH csv", "V4_0,mmm-yy\n88.1,Jan-20\n")
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), fb, contentType, body)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldContainSubstring, "csv file is not valid")
+			So(fb.UploadCalls(), ShouldBeEmpty)
+			So(dc.PutVersionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 413 and does not store a multipart upload larger than the middleware allows", func() {
+			fb := newFileBackend(uploaded)
+			contentType, body := multipartBody("cpih.sdmx", "", strings.Repeat("a", 2048))
+			req := httptest.NewRequest(http.MethodPost, "/datasets/cpih01/editions/time-series/versions/2/files", bytes.NewReader(body))
+			req.Header.Set("Collection-Id", "testcollection")
+			req.Header.Set("X-Florence-Token", "testuser")
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			req.Body = http.MaxBytesReader(w, req.Body, 1024)
+			router := mux.NewRouter()
+			router.Path(versionFilesTarget).HandlerFunc(PostVersionFile(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), fb, 1024)).Methods(http.MethodPost)
+			router.ServeHTTP(w, req)
+
+			So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+			So(w.Body.String(), ShouldContainSubstring, "the upload must not be larger than 1024 bytes")
+			So(fb.UploadCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 and does not store a file that cannot be a distribution", func() {
+			fb := newFileBackend(uploaded)
+			contentType, body := multipartBody("cpih.docx", "", "docx content")
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), fb, contentType, body)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(fb.UploadCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 415 for any other content type", func() {
+			fb := newFileBackend(uploaded)
+			w := serve(newVersionFilesDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), fb, "text/csv", []byte("a,b"))
+
+			So(w.Code, ShouldEqual, http.StatusUnsupportedMediaType)
+			So(fb.UploadCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 500 when the distribution cannot be registered", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.PutVersionFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, update datasetApiModels.Version) (datasetApiModels.Version, error) {
+				return datasetApiModels.Version{}, errors.New("dataset api error")
+			}
+			ar := newMockAuditRecorder()
+			w := serve(dc, newZebedeeClient(), ar, newFileBackend(uploaded), "application/json", []byte(`{"path":"cpih01/time-series/2/cpih.xlsx"}`))
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+	})
+}
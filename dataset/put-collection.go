@@ -0,0 +1,254 @@
+package dataset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// errVersionPublished is returned when a published version is moved or removed from a collection
+var errVersionPublished = errors.New("version is published and cannot be moved or removed from a collection")
+
+// PutVersionCollection moves a version from the caller's collection to another collection, keeping the
+// collection IDs of the version, and of the dataset if it is held by the same collection, in the dataset API
+// in line with zebedee
+func PutVersionCollection(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putVersionCollection(w, r, dc, zc, ar, accessToken, collectionID)
+	})
+}
+
+func putVersionCollection(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "putVersionCollection endpoint: error reading body", err, log.Data(logInfo))
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var body model.CollectionMove
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "putVersionCollection endpoint: error unmarshalling body", err, log.Data(logInfo))
+		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		return
+	}
+
+	if body.CollectionID == "" || body.CollectionID == collectionID {
+		err = errors.New("a different target collection ID must be given")
+		log.Error(ctx, "putVersionCollection endpoint: invalid target collection", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logInfo["targetCollectionID"] = body.CollectionID
+
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+
+	if l.Version.State == datasetApiModels.PublishedState {
+		log.Error(ctx, "version is published", errVersionPublished, log.Data(logInfo))
+		http.Error(w, errVersionPublished.Error(), http.StatusConflict)
+		return
+	}
+
+	source, err := zc.GetCollection(ctx, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, "error getting collection", err, log.Data(logInfo))
+		setErrorStatusCode(req, w, err, datasetID)
+		return
+	}
+	state := versionCollectionState(source, datasetID, edition, version)
+	datasetState, datasetInCollection := datasetCollectionState(source, datasetID)
+
+	moveDataset := l.Dataset.Next != nil && l.Dataset.Next.CollectionID == collectionID
+
+	_, err = dc.PutVersion(ctx, headers, datasetID, edition, version, datasetApiModels.Version{CollectionID: body.CollectionID})
+	if err != nil {
+		log.Error(ctx, "error updating version collection ID", err, log.Data(logInfo))
+		http.Error(w, "error updating version", http.StatusInternalServerError)
+		return
+	}
+
+	if moveDataset {
+		err = dc.PutDataset(ctx, headers, datasetID, datasetApiModels.Dataset{CollectionID: body.CollectionID})
+		if err != nil {
+			log.Error(ctx, "error updating dataset collection ID", err, log.Data(logInfo))
+			revertCollectionIDs(req, dc, headers, datasetID, edition, version, collectionID, false)
+			http.Error(w, "error updating dataset", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	m := collectionMove{
+		from:         collectionID,
+		to:           body.CollectionID,
+		datasetID:    datasetID,
+		edition:      edition,
+		version:      version,
+		versionState: state,
+		datasetState: datasetState,
+		moveDataset:  datasetInCollection,
+	}
+	if err = moveCollectionEntries(ctx, zc, userAccessToken, m); err != nil {
+		log.Error(ctx, "error moving collection entries", err, log.Data(logInfo))
+		revertCollectionIDs(req, dc, headers, datasetID, edition, version, collectionID, moveDataset)
+		http.Error(w, "error moving version between collections", http.StatusInternalServerError)
+		return
+	}
+
+	event := newAuditEvent(ctx, "move-collection", userAccessToken, collectionID, datasetID, edition, version)
+	event.Changes = []audit.Change{{Field: "collection_id", From: collectionID, To: body.CollectionID}}
+	if moveDataset {
+		event.Changes = append(event.Changes, audit.Change{Field: "dataset.collection_id", From: collectionID, To: body.CollectionID})
+	}
+	ar.Record(ctx, event)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "put version collection: request successful", log.Data(logInfo))
+}
+
+// versionCollectionState returns the state of a dataset version in a collection, defaulting to in progress
+func versionCollectionState(c zebedeeclient.Collection, datasetID, edition, version string) string {
+	for _, item := range c.DatasetVersions {
+		if item.ID == datasetID && item.Edition == edition && item.Version == version && item.State != "" {
+			return item.State
+		}
+	}
+	return workflow.CollectionStateInProgress
+}
+
+// datasetCollectionState returns the state of a dataset in a collection, and whether the collection holds the
+// dataset at all
+func datasetCollectionState(c zebedeeclient.Collection, datasetID string) (string, bool) {
+	for _, item := range c.Datasets {
+		if item.ID == datasetID {
+			if item.State == "" {
+				return workflow.CollectionStateInProgress, true
+			}
+			return item.State, true
+		}
+	}
+	return "", false
+}
+
+// collectionMove describes the zebedee entries to move from one collection to another
+type collectionMove struct {
+	from         string
+	to           string
+	datasetID    string
+	edition      string
+	version      string
+	versionState string
+	datasetState string
+	moveDataset  bool
+}
+
+// moveCollectionEntries adds the version, and the dataset if it is being moved, to the target collection and then
+// removes them from the source collection. If a step fails the steps already made are undone, so both collections
+// are left as they were.
+func moveCollectionEntries(ctx context.Context, zc ZebedeeClient, userAccessToken string, m collectionMove) error {
+	logData := log.Data{"datasetID": m.datasetID, "edition": m.edition, "version": m.version, "from": m.from, "to": m.to}
+
+	var undo []func() error
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				log.Error(ctx, "error rolling back collection move", err, logData)
+			}
+		}
+	}
+
+	err := zc.PutDatasetVersionInCollection(ctx, userAccessToken, m.to, "", m.datasetID, m.edition, m.version, m.versionState)
+	if err != nil {
+		return err
+	}
+	undo = append(undo, func() error {
+		return zc.DeleteDatasetVersionFromCollection(ctx, userAccessToken, m.to, m.datasetID, m.edition, m.version)
+	})
+
+	if m.moveDataset {
+		if err = zc.PutDatasetInCollection(ctx, userAccessToken, m.to, "", m.datasetID, m.datasetState); err != nil {
+			rollback()
+			return err
+		}
+		undo = append(undo, func() error {
+			return zc.DeleteDatasetFromCollection(ctx, userAccessToken, m.to, m.datasetID)
+		})
+	}
+
+	if err = zc.DeleteDatasetVersionFromCollection(ctx, userAccessToken, m.from, m.datasetID, m.edition, m.version); err != nil {
+		rollback()
+		return err
+	}
+	undo = append(undo, func() error {
+		return zc.PutDatasetVersionInCollection(ctx, userAccessToken, m.from, "", m.datasetID, m.edition, m.version, m.versionState)
+	})
+
+	if m.moveDataset {
+		if err = zc.DeleteDatasetFromCollection(ctx, userAccessToken, m.from, m.datasetID); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revertCollectionIDs puts the version's collection ID in the dataset API back after a failed move, and the
+// dataset's too if it was moved with the version
+func revertCollectionIDs(req *http.Request, dc DatasetAPIClient, headers datasetApiSdk.Headers, datasetID, edition, version, collectionID string, datasetMoved bool) {
+	ctx := req.Context()
+	logData := log.Data{"datasetID": datasetID, "edition": edition, "version": version, "collectionID": collectionID}
+	if datasetMoved {
+		if err := dc.PutDataset(ctx, headers, datasetID, datasetApiModels.Dataset{CollectionID: collectionID}); err != nil {
+			log.Error(ctx, "error reverting dataset collection ID", err, logData)
+		}
+	}
+	if _, err := dc.PutVersion(ctx, headers, datasetID, edition, version, datasetApiModels.Version{CollectionID: collectionID}); err != nil {
+		log.Error(ctx, "error reverting version collection ID", err, logData)
+	}
+}
@@ -0,0 +1,242 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPutVersionCollection(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection"
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1/collection", bytes.NewBufferString(body))
+		req.Header.Set("Collection-Id", "source-collection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test putVersionCollection", t, func() {
+		var versionCollectionIDs, datasetCollectionIDs []string
+		datasetCollectionID, versionCollectionID := "source-collection", "source-collection"
+		collectionDatasets := []zebedeeclient.CollectionItem{{ID: "test-dataset", State: "Reviewed"}}
+		mockDatasetClient := &DatasetAPIClientMock{
+			GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+				return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID, CollectionID: datasetCollectionID}}, nil
+			},
+			GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "associated", CollectionID: versionCollectionID}, datasetApiSdk.ResponseHeaders{}, nil
+			},
+			PutVersionFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, editionID, versionID string, version datasetApiModels.Version) (datasetApiModels.Version, error) {
+				versionCollectionIDs = append(versionCollectionIDs, version.CollectionID)
+				versionCollectionID = version.CollectionID
+				return version, nil
+			},
+			PutDatasetFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
+				datasetCollectionIDs = append(datasetCollectionIDs, d.CollectionID)
+				datasetCollectionID = d.CollectionID
+				return nil
+			},
+		}
+
+		mockZebedeeClient := &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{
+					ID:              collectionID,
+					Datasets:        collectionDatasets,
+					DatasetVersions: []zebedeeclient.CollectionItem{{ID: "test-dataset", Edition: "test-edition", Version: "1", State: "Complete"}},
+				}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+			DeleteDatasetFromCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, datasetID string) error {
+				return nil
+			},
+			DeleteDatasetVersionFromCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+				return nil
+			},
+		}
+
+		auditRecorder := newMockAuditRecorder()
+
+		Convey("on success", func() {
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(versionCollectionIDs, ShouldResemble, []string{"target-collection"})
+			So(datasetCollectionIDs, ShouldResemble, []string{"target-collection"})
+
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].CollectionID, ShouldEqual, "target-collection")
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "Complete")
+
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls()[0].CollectionID, ShouldEqual, "source-collection")
+
+			So(mockZebedeeClient.PutDatasetInCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].CollectionID, ShouldEqual, "target-collection")
+			So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].State, ShouldEqual, "Reviewed")
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls()[0].CollectionID, ShouldEqual, "source-collection")
+
+			So(auditRecorder.RecordCalls(), ShouldHaveLength, 1)
+			So(auditRecorder.RecordCalls()[0].E.Action, ShouldEqual, "move-collection")
+			So(auditRecorder.RecordCalls()[0].E.Changes, ShouldHaveLength, 2)
+
+			So(mockDatasetClient.GetDatasetCurrentAndNextCalls(), ShouldHaveLength, 1)
+			So(mockDatasetClient.GetVersionWithHeadersCalls(), ShouldHaveLength, 1)
+		})
+
+		Convey("the moved version can then be written from the target collection", func() {
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			req := newRequest(`{"collection_id":"third-collection"}`)
+			req.Header.Set("Collection-Id", "target-collection")
+			w = doTestRequest(target, req, PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(datasetCollectionID, ShouldEqual, "third-collection")
+			So(versionCollectionID, ShouldEqual, "third-collection")
+		})
+
+		Convey("does not move the dataset when it is held by no collection", func() {
+			datasetCollectionID = ""
+			collectionDatasets = nil
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(datasetCollectionIDs, ShouldBeEmpty)
+			So(mockZebedeeClient.PutDatasetInCollectionCalls(), ShouldBeEmpty)
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls(), ShouldBeEmpty)
+			So(auditRecorder.RecordCalls()[0].E.Changes, ShouldHaveLength, 1)
+		})
+
+		Convey("returns 409 when the version is published", func() {
+			mockDatasetClient.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{State: "published"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(w.Body.String(), ShouldEqual, errVersionPublished.Error()+"\n")
+			So(mockDatasetClient.PutVersionCalls(), ShouldBeEmpty)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("reverts the version when the dataset cannot be moved", func() {
+			mockDatasetClient.PutDatasetFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
+				return errors.New("test dataset api error")
+			}
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(versionCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when no target collection is given", func() {
+			w := doTestRequest(target, newRequest(`{}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldEqual, "a different target collection ID must be given\n")
+		})
+
+		Convey("returns 400 when the target is the current collection", func() {
+			w := doTestRequest(target, newRequest(`{"collection_id":"source-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("reverts the dataset API when adding to the target collection fails", func() {
+			mockZebedeeClient.PutDatasetVersionInCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return errors.New("test zebedee error")
+			}
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(versionCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(datasetCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldBeEmpty)
+			So(auditRecorder.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("reverts zebedee and the dataset API when removing from the source collection fails", func() {
+			mockZebedeeClient.DeleteDatasetVersionFromCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, datasetID, edition, version string) error {
+				if collectionID == "source-collection" {
+					return errors.New("test zebedee error")
+				}
+				return nil
+			}
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(versionCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(datasetCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldHaveLength, 2)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls()[1].CollectionID, ShouldEqual, "target-collection")
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls(), ShouldHaveLength, 1)
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls()[0].CollectionID, ShouldEqual, "target-collection")
+		})
+
+		Convey("puts every entry back when removing the dataset from the source collection fails", func() {
+			mockZebedeeClient.DeleteDatasetFromCollectionFunc = func(ctx context.Context, userAccessToken, collectionID, datasetID string) error {
+				if collectionID == "source-collection" {
+					return errors.New("test zebedee error")
+				}
+				return nil
+			}
+			w := doTestRequest(target, newRequest(`{"collection_id":"target-collection"}`), PutVersionCollection(mockDatasetClient, mockZebedeeClient, auditRecorder), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(versionCollectionIDs, ShouldResemble, []string{"target-collection", "source-collection"})
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls(), ShouldHaveLength, 2)
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[1].CollectionID, ShouldEqual, "source-collection")
+			So(mockZebedeeClient.PutDatasetVersionInCollectionCalls()[1].State, ShouldEqual, "Complete")
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls(), ShouldHaveLength, 2)
+			So(mockZebedeeClient.DeleteDatasetFromCollectionCalls()[1].CollectionID, ShouldEqual, "target-collection")
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls(), ShouldHaveLength, 2)
+			So(mockZebedeeClient.DeleteDatasetVersionFromCollectionCalls()[1].CollectionID, ShouldEqual, "target-collection")
+			So(auditRecorder.RecordCalls(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("test versionCollectionState", t, func() {
+		c := zebedeeclient.Collection{
+			DatasetVersions: []zebedeeclient.CollectionItem{{ID: "test-dataset", Edition: "2021", Version: "1", State: "Reviewed"}},
+		}
+
+		So(versionCollectionState(c, "test-dataset", "2021", "1"), ShouldEqual, "Reviewed")
+		So(versionCollectionState(c, "test-dataset", "2021", "2"), ShouldEqual, "InProgress")
+	})
+
+	Convey("test datasetCollectionState", t, func() {
+		c := zebedeeclient.Collection{Datasets: []zebedeeclient.CollectionItem{{ID: "test-dataset", State: "Complete"}, {ID: "no-state"}}}
+
+		state, ok := datasetCollectionState(c, "test-dataset")
+		So(ok, ShouldBeTrue)
+		So(state, ShouldEqual, "Complete")
+
+		state, ok = datasetCollectionState(c, "no-state")
+		So(ok, ShouldBeTrue)
+		So(state, ShouldEqual, "InProgress")
+
+		_, ok = datasetCollectionState(c, "other-dataset")
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,172 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PutDimension is a handler that wraps putDimension passing in addition arguments
+func PutDimension(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putDimension(w, r, dc, zc, ar, accessToken, collectionID)
+	})
+}
+
+// putDimension updates the editable metadata of a single dimension of a version, leaving its other dimensions as
+// they are. The request must send the instance ETag returned by getDimension in an If-Match header, so that an edit
+// made from a stale copy of the version is rejected
+func putDimension(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+	dimension := vars["dimension"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"dimension":    dimension,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	ifMatch := req.Header.Get("If-Match")
+	if ifMatch == "" {
+		log.Error(ctx, "putDimension endpoint: missing If-Match header", nil, log.Data(logInfo))
+		http.Error(w, "an If-Match header with the version ETag is required", http.StatusPreconditionRequired)
+		return
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "putDimension endpoint: error reading body", err, log.Data(logInfo))
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var body model.EditDimension
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "putDimension endpoint: error unmarshalling body", err, log.Data(logInfo))
+		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		return
+	}
+
+	if body.NumberOfOptions != nil && *body.NumberOfOptions < 0 {
+		log.Error(ctx, "putDimension endpoint: negative number of options", nil, log.Data(logInfo))
+		http.Error(w, "number of options must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+	v := l.Version
+
+	if ifMatch != "*" && ifMatch != l.VersionETag {
+		logInfo["currentETag"] = l.VersionETag
+		log.Error(ctx, "putDimension endpoint: version has changed since it was read", nil, log.Data(logInfo))
+		http.Error(w, "the version has been changed since it was read", http.StatusPreconditionFailed)
+		return
+	}
+
+	i := findDimension(v.Dimensions, dimension)
+	if i < 0 {
+		log.Error(ctx, "dimension not found", nil, log.Data(logInfo))
+		http.Error(w, "dimension not found", http.StatusNotFound)
+		return
+	}
+
+	// the instance update replaces every dimension, so the others are sent back unchanged
+	dimensions := append([]datasetApiModels.Dimension(nil), v.Dimensions...)
+	before := dimensions[i]
+	dimensions[i].Label = body.Label
+	dimensions[i].Description = body.Description
+	dimensions[i].QualityStatementText = body.QualityStatementText
+	dimensions[i].QualityStatementURL = body.QualityStatementURL
+	dimensions[i].NumberOfOptions = body.NumberOfOptions
+
+	instance := datasetApiSdk.UpdateInstance{}
+	instance.InstanceID = v.ID
+	instance.Dimensions = dimensions
+
+	eTag, err := dc.PutInstance(ctx, headers, v.ID, instance, ifMatch)
+	if status := upstreamStatus(err); status == http.StatusConflict || status == http.StatusPreconditionFailed {
+		// the version was changed between being read here and being written by the dataset API
+		log.Error(ctx, "putDimension endpoint: version changed while it was being updated", err, log.Data(logInfo))
+		http.Error(w, "the version has been changed since it was read", http.StatusPreconditionFailed)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error updating dimension", err, log.Data(logInfo))
+		http.Error(w, "error updating dimension", http.StatusInternalServerError)
+		return
+	}
+
+	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, workflow.CollectionStateInProgress)
+	if err != nil {
+		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
+		http.Error(w, "error adding dataset to collection", http.StatusInternalServerError)
+		return
+	}
+
+	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, workflow.CollectionStateInProgress)
+	if err != nil {
+		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
+		http.Error(w, "error adding version to collection", http.StatusInternalServerError)
+		return
+	}
+
+	recordAuditDiff(ctx, ar, newAuditEvent(ctx, "put-dimension", userAccessToken, collectionID, datasetID, edition, version), before, dimensions[i])
+
+	writeDimension(w, req, dimensions[i], eTag, logInfo)
+
+	log.Info(ctx, "put dimension: request successful", log.Data(logInfo))
+}
+
+// upstreamStatus returns the status of a failed request to the dataset API, which is reported either as a ClientError
+// or with the status in the error message. It returns 0 if there is no error or its status is not known.
+func upstreamStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var clientErr ClientError
+	if errors.As(err, &clientErr) {
+		return clientErr.Code()
+	}
+
+	var status int
+	msg := err.Error()
+	if i := strings.Index(msg, "received status "); i >= 0 {
+		if _, scanErr := fmt.Sscanf(msg[i:], "received status %d", &status); scanErr == nil {
+			return status
+		}
+	}
+	return 0
+}
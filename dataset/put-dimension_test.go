@@ -0,0 +1,179 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPutDimension(t *testing.T) {
+	t.Parallel()
+
+	newZebedeeClient := func() *ZebedeeClientMock {
+		return &ZebedeeClientMock{
+			GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+				return zebedeeclient.Collection{ID: collectionID}, nil
+			},
+			PutDatasetInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, state string) error {
+				return nil
+			},
+			PutDatasetVersionInCollectionFunc: func(ctx context.Context, userAccessToken, collectionID, lang, datasetID, edition, version, state string) error {
+				return nil
+			},
+		}
+	}
+
+	serve := func(dc DatasetAPIClient, zc ZebedeeClient, ar AuditRecorder, dimension, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/datasets/cpih01/editions/time-series/versions/2/dimensions/"+dimension, bytes.NewBufferString(body))
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		router := mux.NewRouter()
+		router.Path(dimensionTarget).HandlerFunc(PutDimension(dc, zc, ar)).Methods(http.MethodPut)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"label":"Special aggregate","description":"The goods and services in the basket","quality_statement_text":"Experimental","number_of_options":14}`
+
+	Convey("test putDimension", t, func() {
+		Convey("updates the one dimension, keeping the others, and returns the new ETag", func() {
+			dc := newDimensionDatasetClient()
+			zc := newZebedeeClient()
+			ar := newMockAuditRecorder()
+			w := serve(dc, zc, ar, "aggregate", "instance-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Header().Get("ETag"), ShouldEqual, "new-instance-etag")
+
+			var dim datasetApiModels.Dimension
+			So(json.Unmarshal(w.Body.Bytes(), &dim), ShouldBeNil)
+			So(dim.ID, ShouldEqual, "aggregate")
+			So(dim.Label, ShouldEqual, "Special aggregate")
+
+			So(dc.PutInstanceCalls(), ShouldHaveLength, 1)
+			call := dc.PutInstanceCalls()[0]
+			So(call.InstanceID, ShouldEqual, "instance-1")
+			So(call.IfMatch, ShouldEqual, "instance-etag")
+			So(call.I.Dimensions, ShouldHaveLength, 2)
+			So(call.I.Dimensions[0].Description, ShouldEqual, "The goods and services in the basket")
+			So(call.I.Dimensions[0].QualityStatementText, ShouldEqual, "Experimental")
+			So(*call.I.Dimensions[0].NumberOfOptions, ShouldEqual, 14)
+			So(call.I.Dimensions[1], ShouldResemble, datasetApiModels.Dimension{Name: "time", Label: "Time"})
+
+			So(zc.PutDatasetVersionInCollectionCalls()[0].State, ShouldEqual, "InProgress")
+
+			So(ar.RecordCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "put-dimension")
+			So(ar.RecordCalls()[0].E.Changes, ShouldHaveLength, 4)
+		})
+
+		Convey("does not change the version read from the dataset api", func() {
+			dc := newDimensionDatasetClient()
+			versions := dc.GetVersionWithHeadersFunc
+			var read datasetApiModels.Version
+			dc.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				v, h, err := versions(ctx, headers, datasetID, edition, version)
+				read = v
+				return v, h, err
+			}
+
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "aggregate", "instance-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(read.Dimensions[0].Label, ShouldEqual, "Aggregate")
+		})
+
+		Convey("accepts any ETag if the If-Match header is *", func() {
+			w := serve(newDimensionDatasetClient(), newZebedeeClient(), newMockAuditRecorder(), "aggregate", "*", body)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("returns 428 if there is no If-Match header", func() {
+			dc := newDimensionDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "aggregate", "", body)
+
+			So(w.Code, ShouldEqual, http.StatusPreconditionRequired)
+			So(dc.GetVersionWithHeadersCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 412 if the version has changed since the ETag was read", func() {
+			dc := newDimensionDatasetClient()
+			ar := newMockAuditRecorder()
+			w := serve(dc, newZebedeeClient(), ar, "aggregate", "old-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+			So(dc.PutInstanceCalls(), ShouldBeEmpty)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 404 if the version does not have the dimension", func() {
+			dc := newDimensionDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "geography", "instance-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+			So(dc.PutInstanceCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 if the number of options is negative", func() {
+			dc := newDimensionDatasetClient()
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "aggregate", "instance-etag", `{"number_of_options":-1}`)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(dc.PutInstanceCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 if the version is in another collection", func() {
+			dc := newDimensionDatasetClient()
+			dc.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{CollectionID: "othercollection"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			w := serve(dc, newZebedeeClient(), newMockAuditRecorder(), "aggregate", "instance-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(dc.PutInstanceCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 412 and records nothing if the dataset api rejects the ETag", func() {
+			for _, status := range []int{http.StatusConflict, http.StatusPreconditionFailed} {
+				dc := newDimensionDatasetClient()
+				dc.PutInstanceFunc = func(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (string, error) {
+					return "", fmt.Errorf("did not receive success response. received status %d, response body: instance has been modified", status)
+				}
+				ar := newMockAuditRecorder()
+				w := serve(dc, newZebedeeClient(), ar, "aggregate", "instance-etag", body)
+
+				So(w.Code, ShouldEqual, http.StatusPreconditionFailed)
+				So(ar.RecordCalls(), ShouldBeEmpty)
+			}
+		})
+
+		Convey("returns 500 and records nothing if the instance cannot be updated", func() {
+			dc := newDimensionDatasetClient()
+			dc.PutInstanceFunc = func(ctx context.Context, headers datasetApiSdk.Headers, instanceID string, i datasetApiSdk.UpdateInstance, ifMatch string) (string, error) {
+				return "", errors.New("dataset api error")
+			}
+			ar := newMockAuditRecorder()
+			w := serve(dc, newZebedeeClient(), ar, "aggregate", "instance-etag", body)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+	})
+}
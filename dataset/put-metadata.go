@@ -5,25 +5,26 @@ import (
 	"io"
 	"net/http"
 
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
-	dphandlers "github.com/ONSdigital/dp-net/v3/handlers"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/workflow"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
 )
 
 // PutMetadata updates all the dataset, version and dimension object fields
-func PutMetadata(dc DatasetAPIClient, zc ZebedeeClient) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		putMetadata(w, r, dc, zc, accessToken, collectionID, lang)
+func PutMetadata(dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putMetadata(w, r, dc, zc, ic, ar, accessToken, collectionID, lang)
 	})
 }
 
-func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, userAccessToken, collectionID, lang string) {
+func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder, userAccessToken, collectionID, lang string) {
 	ctx := req.Context()
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -60,6 +61,24 @@ func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 		return
 	}
 
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+
+	if workflow.IsReviewed(body.CollectionState) {
+		if err = checkNotSelfReview(ctx, zc, ic, userAccessToken, collectionID, datasetID, edition, version); err != nil {
+			log.Error(ctx, "review check failed", err, log.Data(logInfo))
+			writeReviewError(w, req, err, "/collection_state")
+			return
+		}
+	}
+
+	before, err := getCurrentMetadata(l)
+	if err != nil {
+		log.Warn(ctx, "failed to get current metadata for audit", log.FormatErrors([]error{err}), log.Data(logInfo))
+	}
+
 	err = dc.PutDataset(ctx, headers, datasetID, body.Dataset)
 	if err != nil {
 		log.Error(ctx, "error updating dataset", err, log.Data(logInfo))
@@ -99,6 +118,13 @@ func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 		return
 	}
 
+	after := auditedMetadata{
+		Dataset:    body.Dataset,
+		Version:    body.Version,
+		Dimensions: body.Dimensions,
+	}
+	recordAuditDiff(ctx, ar, newAuditEvent(ctx, "put-metadata", userAccessToken, collectionID, datasetID, edition, version), before, after)
+
 	responseBody, err := json.Marshal(body)
 	if err != nil {
 		log.Error(ctx, "error marshalling response", err, log.Data(logInfo))
@@ -121,16 +147,16 @@ func putMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient,
 // PutEditableMetadata updates a given list of metadata fields, agreed as being editable for both a dataset and a version object
 // This new endpoint makes a unique call to the dataset api updating only the relevant metadata fields in a transactional way
 // It also calls zebedee to update the collection
-func PutEditableMetadata(dc DatasetAPIClient, zc ZebedeeClient) http.HandlerFunc {
-	return dphandlers.ControllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
-		putEditableMetadata(w, r, dc, zc, accessToken, collectionID)
+func PutEditableMetadata(dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putEditableMetadata(w, r, dc, zc, ic, ar, accessToken, collectionID)
 	})
 }
 
-func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, userAccessToken, collectionID string) {
+func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, ic IdentityClient, ar AuditRecorder, userAccessToken, collectionID string) {
 	ctx := req.Context()
 
-	err := checkAccessTokenAndCollectionHeaders(userAccessToken, collectionID)
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
 	if err != nil {
 		log.Error(ctx, err.Error(), err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -167,37 +193,73 @@ func putEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPI
 		return
 	}
 
-	versionEtag := body.VersionEtag
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+
+	if workflow.IsReviewed(body.CollectionState) {
+		if err = checkNotSelfReview(ctx, zc, ic, userAccessToken, collectionID, datasetID, edition, version); err != nil {
+			log.Error(ctx, "review check failed", err, log.Data(logInfo))
+			writeReviewError(w, req, err, "/collection_state")
+			return
+		}
+	}
+
+	var before *datasetApiModels.EditableMetadata
+	current, err := getCurrentMetadata(l)
+	if err != nil {
+		log.Warn(ctx, "failed to get current metadata for audit", log.FormatErrors([]error{err}), log.Data(logInfo))
+	} else {
+		m := mapper.PutMetadata(model.EditMetadata{Dataset: current.Dataset, Version: current.Version})
+		before = &m
+	}
 
 	editableMetadata := mapper.PutMetadata(body)
 
-	err = dc.PutMetadata(ctx, headers, datasetID, edition, version, editableMetadata, versionEtag)
+	if !writeEditableMetadata(w, req, dc, zc, headers, datasetID, edition, version, editableMetadata, body.VersionEtag, body.CollectionState, body.CollectionState, logInfo) {
+		return
+	}
+
+	recordAuditDiff(ctx, ar, newAuditEvent(ctx, "put-editable-metadata", userAccessToken, collectionID, datasetID, edition, version), before, editableMetadata)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
+		http.Error(w, "failed to write response body", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info(ctx, "put metadata: request successful", log.Data(logInfo))
+}
+
+// writeEditableMetadata saves the editable metadata of a dataset and version to the dataset API, then puts the dataset
+// and version in the caller's collection in the given collection states. It writes an error response and returns
+// false if any of the writes fail.
+func writeEditableMetadata(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, zc ZebedeeClient, headers datasetApiSdk.Headers, datasetID, edition, version string, metadata datasetApiModels.EditableMetadata, versionETag, datasetState, versionState string, logInfo map[string]interface{}) bool {
+	ctx := req.Context()
+
+	err := dc.PutMetadata(ctx, headers, datasetID, edition, version, metadata, versionETag)
 	if err != nil {
 		log.Error(ctx, "error updating metadata", err, log.Data(logInfo))
 		http.Error(w, "error updating metadata", http.StatusInternalServerError)
-		return
+		return false
 	}
 
-	err = zc.PutDatasetInCollection(ctx, userAccessToken, collectionID, "", datasetID, body.CollectionState)
+	err = zc.PutDatasetInCollection(ctx, headers.AccessToken, headers.CollectionID, "", datasetID, datasetState)
 	if err != nil {
 		log.Error(ctx, "error adding dataset to collection", err, log.Data(logInfo))
 		http.Error(w, "error adding dataset to collection", http.StatusInternalServerError)
-		return
+		return false
 	}
 
-	err = zc.PutDatasetVersionInCollection(ctx, userAccessToken, collectionID, "", datasetID, edition, version, body.CollectionState)
+	err = zc.PutDatasetVersionInCollection(ctx, headers.AccessToken, headers.CollectionID, "", datasetID, edition, version, versionState)
 	if err != nil {
 		log.Error(ctx, "error adding version to collection", err, log.Data(logInfo))
 		http.Error(w, "error adding version to collection", http.StatusInternalServerError)
-		return
+		return false
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if _, err = w.Write(b); err != nil {
-		log.Error(ctx, "failed to write response body", err, log.Data(logInfo))
-		http.Error(w, "failed to write response body", http.StatusInternalServerError)
-		return
-	}
-
-	log.Info(ctx, "put metadata: request successful", log.Data(logInfo))
+	return true
 }
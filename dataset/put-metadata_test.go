@@ -10,8 +10,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 	"github.com/gorilla/mux"
 
@@ -28,6 +31,12 @@ func TestUnitPutMetadata(t *testing.T) {
 	Convey("test putMetadata", t, func() {
 		Convey("on success", func() {
 			mockDatasetClient := &DatasetAPIClientMock{
+				GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID}}, nil
+				},
+				GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+					return datasetApiModels.Version{ID: "1"}, datasetApiSdk.ResponseHeaders{}, nil
+				},
 				PutDatasetFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
 					return nil
 				},
@@ -54,16 +63,28 @@ func TestUnitPutMetadata(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser") // needed for the zebedee check
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, newMockAuditRecorder()))
 
 			Convey("returns 200 response", func() {
 				router.ServeHTTP(rec, req)
 				So(rec.Code, ShouldEqual, http.StatusOK)
 			})
+
+			Convey("reads the dataset and version once", func() {
+				router.ServeHTTP(rec, req)
+				So(mockDatasetClient.GetDatasetCurrentAndNextCalls(), ShouldHaveLength, 1)
+				So(mockDatasetClient.GetVersionWithHeadersCalls(), ShouldHaveLength, 1)
+			})
 		})
 
 		Convey("errors if no headers are passed", func() {
 			mockDatasetClient := &DatasetAPIClientMock{
+				GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID}}, nil
+				},
+				GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+					return datasetApiModels.Version{ID: "1"}, datasetApiSdk.ResponseHeaders{}, nil
+				},
 				PutDatasetFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
 					return nil
 				},
@@ -90,7 +111,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				req.Header.Set("X-Florence-Token", "testuser") // needed for the zebedee check
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, newMockAuditRecorder()))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -109,7 +130,7 @@ func TestUnitPutMetadata(t *testing.T) {
 				req.Header.Set("Collection-Id", "testcollection")
 				rec := httptest.NewRecorder()
 				router := mux.NewRouter()
-				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, newMockAuditRecorder()))
 
 				Convey("returns 400 response", func() {
 					router.ServeHTTP(rec, req)
@@ -122,10 +143,45 @@ func TestUnitPutMetadata(t *testing.T) {
 					So(response, ShouldResemble, "no user access token header set\n")
 				})
 			})
+
+			Convey("a service request without a collection id", func() {
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(b))
+				req = req.WithContext(authorisation.WithServiceCaller(req.Context(), authorisation.ServiceCaller{Name: "bulk-metadata-fix", Token: "service-token"}))
+				rec := httptest.NewRecorder()
+				router := mux.NewRouter()
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, newMockAuditRecorder()))
+				router.ServeHTTP(rec, req)
+
+				So(rec.Code, ShouldEqual, http.StatusBadRequest)
+				So(rec.Body.String(), ShouldResemble, "no collection ID header set\n")
+			})
+
+			Convey("a service request with a collection id is audited as the service", func() {
+				ar := newMockAuditRecorder()
+				req := httptest.NewRequest("PUT", "/datasets/test-dataset/editions/test-edition/versions/1", bytes.NewBufferString(b))
+				req.Header.Set("Collection-Id", "testcollection")
+				req = req.WithContext(authorisation.WithServiceCaller(req.Context(), authorisation.ServiceCaller{Name: "bulk-metadata-fix", Token: "service-token"}))
+				rec := httptest.NewRecorder()
+				router := mux.NewRouter()
+				router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, ar))
+				router.ServeHTTP(rec, req)
+
+				So(rec.Code, ShouldEqual, http.StatusOK)
+				So(mockZebedeeClient.PutDatasetInCollectionCalls()[0].UserAccessToken, ShouldEqual, "service-token")
+				So(ar.RecordCalls(), ShouldHaveLength, 1)
+				So(ar.RecordCalls()[0].E.User, ShouldEqual, "bulk-metadata-fix")
+				So(ar.RecordCalls()[0].E.Service, ShouldBeTrue)
+			})
 		})
 
 		Convey("handles error from dataset client", func() {
 			mockDatasetClient := &DatasetAPIClientMock{
+				GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID}}, nil
+				},
+				GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+					return datasetApiModels.Version{ID: "1"}, datasetApiSdk.ResponseHeaders{}, nil
+				},
 				PutDatasetFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string, d datasetApiModels.Dataset) error {
 					return errors.New("test dataset API error")
 				},
@@ -152,7 +208,7 @@ func TestUnitPutMetadata(t *testing.T) {
 			req.Header.Set("X-Florence-Token", "testuser") // needed for the zebedee check
 			rec := httptest.NewRecorder()
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(PutMetadata(mockDatasetClient, mockZebedeeClient, &IdentityClientMock{}, newMockAuditRecorder()))
 
 			Convey("returns 500 response and error body", func() {
 				router.ServeHTTP(rec, req)
@@ -224,6 +280,12 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 			florenceToken := "testuser"
 
 			datasetClient := &DatasetAPIClientMock{
+				GetDatasetCurrentAndNextFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID}}, nil
+				},
+				GetVersionWithHeadersFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+					return datasetApiModels.Version{ID: "1"}, datasetApiSdk.ResponseHeaders{}, nil
+				},
 				PutMetadataFunc: func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string, editableMetadata datasetApiModels.EditableMetadata, versionEtag string) error {
 					if headers.AccessToken != florenceToken {
 						return errors.New("Function called with unexpected tokens")
@@ -253,8 +315,10 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 				},
 			}
 
+			auditRecorder := newMockAuditRecorder()
+
 			router := mux.NewRouter()
-			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(PutEditableMetadata(datasetClient, zebedeeClient))
+			router.Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(PutEditableMetadata(datasetClient, zebedeeClient, &IdentityClientMock{}, auditRecorder))
 
 			rec := httptest.NewRecorder()
 
@@ -294,6 +358,55 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 				})
 			})
 
+			Convey("When a request is made for a dataset held by another collection", func() {
+				datasetClient.GetDatasetCurrentAndNextFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID string) (datasetApiModels.DatasetUpdate, error) {
+					return datasetApiModels.DatasetUpdate{Next: &datasetApiModels.Dataset{ID: datasetID, CollectionID: "othercollection"}}, nil
+				}
+
+				body, _ := json.Marshal(metadata)
+				req := httptest.NewRequest("PUT", url, bytes.NewBuffer(body))
+				req.Header.Set("Collection-Id", mockCollectionId)
+				req.Header.Set("X-Florence-Token", florenceToken)
+
+				router.ServeHTTP(rec, req)
+
+				Convey("Then we receive a 409 response naming the owning collection", func() {
+					So(rec.Code, ShouldEqual, http.StatusConflict)
+					So(rec.Body.String(), ShouldEqual, "dataset is locked by collection othercollection\n")
+
+					So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
+					So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+					So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+				})
+			})
+
+			Convey("When a reviewed request is made by the last editor of the dataset", func() {
+				zebedeeClient.GetCollectionFunc = func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+					return zebedeeclient.Collection{
+						ID:       collectionID,
+						Datasets: []zebedeeclient.CollectionItem{{ID: mockDatasetId, LastEditedBy: "editor@ons.gov.uk"}},
+					}, nil
+				}
+
+				metadata.CollectionState = "reviewed"
+				body, _ := json.Marshal(metadata)
+				req := httptest.NewRequest("PUT", url, bytes.NewBuffer(body))
+				req.Header.Set("Collection-Id", mockCollectionId)
+				req.Header.Set("X-Florence-Token", testAccessToken("editor@ons.gov.uk"))
+
+				router.ServeHTTP(rec, req)
+
+				Convey("Then we receive a 403 response", func() {
+					So(rec.Code, ShouldEqual, http.StatusForbidden)
+					So(rec.Body.String(), ShouldContainSubstring, `"code":"self_review"`)
+					So(rec.Body.String(), ShouldContainSubstring, `"path":"/collection_state"`)
+
+					So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 0)
+					So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
+					So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+				})
+			})
+
 			Convey("And all headers are set", func() {
 				req.Header.Set("Collection-Id", mockCollectionId)
 				req.Header.Set("Access-Token", florenceToken)
@@ -312,6 +425,7 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 							So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 1)
 							So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 0)
 							So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 0)
+							So(len(auditRecorder.RecordCalls()), ShouldEqual, 0)
 						})
 					})
 				})
@@ -325,6 +439,13 @@ func TestUnitPutEditableMetadata(t *testing.T) {
 						So(len(datasetClient.PutMetadataCalls()), ShouldEqual, 1)
 						So(len(zebedeeClient.PutDatasetInCollectionCalls()), ShouldEqual, 1)
 						So(len(zebedeeClient.PutDatasetVersionInCollectionCalls()), ShouldEqual, 1)
+
+						So(len(auditRecorder.RecordCalls()), ShouldEqual, 1)
+						event := auditRecorder.RecordCalls()[0].E
+						So(event.Action, ShouldEqual, "put-editable-metadata")
+						So(event.DatasetID, ShouldEqual, mockDatasetId)
+						So(event.CollectionID, ShouldEqual, mockCollectionId)
+						So(event.Changes, ShouldContain, audit.Change{Field: "title", From: nil, To: "dataset title"})
 					})
 				})
 			})
@@ -0,0 +1,116 @@
+package dataset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/mapper"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// PutVersionRelease links a version to an entry in the release calendar, responding with the release and whether
+// the version's release date matches it
+func PutVersionRelease(dc DatasetAPIClient, ar AuditRecorder, rc ReleaseCalendar) http.HandlerFunc {
+	return controllerHandler(func(w http.ResponseWriter, r *http.Request, lang, collectionID, accessToken string) {
+		putVersionRelease(w, r, dc, ar, rc, accessToken, collectionID)
+	})
+}
+
+func putVersionRelease(w http.ResponseWriter, req *http.Request, dc DatasetAPIClient, ar AuditRecorder, rc ReleaseCalendar, userAccessToken, collectionID string) {
+	ctx := req.Context()
+
+	err := checkAccessTokenAndCollectionHeaders(req, userAccessToken, collectionID)
+	if err != nil {
+		log.Error(ctx, err.Error(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	datasetID := vars["datasetID"]
+	edition := vars["editionID"]
+	version := vars["versionID"]
+
+	logInfo := map[string]interface{}{
+		"datasetID":    datasetID,
+		"edition":      edition,
+		"version":      version,
+		"collectionID": collectionID,
+	}
+
+	headers := datasetApiSdk.Headers{
+		CollectionID: collectionID,
+		AccessToken:  userAccessToken,
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.Error(ctx, "putVersionRelease endpoint: error reading body", err, log.Data(logInfo))
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var body model.VersionReleaseLink
+	if err = json.Unmarshal(b, &body); err != nil {
+		log.Error(ctx, "putVersionRelease endpoint: error unmarshalling body", err, log.Data(logInfo))
+		http.Error(w, "error unmarshalling body", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(body.ReleaseID) == "" {
+		err = errors.New("a release ID must be given")
+		log.Error(ctx, "putVersionRelease endpoint: invalid release", err, log.Data(logInfo))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logInfo["releaseID"] = body.ReleaseID
+
+	l, ok := checkCollectionLock(w, req, dc, headers, datasetID, edition, version)
+	if !ok {
+		return
+	}
+	v := l.Version
+
+	// the previous link is only needed for the audit event, so a failure to read it does not stop the new link
+	previous, _, _ := rc.Linked(ctx, userAccessToken, datasetID, edition, version)
+
+	r, err := rc.Link(ctx, userAccessToken, datasetID, edition, version, body.ReleaseID)
+	if errors.Is(err, releases.ErrNotFound) {
+		log.Error(ctx, "putVersionRelease endpoint: release not found", err, log.Data(logInfo))
+		http.Error(w, fmt.Sprintf("release %s is not in the release calendar", body.ReleaseID), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Error(ctx, "error linking version to release", err, log.Data(logInfo))
+		http.Error(w, "error linking version to release", http.StatusInternalServerError)
+		return
+	}
+
+	event := newAuditEvent(ctx, "link-release", userAccessToken, collectionID, datasetID, edition, version)
+	event.Changes = []audit.Change{{Field: "release_id", From: previous.ReleaseID, To: r.ID}}
+	ar.Record(ctx, event)
+
+	b, err = json.Marshal(mapper.VersionRelease(v, r))
+	if err != nil {
+		log.Error(ctx, "error marshalling release to json", err, log.Data(logInfo))
+		http.Error(w, "error marshalling release to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		log.Error(ctx, "error writing response", err, log.Data(logInfo))
+		return
+	}
+
+	log.Info(ctx, "put version release: request successful", log.Data(logInfo))
+}
@@ -0,0 +1,123 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitPutVersionRelease(t *testing.T) {
+	t.Parallel()
+
+	const target = "/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release"
+
+	release := releases.Release{ID: "cpih-jan-2020", Title: "Consumer price inflation, UK: January 2020", ReleaseDate: time.Date(2020, 2, 19, 7, 0, 0, 0, time.UTC)}
+
+	newReleaseCalendar := func() *ReleaseCalendarMock {
+		return &ReleaseCalendarMock{
+			LinkFunc: func(ctx context.Context, userAccessToken, datasetID, edition, version, releaseID string) (releases.Release, error) {
+				if releaseID != release.ID {
+					return releases.Release{}, releases.ErrNotFound
+				}
+				return release, nil
+			},
+			LinkedFunc: func(ctx context.Context, userAccessToken, datasetID, edition, version string) (releases.Link, releases.Release, error) {
+				return releases.Link{ReleaseID: "cpih-dec-2019"}, releases.Release{}, nil
+			},
+		}
+	}
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/datasets/cpih01/editions/time-series/versions/2/release", bytes.NewBufferString(body))
+		req.Header.Set("Collection-Id", "testcollection")
+		req.Header.Set("X-Florence-Token", "testuser")
+		return req
+	}
+
+	Convey("test putVersionRelease", t, func() {
+		Convey("links the version to the release and compares its release date", func() {
+			dc := newVersionFilesDatasetClient()
+			dc.GetVersionWithHeadersFunc = func(ctx context.Context, headers datasetApiSdk.Headers, datasetID, edition, version string) (datasetApiModels.Version, datasetApiSdk.ResponseHeaders, error) {
+				return datasetApiModels.Version{CollectionID: "testcollection", ReleaseDate: "2020-02-19T00:00:00.000Z"}, datasetApiSdk.ResponseHeaders{}, nil
+			}
+			rc := newReleaseCalendar()
+			ar := newMockAuditRecorder()
+			w := doTestRequest(target, newRequest(`{"release_id":"cpih-jan-2020"}`), PutVersionRelease(dc, ar, rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(rc.LinkCalls(), ShouldHaveLength, 1)
+			So(rc.LinkCalls()[0].DatasetID, ShouldEqual, "cpih01")
+			So(rc.LinkCalls()[0].Edition, ShouldEqual, "time-series")
+			So(rc.LinkCalls()[0].Version, ShouldEqual, "2")
+			So(rc.LinkCalls()[0].UserAccessToken, ShouldEqual, "testuser")
+
+			var body model.VersionRelease
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.ID, ShouldEqual, release.ID)
+			So(body.ReleaseDateMismatch, ShouldBeFalse)
+
+			So(ar.RecordCalls(), ShouldHaveLength, 1)
+			So(ar.RecordCalls()[0].E.Action, ShouldEqual, "link-release")
+			So(ar.RecordCalls()[0].E.Changes[0].From, ShouldEqual, "cpih-dec-2019")
+			So(ar.RecordCalls()[0].E.Changes[0].To, ShouldEqual, release.ID)
+		})
+
+		Convey("flags a version without a release date", func() {
+			w := doTestRequest(target, newRequest(`{"release_id":"cpih-jan-2020"}`), PutVersionRelease(newVersionFilesDatasetClient(), newMockAuditRecorder(), newReleaseCalendar()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			var body model.VersionRelease
+			So(json.Unmarshal(w.Body.Bytes(), &body), ShouldBeNil)
+			So(body.ReleaseDateMismatch, ShouldBeTrue)
+		})
+
+		Convey("returns 400 when no release is given", func() {
+			rc := newReleaseCalendar()
+			w := doTestRequest(target, newRequest(`{"release_id":" "}`), PutVersionRelease(newVersionFilesDatasetClient(), newMockAuditRecorder(), rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(rc.LinkCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 400 when the release is not in the calendar", func() {
+			ar := newMockAuditRecorder()
+			w := doTestRequest(target, newRequest(`{"release_id":"cpih-feb-2020"}`), PutVersionRelease(newVersionFilesDatasetClient(), ar, newReleaseCalendar()), nil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+			So(w.Body.String(), ShouldContainSubstring, "cpih-feb-2020 is not in the release calendar")
+			So(ar.RecordCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 409 when the version is in another collection", func() {
+			rc := newReleaseCalendar()
+			req := newRequest(`{"release_id":"cpih-jan-2020"}`)
+			req.Header.Set("Collection-Id", "othercollection")
+			w := doTestRequest(target, req, PutVersionRelease(newVersionFilesDatasetClient(), newMockAuditRecorder(), rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusConflict)
+			So(rc.LinkCalls(), ShouldBeEmpty)
+		})
+
+		Convey("returns 500 when the link cannot be stored", func() {
+			rc := newReleaseCalendar()
+			rc.LinkFunc = func(ctx context.Context, userAccessToken, datasetID, edition, version, releaseID string) (releases.Release, error) {
+				return releases.Release{}, errors.New("disk full")
+			}
+			w := doTestRequest(target, newRequest(`{"release_id":"cpih-jan-2020"}`), PutVersionRelease(newVersionFilesDatasetClient(), newMockAuditRecorder(), rc), nil)
+
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
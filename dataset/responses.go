@@ -0,0 +1,27 @@
+package dataset
+
+import (
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+)
+
+// VersionFile is a file uploaded for a version of a static dataset, with the distribution it is registered as
+type VersionFile struct {
+	upload.File
+	Distribution *datasetApiModels.Distribution `json:"distribution,omitempty"`
+}
+
+// AuditHistory is the audit history of writes made to a dataset through the controller, oldest first
+type AuditHistory struct {
+	DatasetID string        `json:"dataset_id"`
+	Events    []audit.Event `json:"events"`
+}
+
+// MetadataImportPreview lists the changes an import makes to the metadata of a version, and the metadata after
+// the import. Committed is true once the changes have been saved
+type MetadataImportPreview struct {
+	Committed bool                              `json:"committed"`
+	Changes   []audit.Change                    `json:"changes"`
+	Metadata  datasetApiModels.EditableMetadata `json:"metadata"`
+}
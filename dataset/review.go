@@ -0,0 +1,83 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/identity"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// ErrSelfReview is returned when the caller tries to mark as reviewed a dataset or version they last edited
+var ErrSelfReview = errors.New("self-review not allowed: the dataset or version was last edited by the caller and must be reviewed by someone else")
+
+// errUnidentifiedReviewer is returned when the caller's identity cannot be read from their access token
+var errUnidentifiedReviewer = errors.New("unable to identify the reviewer from the access token")
+
+// errReviewerLookup is returned when the email of the caller cannot be looked up in the identity API
+var errReviewerLookup = errors.New("error looking up the reviewer")
+
+// checkNotSelfReview enforces the four-eyes rule by returning ErrSelfReview if the caller is the last editor
+// of the dataset, or of the dataset version if an edition and version are given, in the collection. The caller is
+// the identity verified by the authoriser when authorisation is enabled. Zebedee records editors by email, which
+// Cognito access tokens do not hold, so the caller's email is looked up in the identity API
+func checkNotSelfReview(ctx context.Context, zc ZebedeeClient, ic IdentityClient, userAccessToken, collectionID, datasetID, edition, version string) error {
+	caller, err := identity.Caller(ctx, userAccessToken)
+	if err != nil {
+		log.Error(ctx, "failed to read caller identity from access token", err)
+		return errUnidentifiedReviewer
+	}
+
+	if caller.Email == "" && caller.ID != "" {
+		u, err := ic.GetUser(ctx, userAccessToken, caller.ID)
+		if err != nil {
+			log.Error(ctx, "failed to look up the caller in the identity API", err, log.Data{"user_id": caller.ID})
+			return errReviewerLookup
+		}
+		caller.Email = u.Email
+	}
+
+	c, err := zc.GetCollection(ctx, userAccessToken, collectionID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range c.Datasets {
+		if item.ID == datasetID && caller.Is(item.LastEditedBy) {
+			return ErrSelfReview
+		}
+	}
+
+	if version == "" {
+		return nil
+	}
+
+	for _, item := range c.DatasetVersions {
+		if item.ID == datasetID && item.Edition == edition && item.Version == version && caller.Is(item.LastEditedBy) {
+			return ErrSelfReview
+		}
+	}
+
+	return nil
+}
+
+// writeReviewError writes the response for an error returned by checkNotSelfReview. A self-review is reported
+// against path, the JSON pointer of the field that asked for the review, with the self_review error code
+func writeReviewError(w http.ResponseWriter, req *http.Request, err error, path string) {
+	switch {
+	case errors.Is(err, ErrSelfReview):
+		writeErrorResponse(w, req, http.StatusForbidden, err.Error(), []model.FieldError{{
+			Path:    path,
+			Message: "must be set by someone other than the last editor",
+			Code:    model.ErrorCodeSelfReview,
+		}})
+	case errors.Is(err, errUnidentifiedReviewer):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, errReviewerLookup):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, "error getting collection details", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,142 @@
+package dataset
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zebedeeclient "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/identity"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testAccessToken returns an unsigned JWT access token issued to the given email address
+func testAccessToken(email string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"test-user","email":"` + email + `"}`))
+	return header + "." + payload + ".signature"
+}
+
+func TestUnitCheckNotSelfReview(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	mockZebedeeClient := &ZebedeeClientMock{
+		GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+			return zebedeeclient.Collection{
+				ID:              collectionID,
+				Datasets:        []zebedeeclient.CollectionItem{{ID: "test-dataset", LastEditedBy: "dataset-editor@ons.gov.uk"}},
+				DatasetVersions: []zebedeeclient.CollectionItem{{ID: "test-dataset", Edition: "2021", Version: "1", LastEditedBy: "version-editor@ons.gov.uk"}},
+			}, nil
+		},
+	}
+
+	Convey("test checkNotSelfReview", t, func() {
+		Convey("allows a caller who did not last edit the dataset or version", func() {
+			err := checkNotSelfReview(ctx, mockZebedeeClient, &IdentityClientMock{}, testAccessToken("reviewer@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("rejects the last editor of the dataset", func() {
+			err := checkNotSelfReview(ctx, mockZebedeeClient, &IdentityClientMock{}, testAccessToken("dataset-editor@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldEqual, ErrSelfReview)
+		})
+
+		Convey("rejects the last editor of the version", func() {
+			err := checkNotSelfReview(ctx, mockZebedeeClient, &IdentityClientMock{}, testAccessToken("version-editor@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldEqual, ErrSelfReview)
+		})
+
+		Convey("only checks versions matching the edition and version", func() {
+			err := checkNotSelfReview(ctx, mockZebedeeClient, &IdentityClientMock{}, testAccessToken("version-editor@ons.gov.uk"), "testcollection", "test-dataset", "2021", "2")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("rejects a caller whose access token cannot be read", func() {
+			err := checkNotSelfReview(ctx, mockZebedeeClient, &IdentityClientMock{}, "testuser", "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldEqual, errUnidentifiedReviewer)
+		})
+
+		Convey("uses the identity verified by the authoriser and looks up its email", func() {
+			ic := &IdentityClientMock{
+				GetUserFunc: func(ctx context.Context, userAccessToken, userID string) (users.User, error) {
+					return users.User{ID: userID, Email: "Dataset-Editor@ons.gov.uk"}, nil
+				},
+			}
+			verified := identity.NewContext(ctx, identity.Identity{ID: "cognito-sub", Username: "cognito-username"})
+
+			err := checkNotSelfReview(verified, mockZebedeeClient, ic, testAccessToken("reviewer@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldEqual, ErrSelfReview)
+			So(ic.GetUserCalls()[0].UserID, ShouldEqual, "cognito-sub")
+		})
+
+		Convey("looks up the email of a token without an email claim", func() {
+			ic := &IdentityClientMock{
+				GetUserFunc: func(ctx context.Context, userAccessToken, userID string) (users.User, error) {
+					return users.User{ID: userID, Email: "reviewer@ons.gov.uk"}, nil
+				},
+			}
+
+			err := checkNotSelfReview(ctx, mockZebedeeClient, ic, testAccessToken(""), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldBeNil)
+			So(ic.GetUserCalls()[0].UserID, ShouldEqual, "test-user")
+		})
+
+		Convey("returns errReviewerLookup if the identity API cannot be read", func() {
+			ic := &IdentityClientMock{
+				GetUserFunc: func(ctx context.Context, userAccessToken, userID string) (users.User, error) {
+					return users.User{}, errors.New("test identity error")
+				},
+			}
+
+			err := checkNotSelfReview(ctx, mockZebedeeClient, ic, testAccessToken(""), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldEqual, errReviewerLookup)
+		})
+
+		Convey("returns the error from zebedee", func() {
+			failingZebedeeClient := &ZebedeeClientMock{
+				GetCollectionFunc: func(ctx context.Context, userAccessToken, collectionID string) (zebedeeclient.Collection, error) {
+					return zebedeeclient.Collection{}, errors.New("test zebedee error")
+				},
+			}
+			err := checkNotSelfReview(ctx, failingZebedeeClient, &IdentityClientMock{}, testAccessToken("reviewer@ons.gov.uk"), "testcollection", "test-dataset", "2021", "1")
+			So(err, ShouldResemble, errors.New("test zebedee error"))
+		})
+	})
+
+	Convey("test writeReviewError", t, func() {
+		req := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+
+		Convey("returns 403 for a self-review, naming the field and reason", func() {
+			w := httptest.NewRecorder()
+			writeReviewError(w, req, ErrSelfReview, "/state")
+			So(w.Code, ShouldEqual, http.StatusForbidden)
+
+			var resp model.ErrorResponse
+			So(json.Unmarshal(w.Body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Message, ShouldEqual, ErrSelfReview.Error())
+			So(resp.Errors, ShouldResemble, []model.FieldError{{Path: "/state", Message: "must be set by someone other than the last editor", Code: "self_review"}})
+		})
+
+		Convey("returns 401 for an unidentified reviewer", func() {
+			w := httptest.NewRecorder()
+			writeReviewError(w, req, errUnidentifiedReviewer, "/state")
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("returns 500 for other errors", func() {
+			w := httptest.NewRecorder()
+			writeReviewError(w, req, errors.New("test zebedee error"), "/state")
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Body.String(), ShouldEqual, "error getting collection details\n")
+		})
+	})
+}
@@ -0,0 +1,21 @@
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Spec is the OpenAPI 3 specification of the service's API
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// Handler serves the OpenAPI specification
+func Handler(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(Spec); err != nil {
+		log.Error(req.Context(), "error writing api docs", err)
+	}
+}
@@ -0,0 +1,105 @@
+package identity
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned when an access token is not a JWT with a readable claims section
+var ErrInvalidToken = errors.New("access token is not a valid JWT")
+
+// ErrNoIdentity is returned when an access token does not contain any claim identifying the caller
+var ErrNoIdentity = errors.New("access token does not identify a user")
+
+// Identity describes the caller that an access token was issued to
+type Identity struct {
+	ID       string `json:"sub"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type contextKey string
+
+const verifiedIdentityKey contextKey = "verifiedIdentity"
+
+type claims struct {
+	Identity
+	CognitoUsername string `json:"cognito:username"`
+}
+
+// FromAccessToken reads the caller identity from the claims of a JWT access token. The token signature is
+// not verified here; the token is still passed on to, and verified by, the upstream services
+func FromAccessToken(token string) (Identity, error) {
+	parts := strings.Split(strings.TrimPrefix(token, "Bearer "), ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	if c.Username == "" {
+		c.Username = c.CognitoUsername
+	}
+
+	if c.ID == "" && c.Username == "" && c.Email == "" {
+		return Identity{}, ErrNoIdentity
+	}
+
+	return c.Identity, nil
+}
+
+// NewContext returns a copy of ctx holding an identity whose access token has been verified
+func NewContext(ctx context.Context, i Identity) context.Context {
+	return context.WithValue(ctx, verifiedIdentityKey, i)
+}
+
+// FromContext returns the verified identity held in ctx, if there is one
+func FromContext(ctx context.Context) (Identity, bool) {
+	i, ok := ctx.Value(verifiedIdentityKey).(Identity)
+	return i, ok
+}
+
+// Caller returns the identity of the caller of a request: the identity verified by the authoriser when authorisation
+// is enabled, or else the identity read from the claims of the unverified access token
+func Caller(ctx context.Context, token string) (Identity, error) {
+	if i, ok := FromContext(ctx); ok {
+		return i, nil
+	}
+	return FromAccessToken(token)
+}
+
+// Is reports whether name, as recorded by zebedee or the dataset API, refers to this identity
+func (i Identity) Is(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, id := range []string{i.Email, i.Username, i.ID} {
+		if id != "" && strings.EqualFold(id, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the most readable identifier held for the identity
+func (i Identity) String() string {
+	switch {
+	case i.Email != "":
+		return i.Email
+	case i.Username != "":
+		return i.Username
+	default:
+		return i.ID
+	}
+}
@@ -0,0 +1,90 @@
+package identity
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testToken(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+	return header + "." + payload + ".signature"
+}
+
+func TestUnitFromAccessToken(t *testing.T) {
+	t.Parallel()
+
+	Convey("test FromAccessToken", t, func() {
+		Convey("reads the identity claims", func() {
+			id, err := FromAccessToken(testToken(`{"sub":"1234","username":"publisher","email":"publisher@ons.gov.uk"}`))
+
+			So(err, ShouldBeNil)
+			So(id, ShouldResemble, Identity{ID: "1234", Username: "publisher", Email: "publisher@ons.gov.uk"})
+		})
+
+		Convey("falls back to the cognito username claim", func() {
+			id, err := FromAccessToken("Bearer " + testToken(`{"sub":"1234","cognito:username":"publisher"}`))
+
+			So(err, ShouldBeNil)
+			So(id.Username, ShouldEqual, "publisher")
+		})
+
+		Convey("returns ErrInvalidToken if the token is not a JWT", func() {
+			_, err := FromAccessToken("not-a-jwt")
+
+			So(err, ShouldEqual, ErrInvalidToken)
+		})
+
+		Convey("returns ErrInvalidToken if the claims are not json", func() {
+			_, err := FromAccessToken("a." + base64.RawURLEncoding.EncodeToString([]byte("nope")) + ".c")
+
+			So(err, ShouldEqual, ErrInvalidToken)
+		})
+
+		Convey("returns ErrNoIdentity if no identifying claims are set", func() {
+			_, err := FromAccessToken(testToken(`{"exp":1}`))
+
+			So(err, ShouldEqual, ErrNoIdentity)
+		})
+	})
+}
+
+func TestUnitIs(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Is", t, func() {
+		id := Identity{ID: "1234", Username: "publisher", Email: "Publisher@ons.gov.uk"}
+
+		So(id.Is("publisher@ons.gov.uk"), ShouldBeTrue)
+		So(id.Is("publisher"), ShouldBeTrue)
+		So(id.Is("1234"), ShouldBeTrue)
+		So(id.Is("reviewer@ons.gov.uk"), ShouldBeFalse)
+		So(id.Is(""), ShouldBeFalse)
+	})
+}
+
+func TestUnitCaller(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Caller", t, func() {
+		token := testToken(`{"sub":"unverified","email":"publisher@ons.gov.uk"}`)
+
+		Convey("returns the verified identity held in the context", func() {
+			ctx := NewContext(context.Background(), Identity{ID: "verified", Username: "publisher"})
+			id, err := Caller(ctx, token)
+
+			So(err, ShouldBeNil)
+			So(id, ShouldResemble, Identity{ID: "verified", Username: "publisher"})
+		})
+
+		Convey("reads the access token if there is no verified identity", func() {
+			id, err := Caller(context.Background(), token)
+
+			So(err, ShouldBeNil)
+			So(id.ID, ShouldEqual, "unverified")
+		})
+	})
+}
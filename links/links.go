@@ -0,0 +1,111 @@
+package links
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	datasetsSegment = "datasets"
+	editionsSegment = "editions"
+	versionsSegment = "versions"
+)
+
+// ErrInvalidLink is returned when a link cannot be parsed as a URL
+type ErrInvalidLink struct {
+	Link string
+	Err  error
+}
+
+// Error returns the stringified version of the error
+func (e ErrInvalidLink) Error() string {
+	return fmt.Sprintf("invalid link %q: %v", e.Link, e.Err)
+}
+
+// Unwrap returns the underlying url parsing error
+func (e ErrInvalidLink) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingSegment is returned when a link does not contain an expected resource path segment or its ID
+type ErrMissingSegment struct {
+	Link    string
+	Segment string
+}
+
+// Error returns the stringified version of the error
+func (e ErrMissingSegment) Error() string {
+	return fmt.Sprintf("link %q does not contain a %s ID", e.Link, e.Segment)
+}
+
+// Resource holds the IDs found in a dataset API link. IDs not present in the link are left empty
+type Resource struct {
+	DatasetID string
+	EditionID string
+	VersionID string
+}
+
+// Parse reads the dataset, edition and version IDs from a dataset API HATEOAS link by matching its path
+// segments, so any prefix before "/datasets" (such as "/v1") is ignored
+func Parse(link string) (Resource, error) {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return Resource{}, ErrInvalidLink{Link: link, Err: err}
+	}
+
+	segments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+
+	start := -1
+	for i := range segments {
+		if segments[i] == datasetsSegment {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return Resource{}, ErrMissingSegment{Link: link, Segment: datasetsSegment}
+	}
+
+	var r Resource
+	ids := []struct {
+		segment string
+		id      *string
+	}{
+		{datasetsSegment, &r.DatasetID},
+		{editionsSegment, &r.EditionID},
+		{versionsSegment, &r.VersionID},
+	}
+
+	remaining := segments[start:]
+	for _, id := range ids {
+		if len(remaining) == 0 {
+			break
+		}
+		if remaining[0] != id.segment || len(remaining) < 2 || remaining[1] == "" {
+			return Resource{}, ErrMissingSegment{Link: link, Segment: id.segment}
+		}
+		*id.id = remaining[1]
+		remaining = remaining[2:]
+	}
+
+	return r, nil
+}
+
+// ParseVersion reads the dataset, edition and version IDs from a dataset API version link, returning an
+// ErrMissingSegment if any of the three are not present
+func ParseVersion(link string) (Resource, error) {
+	r, err := Parse(link)
+	if err != nil {
+		return Resource{}, err
+	}
+
+	switch {
+	case r.EditionID == "":
+		return Resource{}, ErrMissingSegment{Link: link, Segment: editionsSegment}
+	case r.VersionID == "":
+		return Resource{}, ErrMissingSegment{Link: link, Segment: versionsSegment}
+	}
+
+	return r, nil
+}
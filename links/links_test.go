@@ -0,0 +1,93 @@
+package links
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitParse(t *testing.T) {
+	t.Parallel()
+
+	Convey("test Parse", t, func() {
+		Convey("returns correct values for a link with a version prefix", func() {
+			r, err := Parse("https://test.ons.gov.uk/v1/datasets/ds1/editions/ed2/versions/1")
+
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1", EditionID: "ed2", VersionID: "1"})
+		})
+
+		Convey("returns correct values for a link without a version prefix", func() {
+			r, err := Parse("http://localhost:22000/datasets/ds1/editions/ed2/versions/1")
+
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1", EditionID: "ed2", VersionID: "1"})
+		})
+
+		Convey("returns correct values for a relative link with a trailing slash", func() {
+			r, err := Parse("/datasets/ds1/editions/ed2/versions/1/")
+
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1", EditionID: "ed2", VersionID: "1"})
+		})
+
+		Convey("returns the IDs present in a dataset or edition link", func() {
+			r, err := Parse("https://test.ons.gov.uk/v1/datasets/ds1")
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1"})
+
+			r, err = Parse("https://test.ons.gov.uk/v1/datasets/ds1/editions/ed2")
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1", EditionID: "ed2"})
+		})
+
+		Convey("returns ErrMissingSegment if the link has no datasets segment", func() {
+			_, err := Parse("https://test.ons.gov.uk/this/isnt/enough")
+
+			var missing ErrMissingSegment
+			So(errors.As(err, &missing), ShouldBeTrue)
+			So(missing.Segment, ShouldEqual, "datasets")
+		})
+
+		Convey("returns ErrMissingSegment if a segment is out of order", func() {
+			_, err := Parse("https://test.ons.gov.uk/v1/datasets/ds1/versions/1")
+
+			var missing ErrMissingSegment
+			So(errors.As(err, &missing), ShouldBeTrue)
+			So(missing.Segment, ShouldEqual, "editions")
+		})
+
+		Convey("returns ErrInvalidLink if the link cannot be parsed", func() {
+			_, err := Parse("http://[::1/datasets")
+
+			var invalid ErrInvalidLink
+			So(errors.As(err, &invalid), ShouldBeTrue)
+		})
+	})
+}
+
+func TestUnitParseVersion(t *testing.T) {
+	t.Parallel()
+
+	Convey("test ParseVersion", t, func() {
+		Convey("returns correct values", func() {
+			r, err := ParseVersion("https://test.ons.gov.uk/datasets/ds1/editions/ed2/versions/3")
+
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Resource{DatasetID: "ds1", EditionID: "ed2", VersionID: "3"})
+		})
+
+		Convey("returns ErrMissingSegment if there is no version ID", func() {
+			_, err := ParseVersion("https://test.ons.gov.uk/v1/datasets/ds1/editions/ed2/versions")
+
+			So(err, ShouldResemble, ErrMissingSegment{Link: "https://test.ons.gov.uk/v1/datasets/ds1/editions/ed2/versions", Segment: "versions"})
+		})
+
+		Convey("returns ErrMissingSegment if there is no edition ID", func() {
+			_, err := ParseVersion("https://test.ons.gov.uk/v1/datasets/ds1")
+
+			So(err, ShouldResemble, ErrMissingSegment{Link: "https://test.ons.gov.uk/v1/datasets/ds1", Segment: "editions"})
+		})
+	})
+}
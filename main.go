@@ -5,17 +5,28 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	"github.com/ONSdigital/dp-api-clients-go/v2/health"
-	"github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	dpnethttp "github.com/ONSdigital/dp-net/v3/http"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/download"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/zebedee"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/config"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/docs"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/metrics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/routes"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/tracing"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/validation"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
 )
@@ -55,10 +66,23 @@ func main() {
 		os.Exit(1)
 	}
 
-	apiRouterCli := health.NewClient("api-router", cfg.APIRouterURL)
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		Enabled:      cfg.OtelEnabled,
+		ServiceName:  cfg.OTServiceName,
+		Endpoint:     cfg.OTExporterOTLPEndpoint,
+		BatchTimeout: cfg.OTBatchTimeout,
+	})
+	if err != nil {
+		log.Fatal(ctx, "failed to initialise tracing", err)
+		os.Exit(1)
+	}
+
+	apiRouterCli := health.NewClientWithClienter("api-router", cfg.APIRouterURL, tracing.NewClient())
 	dc := dataset.NewWithHealthClient(apiRouterCli)
 	zc := zebedee.NewWithHealthClient(apiRouterCli)
-	bc := topics.New(cfg.BabbageURL)
+	bc := topics.NewWithHealthClient(health.NewClientWithClienter("Babbage", cfg.BabbageURL, tracing.NewClient()))
+	dl := download.New(cfg.DownloadServiceURL, tracing.NewClient())
+	uc := users.NewWithHealthClient(apiRouterCli)
 
 	datasetAPISdkClient := datasetApiSdk.NewWithHealthClient(apiRouterCli)
 
@@ -68,8 +92,61 @@ func main() {
 		os.Exit(1)
 	}
 
+	auditor, err := audit.NewFromConfig(cfg.AuditSink, cfg.AuditFilePath, cfg.AuditHTTPURL)
+	if err != nil {
+		log.Fatal(ctx, "failed to create auditor", err)
+		os.Exit(1)
+	}
+
+	files, err := upload.NewFromConfig(cfg.FileBackend, cfg.FileLocalDir, cfg.AllowLocalFileBackend)
+	if err != nil {
+		log.Fatal(ctx, "failed to create file backend", err)
+		os.Exit(1)
+	}
+
+	chunks := upload.NewResumable(files, upload.NewLocalStateStore(filepath.Join(cfg.ResumableUploadDir, "state")), filepath.Join(cfg.ResumableUploadDir, "chunks"), cfg.MaxResumableUploadSize)
+	sweepCtx, stopSweeping := context.WithCancel(ctx)
+	if cfg.ResumableUploadTTL > 0 {
+		chunks.StartSweeping(sweepCtx, cfg.ResumableUploadTTL)
+	}
+
+	releaseSource, err := releases.NewFromConfig(cfg.ReleaseSource, cfg.ReleaseLocalFile, apiRouterCli)
+	if err != nil {
+		log.Fatal(ctx, "failed to create release source", err)
+		os.Exit(1)
+	}
+	releaseLinks, err := releases.NewLinkStoreFromConfig(ctx, cfg.ReleaseLinkStore, cfg.ReleaseLinksFile, releases.MongoDBConfig{
+		URI:        cfg.MongoDBURI,
+		Database:   cfg.MongoDBDatabase,
+		Collection: cfg.MongoDBReleaseLinks,
+	})
+	if err != nil {
+		log.Fatal(ctx, "failed to create release link store", err)
+		os.Exit(1)
+	}
+	mongoLinks, usesMongoDB := releaseLinks.(*releases.MongoLinkStore)
+	if usesMongoDB {
+		if err = hc.AddCheck("MongoDB", mongoLinks.Checker); err != nil {
+			log.Fatal(ctx, "failed to add MongoDB checker", err)
+			os.Exit(1)
+		}
+	}
+	calendar := releases.NewCalendar(releaseSource, releaseLinks)
+
+	validator, err := validation.New(docs.Spec, cfg.MaxRequestBodySize, cfg.MaxUploadSize)
+	if err != nil {
+		log.Fatal(ctx, "failed to create request validator", err)
+		os.Exit(1)
+	}
+
+	authoriser, err := authorisation.NewFromConfig(cfg.AuthorisationEnabled, cfg.JWKSFile, cfg.PermissionsBundleFile, cfg.ServiceAuthEnabled, cfg.ServiceTokensFile)
+	if err != nil {
+		log.Fatal(ctx, "failed to create authoriser", err)
+		os.Exit(1)
+	}
+
 	router := mux.NewRouter()
-	routes.Init(router, cfg, hc, dc, zc, bc, datasetAPISdkClient)
+	routes.Init(router, cfg, hc, dc, zc, bc, dl, uc, datasetAPISdkClient, auditor, files, chunks, calendar, metrics.New(), validator, authoriser)
 
 	s := dpnethttp.NewServer(cfg.BindAddr, router)
 
@@ -94,11 +171,22 @@ func main() {
 	go func() {
 		log.Info(ctx, "stop health checkers")
 		hc.Stop()
+		stopSweeping()
 
 		if err := s.Shutdown(ctx); err != nil {
 			log.Error(ctx, "failed to gracefully shutdown http server", err)
 		}
 
+		if usesMongoDB {
+			if err := mongoLinks.Close(ctx); err != nil {
+				log.Error(ctx, "failed to close MongoDB connection", err)
+			}
+		}
+
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error(ctx, "failed to flush traces", err)
+		}
+
 		cancel() // stop timer
 	}()
 
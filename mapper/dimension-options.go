@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// DimensionOptions maps the options of a version's dimension to the page of options shown in the editor. If published
+// is not nil, the options that are not in it are flagged as new and the published options that the version no longer
+// has are added to the end, flagged as removed. The options are then filtered by q, which matches the code or label
+// ignoring case, before the page at offset is taken
+func DimensionOptions(dim datasetApiModels.Dimension, options []datasetApiModels.PublicDimensionOption, published *[]datasetApiModels.PublicDimensionOption, q string, offset, limit int) model.DimensionOptions {
+	all := make([]model.DimensionOption, 0, len(options))
+	for i := range options {
+		all = append(all, dimensionOption(options[i]))
+	}
+
+	if published != nil {
+		codes := make(map[string]bool, len(*published))
+		for i := range *published {
+			codes[(*published)[i].Option] = true
+		}
+
+		current := make(map[string]bool, len(options))
+		for i := range all {
+			current[all[i].Code] = true
+			all[i].New = !codes[all[i].Code]
+		}
+
+		for i := range *published {
+			if current[(*published)[i].Option] {
+				continue
+			}
+			removed := dimensionOption((*published)[i])
+			removed.Removed = true
+			all = append(all, removed)
+		}
+	}
+
+	matched := all
+	if q = strings.ToLower(strings.TrimSpace(q)); q != "" {
+		matched = make([]model.DimensionOption, 0, len(all))
+		for _, o := range all {
+			if strings.Contains(strings.ToLower(o.Code), q) || strings.Contains(strings.ToLower(o.Label), q) {
+				matched = append(matched, o)
+			}
+		}
+	}
+
+	page := []model.DimensionOption{}
+	if offset < len(matched) {
+		end := min(offset+limit, len(matched))
+		page = matched[offset:end]
+	}
+
+	return model.DimensionOptions{
+		Dimension:  dim.Name,
+		IsAreaType: dim.IsAreaType != nil && *dim.IsAreaType,
+		Items:      page,
+		Count:      len(page),
+		Offset:     offset,
+		Limit:      limit,
+		TotalCount: len(matched),
+	}
+}
+
+func dimensionOption(o datasetApiModels.PublicDimensionOption) model.DimensionOption {
+	return model.DimensionOption{
+		Code:     o.Option,
+		Label:    o.Label,
+		CodeList: o.Links.CodeList.ID,
+	}
+}
@@ -0,0 +1,103 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDimensionOptions(t *testing.T) {
+	t.Parallel()
+
+	isAreaType := true
+	dim := models.Dimension{Name: "geography", IsAreaType: &isAreaType}
+
+	option := func(code, label string) models.PublicDimensionOption {
+		return models.PublicDimensionOption{
+			Name:   "geography",
+			Option: code,
+			Label:  label,
+			Links:  models.DimensionOptionLinks{CodeList: models.LinkObject{ID: "ashe-geography"}},
+		}
+	}
+
+	options := []models.PublicDimensionOption{
+		option("K02000001", "United Kingdom"),
+		option("E92000001", "England"),
+		option("W92000004", "Wales"),
+	}
+	published := []models.PublicDimensionOption{
+		option("K02000001", "United Kingdom"),
+		option("E92000001", "England"),
+		option("S92000003", "Scotland"),
+	}
+
+	Convey("test DimensionOptions", t, func() {
+		Convey("maps the options without flags when there is no published version", func() {
+			page := DimensionOptions(dim, options, nil, "", 0, 20)
+
+			So(page.Dimension, ShouldEqual, "geography")
+			So(page.IsAreaType, ShouldBeTrue)
+			So(page.TotalCount, ShouldEqual, 3)
+			So(page.Count, ShouldEqual, 3)
+			So(page.Items[0], ShouldResemble, model.DimensionOption{Code: "K02000001", Label: "United Kingdom", CodeList: "ashe-geography"})
+			So(page.Items[2].New, ShouldBeFalse)
+		})
+
+		Convey("flags new options and adds removed options to the end", func() {
+			page := DimensionOptions(dim, options, &published, "", 0, 20)
+
+			So(page.TotalCount, ShouldEqual, 4)
+			So(page.Items[0].New, ShouldBeFalse)
+			So(page.Items[2].Code, ShouldEqual, "W92000004")
+			So(page.Items[2].New, ShouldBeTrue)
+			So(page.Items[3].Code, ShouldEqual, "S92000003")
+			So(page.Items[3].Removed, ShouldBeTrue)
+			So(page.Items[3].New, ShouldBeFalse)
+		})
+
+		Convey("flags every option as new if the published version does not have the dimension", func() {
+			page := DimensionOptions(dim, options, &[]models.PublicDimensionOption{}, "", 0, 20)
+
+			So(page.TotalCount, ShouldEqual, 3)
+			for _, o := range page.Items {
+				So(o.New, ShouldBeTrue)
+			}
+		})
+
+		Convey("filters by code or label ignoring case before paging", func() {
+			page := DimensionOptions(dim, options, &published, " LAND ", 0, 20)
+
+			So(page.TotalCount, ShouldEqual, 2)
+			So(page.Items[0].Label, ShouldEqual, "England")
+			So(page.Items[1].Label, ShouldEqual, "Scotland")
+
+			page = DimensionOptions(dim, options, nil, "w92", 0, 20)
+
+			So(page.TotalCount, ShouldEqual, 1)
+			So(page.Items[0].Label, ShouldEqual, "Wales")
+		})
+
+		Convey("returns the page at the offset", func() {
+			page := DimensionOptions(dim, options, &published, "", 1, 2)
+
+			So(page.Offset, ShouldEqual, 1)
+			So(page.Limit, ShouldEqual, 2)
+			So(page.Count, ShouldEqual, 2)
+			So(page.TotalCount, ShouldEqual, 4)
+			So(page.Items[0].Code, ShouldEqual, "E92000001")
+			So(page.Items[1].Code, ShouldEqual, "W92000004")
+		})
+
+		Convey("returns an empty page if the offset is past the end", func() {
+			page := DimensionOptions(dim, options, nil, "", 10, 20)
+
+			So(page.Items, ShouldNotBeNil)
+			So(page.Items, ShouldBeEmpty)
+			So(page.Count, ShouldEqual, 0)
+			So(page.TotalCount, ShouldEqual, 3)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package mapper
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+)
+
+var distributionFormats = map[string]struct {
+	format    datasetApiModels.DistributionFormat
+	mediaType datasetApiModels.DistributionMediaType
+}{
+	".csv":  {datasetApiModels.DistributionFormatCSV, datasetApiModels.DistributionMediaTypeCSV},
+	".xls":  {datasetApiModels.DistributionFormatXLS, datasetApiModels.DistributionMediaTypeXLS},
+	".xlsx": {datasetApiModels.DistributionFormatXLSX, datasetApiModels.DistributionMediaTypeXLSX},
+	".sdmx": {datasetApiModels.DistributionFormatSDMX, datasetApiModels.DistributionMediaTypeSDMX},
+	".xml":  {datasetApiModels.DistributionFormatSDMX, datasetApiModels.DistributionMediaTypeSDMX},
+	".csdb": {datasetApiModels.DistributionFormatCSDB, datasetApiModels.DistributionMediaTypeCSDB},
+	".json": {datasetApiModels.DistributionFormatCSVWMeta, datasetApiModels.DistributionMediaTypeCSVWMeta},
+}
+
+// Distribution maps an uploaded file to a distribution of a static dataset version, working out its format from the
+// file extension. The title defaults to the name of the file
+func Distribution(f upload.File, title string) (datasetApiModels.Distribution, error) {
+	ext := strings.ToLower(path.Ext(f.Path))
+	format, ok := distributionFormats[ext]
+	if !ok {
+		return datasetApiModels.Distribution{}, fmt.Errorf("files with the extension %q cannot be a distribution", ext)
+	}
+
+	if strings.TrimSpace(title) == "" {
+		title = path.Base(f.Path)
+	}
+
+	return datasetApiModels.Distribution{
+		Title:       title,
+		Format:      format.format,
+		MediaType:   format.mediaType,
+		DownloadURL: "/" + f.Path,
+		ByteSize:    f.SizeInBytes,
+	}, nil
+}
+
+// AddDistribution returns a copy of the distributions with d added to the end, or in place of the distribution with
+// the same download url if there is one
+func AddDistribution(distributions *[]datasetApiModels.Distribution, d datasetApiModels.Distribution) []datasetApiModels.Distribution {
+	updated := []datasetApiModels.Distribution{}
+	if distributions != nil {
+		updated = append(updated, *distributions...)
+	}
+
+	for i := range updated {
+		if updated[i].DownloadURL == d.DownloadURL {
+			updated[i] = d
+			return updated
+		}
+	}
+	return append(updated, d)
+}
+
+// FindDistribution returns the distribution with the download url of an uploaded file, or nil if it has not been
+// registered
+func FindDistribution(distributions *[]datasetApiModels.Distribution, f upload.File) *datasetApiModels.Distribution {
+	if distributions == nil {
+		return nil
+	}
+	for i := range *distributions {
+		if (*distributions)[i].DownloadURL == "/"+f.Path {
+			return &(*distributions)[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package mapper
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDistribution(t *testing.T) {
+	t.Parallel()
+
+	f := upload.File{Path: "cpih01/time-series/2/CPIH.XLSX", SizeInBytes: 2048}
+
+	Convey("test Distribution", t, func() {
+		Convey("works out the format from the extension and defaults the title", func() {
+			d, err := Distribution(f, " ")
+			So(err, ShouldBeNil)
+			So(d, ShouldResemble, models.Distribution{
+				Title:       "CPIH.XLSX",
+				Format:      models.DistributionFormatXLSX,
+				MediaType:   models.DistributionMediaTypeXLSX,
+				DownloadURL: "/cpih01/time-series/2/CPIH.XLSX",
+				ByteSize:    2048,
+			})
+		})
+
+		Convey("uses the title given", func() {
+			d, err := Distribution(upload.File{Path: "cpih01/time-series/2/cpih-metadata.json"}, "CSVW metadata")
+			So(err, ShouldBeNil)
+			So(d.Title, ShouldEqual, "CSVW metadata")
+			So(d.Format, ShouldEqual, models.DistributionFormatCSVWMeta)
+		})
+
+		Convey("errors for an extension that cannot be a distribution", func() {
+			_, err := Distribution(upload.File{Path: "cpih01/time-series/2/cpih.docx"}, "")
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("test AddDistribution", t, func() {
+		csv := models.Distribution{Title: "CSV", DownloadURL: "/cpih01/time-series/2/cpih.csv"}
+		xlsx := models.Distribution{Title: "XLSX", DownloadURL: "/cpih01/time-series/2/cpih.xlsx"}
+		existing := []models.Distribution{csv, xlsx}
+
+		Convey("adds a new distribution to the end", func() {
+			So(AddDistribution(nil, csv), ShouldResemble, []models.Distribution{csv})
+
+			sdmx := models.Distribution{Title: "SDMX", DownloadURL: "/cpih01/time-series/2/cpih.sdmx"}
+			So(AddDistribution(&existing, sdmx), ShouldResemble, []models.Distribution{csv, xlsx, sdmx})
+		})
+
+		Convey("replaces the distribution with the same download url in place", func() {
+			updated := models.Distribution{Title: "Updated CSV", DownloadURL: csv.DownloadURL}
+
+			So(AddDistribution(&existing, updated), ShouldResemble, []models.Distribution{updated, xlsx})
+			So(existing[0].Title, ShouldEqual, "CSV")
+		})
+
+		Convey("finds the distribution of a file", func() {
+			So(FindDistribution(&existing, upload.File{Path: "cpih01/time-series/2/cpih.xlsx"}).Title, ShouldEqual, "XLSX")
+			So(FindDistribution(&existing, upload.File{Path: "cpih01/time-series/2/cpih.sdmx"}), ShouldBeNil)
+			So(FindDistribution(nil, upload.File{Path: "cpih01/time-series/2/cpih.csv"}), ShouldBeNil)
+		})
+	})
+}
@@ -4,18 +4,17 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"time"
 
-	dataset "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
 	zebedee "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
 	babbageclient "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
 	"github.com/ONSdigital/log.go/v2/log"
-	"github.com/pkg/errors"
 )
 
 type related struct {
@@ -67,6 +66,7 @@ func AllVersions(ctx context.Context, datasetUpdate datasetApiModels.DatasetUpda
 			Version:     versions.Items[v].Version,
 			ReleaseDate: timeF,
 			State:       versions.Items[v].State,
+			Downloads:   mapDownloads(versions.Items[v]),
 		}
 	}
 
@@ -81,6 +81,65 @@ func AllVersions(ctx context.Context, datasetUpdate datasetApiModels.DatasetUpda
 	}
 }
 
+// mapDownloads returns the status of each of the download formats that a version is expected to have, whether or not
+// its file has been generated yet, followed by the files uploaded as distributions. A static version only has its
+// distributions.
+func mapDownloads(v datasetApiModels.Version) []model.VersionDownload {
+	mapped := []model.VersionDownload{}
+	if v.Type != datasetApiModels.Static.String() {
+		mapped = append(mapped, mapDownloadList(v.Downloads)...)
+	}
+
+	if v.Distributions != nil {
+		// uploaded files are held privately until the version is published
+		published := v.State == datasetApiModels.PublishedState
+		for _, d := range *v.Distributions {
+			mapped = append(mapped, model.VersionDownload{
+				Format:    string(d.Format),
+				Title:     d.Title,
+				Available: true,
+				Size:      d.ByteSize,
+				Private:   !published,
+				Public:    published,
+			})
+		}
+	}
+
+	return mapped
+}
+
+// mapDownloadList returns the status of each of the generated download formats
+func mapDownloadList(downloads *datasetApiModels.DownloadList) []model.VersionDownload {
+	if downloads == nil {
+		downloads = &datasetApiModels.DownloadList{}
+	}
+
+	formats := []struct {
+		name     string
+		download *datasetApiModels.DownloadObject
+	}{
+		{"csv", downloads.CSV},
+		{"xlsx", downloads.XLSX},
+		{"csvw", downloads.CSVW},
+		{"txt", downloads.TXT},
+	}
+
+	mapped := make([]model.VersionDownload, 0, len(formats))
+	for _, f := range formats {
+		d := model.VersionDownload{Format: f.name}
+		if f.download != nil {
+			d.Available = f.download.HRef != "" || f.download.Private != "" || f.download.Public != ""
+			d.Private = f.download.Private != ""
+			d.Public = f.download.Public != ""
+			// the dataset API holds the size in bytes as a string, which is left out if it is not a number
+			d.Size, _ = strconv.ParseInt(f.download.Size, 10, 64)
+		}
+		mapped = append(mapped, d)
+	}
+
+	return mapped
+}
+
 func EditMetadata(d *datasetApiModels.Dataset, v datasetApiModels.Version, dim []datasetApiModels.Dimension, c zebedee.Collection) model.EditMetadata {
 	mappedMetadata := model.EditMetadata{
 		Dataset:      *d,
@@ -149,40 +208,18 @@ func PutMetadata(m model.EditMetadata) datasetApiModels.EditableMetadata {
 	return metadata
 }
 
-func EditDatasetVersionMetaData(d dataset.DatasetDetails, v dataset.Version) (model.EditVersionMetaData, error) {
-	keywordsString := ""
-	if d.Keywords != nil {
-		keywords := *d.Keywords
-		keywordsString = strings.Join(keywords, ", ")
-	}
+// EditDatasetVersionMetaData maps a dataset and version to the simple list view model used by Florence to show the
+// metadata of a version, with each list entry given an id and simple list heading. Fields that cannot be mapped are
+// returned as warnings rather than failing the whole mapping
+func EditDatasetVersionMetaData(d datasetApiModels.Dataset, v datasetApiModels.Version) model.EditVersionMetaData {
+	notices, warnings := mapAlerts(v.Alerts)
 
 	relatedContent := mapRelatedContent(d.RelatedDatasets, d.Methodologies, d.Publications)
 
-	var contacts = []dataset.Contact{
-		{
-			Name:      "",
-			Telephone: "",
-			Email:     "",
-		},
-	}
-	if d.Contacts != nil {
-		contacts = *d.Contacts
-	}
-
-	releaseDate := model.ReleaseDate{
-		ReleaseDate: v.ReleaseDate,
-		Error:       "",
-	}
-
-	notices, err := mapAlerts(v)
-	if err != nil {
-		return model.EditVersionMetaData{}, errors.Wrap(err, "error whilst parsing alerts")
-	}
-
 	mappedMetaData := model.MetaData{
 		Edition:       v.Edition,
 		Version:       v.Version,
-		ReleaseDate:   releaseDate,
+		ReleaseDate:   model.ReleaseDate{ReleaseDate: v.ReleaseDate},
 		Notices:       notices,
 		Dimensions:    v.Dimensions,
 		UsageNotes:    mapUsageNotes(v.UsageNotes),
@@ -190,105 +227,143 @@ func EditDatasetVersionMetaData(d dataset.DatasetDetails, v dataset.Version) (mo
 
 		Title:                d.Title,
 		Summary:              d.Description,
-		Keywords:             keywordsString,
-		NationalStatistic:    d.NationalStatistic,
+		Keywords:             strings.Join(d.Keywords, ", "),
+		NationalStatistic:    d.NationalStatistic != nil && *d.NationalStatistic,
 		License:              d.License,
-		ContactName:          contacts[0].Name,
-		ContactEmail:         contacts[0].Email,
-		ContactTelephone:     contacts[0].Telephone,
+		Contacts:             mapContacts(d.Contacts),
 		RelatedDatasets:      relatedContent.datasets,
 		RelatedPublications:  relatedContent.publications,
 		RelatedMethodologies: relatedContent.methodologies,
 		ReleaseFrequency:     d.ReleaseFrequency,
 		NextReleaseDate:      d.NextRelease,
 		UnitOfMeassure:       d.UnitOfMeasure,
-		QMI:                  d.QMI.URL,
 	}
-	var mappedCollectionValue string
-	if v.CollectionID == "" {
+	if d.QMI != nil {
+		mappedMetaData.QMI = d.QMI.HRef
+	}
+
+	mappedCollectionValue := v.CollectionID
+	if mappedCollectionValue == "" {
 		mappedCollectionValue = "false"
-	} else {
-		mappedCollectionValue = v.CollectionID
 	}
-	mappedEditVersionMetaData := model.EditVersionMetaData{
+
+	return model.EditVersionMetaData{
 		MetaData:   mappedMetaData,
 		Collection: mappedCollectionValue,
 		InstanceID: v.ID,
 		Published:  v.State == "published",
+		Warnings:   warnings,
 	}
+}
 
-	return mappedEditVersionMetaData, nil
+func mapContacts(c []datasetApiModels.ContactDetails) []model.Contact {
+	contacts := make([]model.Contact, len(c))
+
+	for i, contact := range c {
+		contacts[i] = model.Contact{
+			ID:                    i,
+			Name:                  contact.Name,
+			Email:                 contact.Email,
+			Telephone:             contact.Telephone,
+			SimpleListHeading:     contact.Name,
+			SimpleListDescription: contact.Email,
+		}
+	}
+	return contacts
 }
 
-func mapRelatedContent(rd *[]dataset.RelatedDataset, rm *[]dataset.Methodology, rp *[]dataset.Publication) related {
+func mapRelatedContent(rd, rm, rp []datasetApiModels.GeneralDetails) related {
 	var relatedContent related
-	if rd != nil {
-		for i, content := range *rd {
-			relatedContent.datasets = append(relatedContent.datasets, model.RelatedContent{
-				ID:                i,
-				Title:             content.Title,
-				Href:              content.URL,
-				SimpleListHeading: content.Title,
-			})
-		}
+	for i, content := range rd {
+		relatedContent.datasets = append(relatedContent.datasets, model.RelatedContent{
+			ID:                i,
+			Title:             content.Title,
+			Href:              content.HRef,
+			SimpleListHeading: content.Title,
+		})
 	}
 
-	if rm != nil {
-		for i, content := range *rm {
-			relatedContent.methodologies = append(relatedContent.methodologies, model.RelatedContent{
-				ID:                    i,
-				Title:                 content.Title,
-				Description:           content.Description,
-				Href:                  content.URL,
-				SimpleListHeading:     content.Title,
-				SimpleListDescription: content.Description,
-			})
-		}
+	for i, content := range rm {
+		relatedContent.methodologies = append(relatedContent.methodologies, model.RelatedContent{
+			ID:                    i,
+			Title:                 content.Title,
+			Description:           content.Description,
+			Href:                  content.HRef,
+			SimpleListHeading:     content.Title,
+			SimpleListDescription: content.Description,
+		})
 	}
 
-	if rp != nil {
-		for i, content := range *rp {
-			relatedContent.publications = append(relatedContent.publications, model.RelatedContent{
-				ID:                    i,
-				Title:                 content.Title,
-				Description:           content.Description,
-				Href:                  content.URL,
-				SimpleListHeading:     content.Title,
-				SimpleListDescription: content.Description,
-			})
-		}
+	for i, content := range rp {
+		relatedContent.publications = append(relatedContent.publications, model.RelatedContent{
+			ID:                    i,
+			Title:                 content.Title,
+			Description:           content.Description,
+			Href:                  content.HRef,
+			SimpleListHeading:     content.Title,
+			SimpleListDescription: content.Description,
+		})
 	}
 	return relatedContent
 }
 
-func mapAlerts(v dataset.Version) ([]model.Notice, error) {
-	notices := make([]model.Notice, len(*v.Alerts))
+// alertDateFormats are the formats alert dates are accepted in, most precise first. Alert dates are written in
+// RFC3339, but alerts written before they were normalised can be in any of the others
+var alertDateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"02/01/2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
 
-	if v.Alerts == nil {
-		return notices, nil
+// ParseAlertDate parses the date of an alert in any of the formats alert dates are accepted in. It is used to read
+// alert dates, which are then written in RFC3339
+func ParseAlertDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+	for _, format := range alertDateFormats {
+		if t, err := time.Parse(format, date); err == nil {
+			return t, nil
+		}
 	}
-	for i, alert := range *v.Alerts {
-		alertDateInDateFormat, err := time.Parse(time.RFC3339Nano, alert.Date)
-		if err != nil {
-			return nil, errors.Wrap(err, "error whilst parsing time from alert date")
+	return time.Time{}, fmt.Errorf("unrecognised alert date %q", date)
+}
+
+// mapAlerts maps alerts to notices. An alert with a date that cannot be parsed is still listed, showing the date
+// as it was given, and a warning is returned for it
+func mapAlerts(alerts *[]datasetApiModels.Alert) ([]model.Notice, []model.FieldError) {
+	if alerts == nil {
+		return []model.Notice{}, nil
+	}
+
+	var warnings []model.FieldError
+	notices := make([]model.Notice, len(*alerts))
+	for i, alert := range *alerts {
+		noticeDate := alert.Date
+		if alertDate, err := ParseAlertDate(alert.Date); err == nil {
+			noticeDate = alertDate.Format("02 Jan 2006")
+		} else {
+			warnings = append(warnings, model.FieldError{
+				Path:    fmt.Sprintf("/alerts/%d/date", i),
+				Message: err.Error(),
+			})
 		}
 
-		noticeDate := alertDateInDateFormat.Format("02 Jan 2006")
-		simpleListHeading := fmt.Sprintf(`%s (%s)`, alert.Type, noticeDate)
 		notices[i] = model.Notice{
 			ID:                    i,
-			Type:                  alert.Type,
+			Type:                  alert.Type.String(),
 			Date:                  noticeDate,
 			Description:           alert.Description,
-			SimpleListHeading:     simpleListHeading,
+			SimpleListHeading:     fmt.Sprintf(`%s (%s)`, alert.Type, noticeDate),
 			SimpleListDescription: alert.Description,
 		}
 	}
 
-	return notices, nil
+	return notices, warnings
 }
 
-func mapUsageNotes(un *[]dataset.UsageNote) []model.UsageNote {
+func mapUsageNotes(un *[]datasetApiModels.UsageNote) []model.UsageNote {
 	//nolint:prealloc // Could result in a nil pointer exception if this slice is pre-allocated
 	var usageNotes []model.UsageNote
 	if un == nil {
@@ -307,10 +382,13 @@ func mapUsageNotes(un *[]dataset.UsageNote) []model.UsageNote {
 	return usageNotes
 }
 
-func mapLatestChanges(un []dataset.Change) []model.LatestChanges {
-	latestChanges := make([]model.LatestChanges, len(un))
+func mapLatestChanges(lc *[]datasetApiModels.LatestChange) []model.LatestChanges {
+	if lc == nil {
+		return []model.LatestChanges{}
+	}
 
-	for i, change := range un {
+	latestChanges := make([]model.LatestChanges, len(*lc))
+	for i, change := range *lc {
 		latestChanges[i] = model.LatestChanges{
 			ID:                    i,
 			Title:                 change.Name,
@@ -139,6 +139,10 @@ func TestUnitMapper(t *testing.T) {
 		Version:     2,
 		ReleaseDate: "2020-11-20T00:00:00.000Z",
 		State:       "published",
+		Downloads: &models.DownloadList{
+			CSV:  &models.DownloadObject{HRef: "http://localhost:23600/downloads/test-id-2.csv", Private: "s3://private/test-id-2.csv", Public: "https://public/test-id-2.csv", Size: "1024"},
+			XLSX: &models.DownloadObject{HRef: "http://localhost:23600/downloads/test-id-2.xlsx", Private: "s3://private/test-id-2.xlsx", Size: "unknown"},
+		},
 	})
 
 	mockedDataset := models.DatasetUpdate{
@@ -151,7 +155,15 @@ func TestUnitMapper(t *testing.T) {
 		Edition: "edition-1",
 	}
 
-	expectedAllVersions := []model.Version{{ID: "test-id-3", Title: "Version: 3", Version: 3, ReleaseDate: "", State: "edition-confirmed"}, {ID: "test-id-2", Title: "Version: 2 (published)", Version: 2, ReleaseDate: "20 November 2020", State: "published"}, {ID: "test-id-1", Title: "Version: 1 (published)", Version: 1, ReleaseDate: "07 November 2020", State: "published"}}
+	noDownloads := []model.VersionDownload{{Format: "csv"}, {Format: "xlsx"}, {Format: "csvw"}, {Format: "txt"}}
+	downloads := []model.VersionDownload{
+		{Format: "csv", Available: true, Size: 1024, Private: true, Public: true},
+		{Format: "xlsx", Available: true, Private: true},
+		{Format: "csvw"},
+		{Format: "txt"},
+	}
+
+	expectedAllVersions := []model.Version{{ID: "test-id-3", Title: "Version: 3", Version: 3, ReleaseDate: "", State: "edition-confirmed", Downloads: noDownloads}, {ID: "test-id-2", Title: "Version: 2 (published)", Version: 2, ReleaseDate: "20 November 2020", State: "published", Downloads: downloads}, {ID: "test-id-1", Title: "Version: 1 (published)", Version: 1, ReleaseDate: "07 November 2020", State: "published", Downloads: noDownloads}}
 
 	expectedVersionsPage := model.VersionsPage{DatasetName: "Test title", EditionName: "edition-1", Versions: expectedAllVersions}
 
@@ -160,6 +172,38 @@ func TestUnitMapper(t *testing.T) {
 			mapped := AllVersions(ctx, mockedDataset, mockedEdition, mockedAllVersions)
 			So(mapped, ShouldResemble, expectedVersionsPage)
 		})
+
+		Convey("maps the distributions of a static version", func() {
+			versions := datasetApiSdk.VersionsList{Items: []models.Version{{
+				ID:      "test-static",
+				Version: 1,
+				State:   "associated",
+				Type:    models.Static.String(),
+				Distributions: &[]models.Distribution{
+					{Title: "Full dataset", Format: models.DistributionFormatCSV, DownloadURL: "/uploads/full.csv", ByteSize: 2048},
+					{Title: "Tables", Format: models.DistributionFormatXLSX, DownloadURL: "/uploads/tables.xlsx", ByteSize: 4096},
+				},
+			}}}
+
+			mapped := AllVersions(ctx, mockedDataset, mockedEdition, versions)
+			So(mapped.Versions[0].Downloads, ShouldResemble, []model.VersionDownload{
+				{Format: "csv", Title: "Full dataset", Available: true, Size: 2048, Private: true},
+				{Format: "xlsx", Title: "Tables", Available: true, Size: 4096, Private: true},
+			})
+		})
+
+		Convey("marks the distributions of a published version as public", func() {
+			versions := datasetApiSdk.VersionsList{Items: []models.Version{{
+				ID:            "test-published",
+				Version:       1,
+				State:         "published",
+				Distributions: &[]models.Distribution{{Title: "Full dataset", Format: models.DistributionFormatCSV, ByteSize: 2048}},
+			}}}
+
+			mapped := AllVersions(ctx, mockedDataset, mockedEdition, versions)
+			So(mapped.Versions[0].Downloads, ShouldHaveLength, 5)
+			So(mapped.Versions[0].Downloads[4], ShouldResemble, model.VersionDownload{Format: "csv", Title: "Full dataset", Available: true, Size: 2048, Public: true})
+		})
 	})
 }
 
@@ -408,5 +452,104 @@ func TestMetadata(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("When we call EditDatasetVersionMetaData", func() {
+			summary := EditDatasetVersionMetaData(*mockDatasetDetails, mockVersion)
+
+			Convey("Then it returns the simple list view model of the version", func() {
+				So(summary.Warnings, ShouldBeEmpty)
+				So(summary.Collection, ShouldEqual, "foo")
+				So(summary.InstanceID, ShouldEqual, "bAz")
+				So(summary.Published, ShouldBeFalse)
+				So(summary.MetaData.Title, ShouldEqual, "fred")
+				So(summary.MetaData.Keywords, ShouldEqual, "foo, Bar, bAz")
+				So(summary.MetaData.QMI, ShouldEqual, "Bar")
+				So(summary.MetaData.Dimensions, ShouldResemble, mockDimensions)
+				So(summary.MetaData.Notices, ShouldResemble, []model.Notice{
+					{ID: 0, Type: "bAz", Date: "04 Feb 2020", Description: "Bar", SimpleListHeading: "bAz (04 Feb 2020)", SimpleListDescription: "Bar"},
+					{ID: 1, Type: "grault", Date: "02 Apr 2001", Description: "quux", SimpleListHeading: "grault (02 Apr 2001)", SimpleListDescription: "quux"},
+				})
+				So(summary.MetaData.LatestChanges[1], ShouldResemble, model.LatestChanges{ID: 1, Title: "quux", Description: "qux", SimpleListHeading: "quux", SimpleListDescription: "qux"})
+				So(summary.MetaData.UsageNotes, ShouldHaveLength, 2)
+				So(summary.MetaData.RelatedMethodologies[1].Href, ShouldEqual, "quux")
+				So(summary.MetaData.RelatedPublications, ShouldHaveLength, 2)
+				So(summary.MetaData.RelatedDatasets[0].SimpleListHeading, ShouldEqual, "Bar")
+			})
+
+			Convey("Then every contact is listed", func() {
+				So(summary.MetaData.Contacts, ShouldResemble, []model.Contact{
+					{ID: 0, Name: "foo", Email: "bAz", Telephone: "Bar", SimpleListHeading: "foo", SimpleListDescription: "bAz"},
+					{ID: 1, Name: "bad-foo", Email: "bad-bAz", Telephone: "bad-Bar", SimpleListHeading: "bad-foo", SimpleListDescription: "bad-bAz"},
+				})
+			})
+		})
+
+		Convey("When we call EditDatasetVersionMetaData for a version without lists", func() {
+			summary := EditDatasetVersionMetaData(models.Dataset{}, models.Version{State: "published"})
+
+			Convey("Then it returns empty lists", func() {
+				So(summary.Warnings, ShouldBeEmpty)
+				So(summary.Collection, ShouldEqual, "false")
+				So(summary.Published, ShouldBeTrue)
+				So(summary.MetaData.Notices, ShouldBeEmpty)
+				So(summary.MetaData.Contacts, ShouldBeEmpty)
+				So(summary.MetaData.QMI, ShouldBeEmpty)
+				So(summary.MetaData.NationalStatistic, ShouldBeFalse)
+			})
+		})
+
+		Convey("When we call EditDatasetVersionMetaData with an alert that has an invalid date", func() {
+			alerts := []models.Alert{
+				{Date: "2020-02-04", Description: "Bar", Type: "correction"},
+				{Date: "yesterday", Description: "quux", Type: "alert"},
+			}
+			summary := EditDatasetVersionMetaData(*mockDatasetDetails, models.Version{Alerts: &alerts})
+
+			Convey("Then the other alerts are still mapped", func() {
+				So(summary.MetaData.Title, ShouldEqual, "fred")
+				So(summary.MetaData.Notices, ShouldHaveLength, 2)
+				So(summary.MetaData.Notices[0].Date, ShouldEqual, "04 Feb 2020")
+			})
+
+			Convey("Then the invalid alert is listed with the date it was given and returned as a warning", func() {
+				So(summary.MetaData.Notices[1].Date, ShouldEqual, "yesterday")
+				So(summary.MetaData.Notices[1].SimpleListHeading, ShouldEqual, "alert (yesterday)")
+				So(summary.Warnings, ShouldResemble, []model.FieldError{
+					{Path: "/alerts/1/date", Message: `unrecognised alert date "yesterday"`},
+				})
+			})
+		})
+	})
+}
+
+func TestUnitParseAlertDate(t *testing.T) {
+	t.Parallel()
+
+	Convey("test ParseAlertDate", t, func() {
+		Convey("accepts each of the alert date formats", func() {
+			for _, date := range []string{
+				"2020-02-04T11:05:06.000Z",
+				"2020-02-04T11:05:06+01:00",
+				"2020-02-04T11:05:06",
+				"2020-02-04",
+				"04/02/2020",
+				"4 February 2020",
+				"4 Feb 2020",
+				" 2020-02-04 ",
+			} {
+				parsed, err := ParseAlertDate(date)
+
+				So(err, ShouldBeNil)
+				So(parsed.Format("2006-01-02"), ShouldEqual, "2020-02-04")
+			}
+		})
+
+		Convey("returns an error for an unrecognised or empty date", func() {
+			for _, date := range []string{"", "yesterday", "2020-13-01", "02-04-2020"} {
+				_, err := ParseAlertDate(date)
+
+				So(err, ShouldNotBeNil)
+			}
+		})
 	})
 }
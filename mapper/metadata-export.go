@@ -0,0 +1,338 @@
+package mapper
+
+import (
+	"strconv"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+const (
+	csvwContext   = "http://www.w3.org/ns/csvw"
+	schemaContext = "https://schema.org"
+)
+
+var dcatContext = map[string]string{
+	"dcat":  "http://www.w3.org/ns/dcat#",
+	"dct":   "http://purl.org/dc/terms/",
+	"foaf":  "http://xmlns.com/foaf/0.1/",
+	"vcard": "http://www.w3.org/2006/vcard/ns#",
+}
+
+// downloadMediaTypes are the media types of the files in a version's download list
+var downloadMediaTypes = map[string]string{
+	"csv":  "text/csv",
+	"csvw": "application/csvm+json",
+	"xls":  "application/vnd.ms-excel",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"txt":  "text/plain",
+}
+
+// download is a file a version can be downloaded as, from either its download list or its distributions
+type download struct {
+	title     string
+	format    string
+	mediaType string
+	url       string
+	size      int64
+}
+
+// CSVW maps a dataset and version to a CSV on the Web metadata document for the version's csv download, with a
+// column for each column of the V4 file the version was imported from
+func CSVW(d datasetApiModels.Dataset, v datasetApiModels.Version) model.CSVWMetadata {
+	m := model.CSVWMetadata{
+		Context:     csvwContext,
+		Title:       d.Title,
+		Description: d.Description,
+		Issued:      v.ReleaseDate,
+		Modified:    lastUpdated(v),
+		License:     d.License,
+		Keywords:    d.Keywords,
+		TableSchema: model.CSVWSchema{Columns: csvwColumns(v4Header(v), v.Dimensions)},
+	}
+
+	for _, dl := range downloads(v) {
+		if dl.format == "csv" {
+			m.URL = dl.url
+			break
+		}
+	}
+
+	if d.Publisher != nil {
+		m.Publisher = &model.CSVWPublisher{Name: d.Publisher.Name, URL: d.Publisher.HRef}
+	}
+
+	m.ContactPoint = vCards(d.Contacts)
+
+	return m
+}
+
+// v4Header returns the layout of the V4 file a version was imported from, which has a code list and label column
+// for each of the version's dimensions. The dataset API does not return the headers of the file, so it is taken to
+// have no data markings columns
+func v4Header(v datasetApiModels.Version) model.V4Header {
+	h := model.V4Header{Dimensions: []model.V4Dimension{}}
+	for _, dim := range v.Dimensions {
+		codeList := dim.Links.CodeList.ID
+		if codeList == "" {
+			codeList = dimensionName(dim)
+		}
+		name := dim.Name
+		if name == "" {
+			name = dimensionName(dim)
+		}
+		h.Dimensions = append(h.Dimensions, model.V4Dimension{CodeList: codeList, Name: name})
+	}
+	return h
+}
+
+// csvwColumns lists the columns of a V4 file in order: the observation, the data markings, and then the code list
+// and label of each dimension, titled by their headers in the file. A version with no dimensions has no columns.
+func csvwColumns(h model.V4Header, dimensions []datasetApiModels.Dimension) []model.CSVWColumn {
+	columns := []model.CSVWColumn{}
+	if len(h.Dimensions) == 0 {
+		return columns
+	}
+
+	columns = append(columns, model.CSVWColumn{
+		Name:        "observation",
+		Titles:      "V4_" + strconv.Itoa(h.DataMarkings),
+		Description: "The value of the observation",
+	})
+	for i := 1; i <= h.DataMarkings; i++ {
+		columns = append(columns, model.CSVWColumn{
+			Name:        "data_marking_" + strconv.Itoa(i),
+			Titles:      "data_marking_" + strconv.Itoa(i),
+			Description: "A marking of the observation, such as its reliability",
+		})
+	}
+
+	for i, dim := range h.Dimensions {
+		name, title, description := dim.Name, dim.Name, ""
+		if i < len(dimensions) {
+			name, title, description = dimensionName(dimensions[i]), dimensionTitle(dimensions[i]), dimensions[i].Description
+		}
+		columns = append(columns,
+			model.CSVWColumn{
+				Name:        name + "_code",
+				Titles:      dim.CodeList,
+				Description: "The code of the " + title + " in its code list",
+			},
+			model.CSVWColumn{
+				Name:        name,
+				Titles:      dim.Name,
+				Description: description,
+			},
+		)
+	}
+
+	return columns
+}
+
+// JSONLD maps a dataset and version to a schema.org Dataset JSON-LD document
+func JSONLD(d datasetApiModels.Dataset, v datasetApiModels.Version) model.SchemaDataset {
+	m := model.SchemaDataset{
+		Context:          schemaContext,
+		Type:             "Dataset",
+		ID:               versionURL(v),
+		Name:             d.Title,
+		Description:      d.Description,
+		Keywords:         d.Keywords,
+		License:          d.License,
+		DatePublished:    v.ReleaseDate,
+		DateModified:     lastUpdated(v),
+		Version:          versionNumber(v),
+		TemporalCoverage: temporalCoverage(v),
+	}
+
+	if d.Publisher != nil {
+		m.Publisher = &model.SchemaOrganization{Type: "Organization", Name: d.Publisher.Name, URL: d.Publisher.HRef}
+	}
+
+	for _, c := range d.Contacts {
+		m.ContactPoint = append(m.ContactPoint, model.SchemaContactPoint{
+			Type:      "ContactPoint",
+			Name:      c.Name,
+			Email:     c.Email,
+			Telephone: c.Telephone,
+		})
+	}
+
+	for _, dl := range downloads(v) {
+		m.Distribution = append(m.Distribution, model.SchemaDataDownload{
+			Type:           "DataDownload",
+			Name:           dl.title,
+			EncodingFormat: dl.mediaType,
+			ContentURL:     dl.url,
+			ContentSize:    dl.size,
+		})
+	}
+
+	for _, dim := range v.Dimensions {
+		m.VariableMeasured = append(m.VariableMeasured, model.SchemaPropertyValue{
+			Type:        "PropertyValue",
+			Name:        dimensionTitle(dim),
+			Description: dim.Description,
+		})
+	}
+
+	return m
+}
+
+// DCAT maps a dataset and version to a DCAT JSON-LD document
+func DCAT(d datasetApiModels.Dataset, v datasetApiModels.Version) model.DCATDataset {
+	m := model.DCATDataset{
+		Context:            dcatContext,
+		Type:               "dcat:Dataset",
+		ID:                 versionURL(v),
+		Title:              d.Title,
+		Description:        d.Description,
+		Keywords:           d.Keywords,
+		License:            d.License,
+		Issued:             v.ReleaseDate,
+		Modified:           lastUpdated(v),
+		Version:            versionNumber(v),
+		AccrualPeriodicity: d.ReleaseFrequency,
+		ContactPoint:       vCards(d.Contacts),
+	}
+
+	if v.Temporal != nil && len(*v.Temporal) > 0 {
+		t := (*v.Temporal)[0]
+		if t.StartDate != "" || t.EndDate != "" {
+			m.Temporal = &model.DCATPeriodOfTime{Type: "dct:PeriodOfTime", StartDate: t.StartDate, EndDate: t.EndDate}
+		}
+	}
+
+	if d.Publisher != nil {
+		m.Publisher = &model.DCATAgent{Type: "foaf:Agent", ID: d.Publisher.HRef, Name: d.Publisher.Name}
+	}
+
+	for _, dl := range downloads(v) {
+		m.Distribution = append(m.Distribution, model.DCATDistribution{
+			Type:        "dcat:Distribution",
+			Title:       dl.title,
+			DownloadURL: dl.url,
+			MediaType:   dl.mediaType,
+			ByteSize:    dl.size,
+		})
+	}
+
+	return m
+}
+
+// downloads lists the files in the version's download list, in the list's order, followed by its distributions
+func downloads(v datasetApiModels.Version) []download {
+	var dls []download
+
+	if v.Downloads != nil {
+		list := []struct {
+			format string
+			obj    *datasetApiModels.DownloadObject
+		}{
+			{"xls", v.Downloads.XLS},
+			{"xlsx", v.Downloads.XLSX},
+			{"csv", v.Downloads.CSV},
+			{"txt", v.Downloads.TXT},
+			{"csvw", v.Downloads.CSVW},
+		}
+		for _, item := range list {
+			if item.obj == nil {
+				continue
+			}
+			url := item.obj.Public
+			if url == "" {
+				url = item.obj.HRef
+			}
+			if url == "" {
+				continue
+			}
+			size, _ := strconv.ParseInt(item.obj.Size, 10, 64)
+			dls = append(dls, download{
+				title:     strings.ToUpper(item.format),
+				format:    item.format,
+				mediaType: downloadMediaTypes[item.format],
+				url:       url,
+				size:      size,
+			})
+		}
+	}
+
+	if v.Distributions != nil {
+		for _, dist := range *v.Distributions {
+			if dist.DownloadURL == "" {
+				continue
+			}
+			dls = append(dls, download{
+				title:     dist.Title,
+				format:    strings.ToLower(dist.Format.String()),
+				mediaType: dist.MediaType.String(),
+				url:       dist.DownloadURL,
+				size:      dist.ByteSize,
+			})
+		}
+	}
+
+	return dls
+}
+
+func vCards(contacts []datasetApiModels.ContactDetails) []model.VCard {
+	var cards []model.VCard
+	for _, c := range contacts {
+		card := model.VCard{Type: "vcard:Kind", Name: c.Name}
+		if c.Email != "" {
+			card.Email = "mailto:" + c.Email
+		}
+		if c.Telephone != "" {
+			card.Telephone = "tel:" + strings.ReplaceAll(c.Telephone, " ", "")
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+func versionURL(v datasetApiModels.Version) string {
+	if v.Links != nil && v.Links.Self != nil {
+		return v.Links.Self.HRef
+	}
+	return ""
+}
+
+func versionNumber(v datasetApiModels.Version) string {
+	if v.Version == 0 {
+		return ""
+	}
+	return strconv.Itoa(v.Version)
+}
+
+func lastUpdated(v datasetApiModels.Version) string {
+	if v.LastUpdated.IsZero() {
+		return ""
+	}
+	return v.LastUpdated.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func temporalCoverage(v datasetApiModels.Version) string {
+	if v.Temporal == nil || len(*v.Temporal) == 0 {
+		return ""
+	}
+	t := (*v.Temporal)[0]
+	if t.StartDate == "" && t.EndDate == "" {
+		return ""
+	}
+	return t.StartDate + "/" + t.EndDate
+}
+
+func dimensionName(dim datasetApiModels.Dimension) string {
+	if dim.ID != "" {
+		return dim.ID
+	}
+	return dim.Name
+}
+
+func dimensionTitle(dim datasetApiModels.Dimension) string {
+	if dim.Label != "" {
+		return dim.Label
+	}
+	return dim.Name
+}
@@ -0,0 +1,138 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitMetadataExport(t *testing.T) {
+	t.Parallel()
+
+	d := models.Dataset{
+		ID:               "cpih01",
+		Title:            "CPIH",
+		Description:      "Consumer prices including housing costs",
+		Keywords:         []string{"inflation", "prices"},
+		License:          "Open Government Licence v3.0",
+		ReleaseFrequency: "Monthly",
+		Publisher:        &models.Publisher{Name: "Office for National Statistics", HRef: "https://www.ons.gov.uk"},
+		Contacts: []models.ContactDetails{
+			{Name: "Prices team", Email: "prices@ons.gov.uk", Telephone: "+44 1633 456900"},
+			{Name: "Housing team", Email: "housing@ons.gov.uk"},
+		},
+	}
+	v := models.Version{
+		Edition:     "time-series",
+		Version:     2,
+		ReleaseDate: "2020-11-07T00:00:00.000Z",
+		LastUpdated: time.Date(2020, 11, 1, 9, 30, 0, 0, time.UTC),
+		Links:       &models.VersionLinks{Self: &models.LinkObject{HRef: "http://localhost:22000/datasets/cpih01/editions/time-series/versions/2"}},
+		Temporal:    &[]models.TemporalFrequency{{StartDate: "2005-01-01", EndDate: "2020-10-01", Frequency: "Monthly"}},
+		Dimensions: []models.Dimension{
+			{ID: "time", Name: "time", Label: "Time", Description: "The month of the observation", Links: models.DimensionLink{CodeList: models.LinkObject{ID: "mmm-yy"}}},
+			{Name: "aggregate"},
+		},
+		Downloads: &models.DownloadList{
+			XLSX: &models.DownloadObject{HRef: "http://localhost:23600/cpih01.xlsx", Size: "2048"},
+			CSV:  &models.DownloadObject{HRef: "http://localhost:23600/cpih01.csv", Public: "https://download.ons.gov.uk/cpih01.csv", Size: "1024"},
+			TXT:  &models.DownloadObject{},
+		},
+		Distributions: &[]models.Distribution{
+			{Title: "SDMX", Format: models.DistributionFormatSDMX, MediaType: "application/vnd.sdmx.structurespecificdata+xml", DownloadURL: "https://download.ons.gov.uk/cpih01.xml", ByteSize: 4096},
+		},
+	}
+
+	Convey("test CSVW", t, func() {
+		m := CSVW(d, v)
+
+		So(m.Context, ShouldEqual, "http://www.w3.org/ns/csvw")
+		So(m.URL, ShouldEqual, "https://download.ons.gov.uk/cpih01.csv")
+		So(m.Title, ShouldEqual, "CPIH")
+		So(m.Issued, ShouldEqual, "2020-11-07T00:00:00.000Z")
+		So(m.Modified, ShouldEqual, "2020-11-01T09:30:00Z")
+		So(m.Publisher, ShouldResemble, &model.CSVWPublisher{Name: "Office for National Statistics", URL: "https://www.ons.gov.uk"})
+		So(m.Keywords, ShouldResemble, []string{"inflation", "prices"})
+		So(m.ContactPoint, ShouldResemble, []model.VCard{
+			{Type: "vcard:Kind", Name: "Prices team", Email: "mailto:prices@ons.gov.uk", Telephone: "tel:+441633456900"},
+			{Type: "vcard:Kind", Name: "Housing team", Email: "mailto:housing@ons.gov.uk"},
+		})
+		So(m.TableSchema.Columns, ShouldResemble, []model.CSVWColumn{
+			{Name: "observation", Titles: "V4_0", Description: "The value of the observation"},
+			{Name: "time_code", Titles: "mmm-yy", Description: "The code of the Time in its code list"},
+			{Name: "time", Titles: "time", Description: "The month of the observation"},
+			{Name: "aggregate_code", Titles: "aggregate", Description: "The code of the aggregate in its code list"},
+			{Name: "aggregate", Titles: "aggregate"},
+		})
+
+		Convey("has a column for each data marking of the V4 layout", func() {
+			columns := csvwColumns(model.V4Header{DataMarkings: 1, Dimensions: []model.V4Dimension{{CodeList: "mmm-yy", Name: "time"}}}, nil)
+
+			So(columns, ShouldHaveLength, 4)
+			So(columns[0].Titles, ShouldEqual, "V4_1")
+			So(columns[1].Name, ShouldEqual, "data_marking_1")
+			So(columns[2], ShouldResemble, model.CSVWColumn{Name: "time_code", Titles: "mmm-yy", Description: "The code of the time in its code list"})
+			So(columns[3], ShouldResemble, model.CSVWColumn{Name: "time", Titles: "time"})
+		})
+
+		Convey("has an empty column list if the version has no dimensions", func() {
+			m := CSVW(models.Dataset{}, models.Version{})
+
+			So(m.TableSchema.Columns, ShouldNotBeNil)
+			So(m.TableSchema.Columns, ShouldBeEmpty)
+			So(m.URL, ShouldBeEmpty)
+		})
+	})
+
+	Convey("test JSONLD", t, func() {
+		m := JSONLD(d, v)
+
+		So(m.Context, ShouldEqual, "https://schema.org")
+		So(m.Type, ShouldEqual, "Dataset")
+		So(m.ID, ShouldEqual, "http://localhost:22000/datasets/cpih01/editions/time-series/versions/2")
+		So(m.Name, ShouldEqual, "CPIH")
+		So(m.Version, ShouldEqual, "2")
+		So(m.DatePublished, ShouldEqual, "2020-11-07T00:00:00.000Z")
+		So(m.TemporalCoverage, ShouldEqual, "2005-01-01/2020-10-01")
+		So(m.Publisher, ShouldResemble, &model.SchemaOrganization{Type: "Organization", Name: "Office for National Statistics", URL: "https://www.ons.gov.uk"})
+		So(m.ContactPoint, ShouldHaveLength, 2)
+		So(m.ContactPoint[0], ShouldResemble, model.SchemaContactPoint{Type: "ContactPoint", Name: "Prices team", Email: "prices@ons.gov.uk", Telephone: "+44 1633 456900"})
+		So(m.Distribution, ShouldResemble, []model.SchemaDataDownload{
+			{Type: "DataDownload", Name: "XLSX", EncodingFormat: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ContentURL: "http://localhost:23600/cpih01.xlsx", ContentSize: 2048},
+			{Type: "DataDownload", Name: "CSV", EncodingFormat: "text/csv", ContentURL: "https://download.ons.gov.uk/cpih01.csv", ContentSize: 1024},
+			{Type: "DataDownload", Name: "SDMX", EncodingFormat: "application/vnd.sdmx.structurespecificdata+xml", ContentURL: "https://download.ons.gov.uk/cpih01.xml", ContentSize: 4096},
+		})
+		So(m.VariableMeasured, ShouldResemble, []model.SchemaPropertyValue{
+			{Type: "PropertyValue", Name: "Time", Description: "The month of the observation"},
+			{Type: "PropertyValue", Name: "aggregate"},
+		})
+	})
+
+	Convey("test DCAT", t, func() {
+		m := DCAT(d, v)
+
+		So(m.Context["dcat"], ShouldEqual, "http://www.w3.org/ns/dcat#")
+		So(m.Type, ShouldEqual, "dcat:Dataset")
+		So(m.Title, ShouldEqual, "CPIH")
+		So(m.Issued, ShouldEqual, "2020-11-07T00:00:00.000Z")
+		So(m.AccrualPeriodicity, ShouldEqual, "Monthly")
+		So(m.Temporal, ShouldResemble, &model.DCATPeriodOfTime{Type: "dct:PeriodOfTime", StartDate: "2005-01-01", EndDate: "2020-10-01"})
+		So(m.Publisher, ShouldResemble, &model.DCATAgent{Type: "foaf:Agent", ID: "https://www.ons.gov.uk", Name: "Office for National Statistics"})
+		So(m.ContactPoint, ShouldHaveLength, 2)
+		So(m.Distribution, ShouldHaveLength, 3)
+		So(m.Distribution[1], ShouldResemble, model.DCATDistribution{Type: "dcat:Distribution", Title: "CSV", DownloadURL: "https://download.ons.gov.uk/cpih01.csv", MediaType: "text/csv", ByteSize: 1024})
+
+		Convey("leaves out optional sections the version does not have", func() {
+			m := DCAT(models.Dataset{Title: "CPIH"}, models.Version{})
+
+			So(m.Temporal, ShouldBeNil)
+			So(m.Publisher, ShouldBeNil)
+			So(m.Distribution, ShouldBeEmpty)
+			So(m.Version, ShouldBeEmpty)
+			So(m.Modified, ShouldBeEmpty)
+		})
+	})
+}
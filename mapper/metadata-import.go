@@ -0,0 +1,216 @@
+package mapper
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+)
+
+// MetadataImportFromCSV reads a metadata import from a csv file with a field and a value column. Keywords are
+// given one per row, numbered fields such as contact.1.email group the values of one contact, usage note or
+// alert, and dimension.<name>.label or dimension.<name>.description rows relabel a dimension
+func MetadataImportFromCSV(r io.Reader) (model.MetadataImport, error) {
+	var m model.MetadataImport
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return m, fmt.Errorf("error reading csv header: %w", err)
+	}
+	if !strings.EqualFold(strings.TrimSpace(header[0]), "field") || !strings.EqualFold(strings.TrimSpace(header[1]), "value") {
+		return m, errors.New(`csv header must be "field,value"`)
+	}
+
+	contacts := map[int]*datasetApiModels.ContactDetails{}
+	usageNotes := map[int]*datasetApiModels.UsageNote{}
+	alerts := map[int]*datasetApiModels.Alert{}
+	dimensions := map[string]*model.ImportedDimension{}
+	var dimensionOrder []string
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, fmt.Errorf("error reading csv: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		field := strings.TrimSpace(record[0])
+		value := strings.TrimSpace(record[1])
+
+		switch strings.ToLower(field) {
+		case "title":
+			m.Title = &value
+			continue
+		case "description":
+			m.Description = &value
+			continue
+		case "keyword":
+			m.Keywords = append(m.Keywords, value)
+			continue
+		}
+
+		// numbered and dimension fields have three parts, of which only the middle one is case sensitive
+		parts := strings.SplitN(field, ".", 3)
+		if len(parts) != 3 {
+			return m, fmt.Errorf("line %d: unknown field %q", line, field)
+		}
+		parts[0], parts[2] = strings.ToLower(parts[0]), strings.ToLower(parts[2])
+
+		if parts[0] == "dimension" {
+			dim, ok := dimensions[parts[1]]
+			if !ok {
+				dim = &model.ImportedDimension{Name: parts[1]}
+				dimensions[parts[1]] = dim
+				dimensionOrder = append(dimensionOrder, parts[1])
+			}
+			switch parts[2] {
+			case "label":
+				dim.Label = value
+			case "description":
+				dim.Description = value
+			default:
+				return m, fmt.Errorf("line %d: unknown field %q", line, field)
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 1 {
+			return m, fmt.Errorf("line %d: %q must be numbered from 1", line, field)
+		}
+
+		switch {
+		case parts[0] == "contact" && parts[2] == "name":
+			contact(contacts, n).Name = value
+		case parts[0] == "contact" && parts[2] == "email":
+			contact(contacts, n).Email = value
+		case parts[0] == "contact" && parts[2] == "telephone":
+			contact(contacts, n).Telephone = value
+		case parts[0] == "usage_note" && parts[2] == "title":
+			usageNote(usageNotes, n).Title = value
+		case parts[0] == "usage_note" && parts[2] == "note":
+			usageNote(usageNotes, n).Note = value
+		case parts[0] == "alert" && parts[2] == "date":
+			alert(alerts, n).Date = value
+		case parts[0] == "alert" && parts[2] == "description":
+			alert(alerts, n).Description = value
+		case parts[0] == "alert" && parts[2] == "type":
+			alert(alerts, n).Type = datasetApiModels.AlertType(value)
+		default:
+			return m, fmt.Errorf("line %d: unknown field %q", line, field)
+		}
+	}
+
+	for _, n := range sortedKeys(contacts) {
+		m.Contacts = append(m.Contacts, *contacts[n])
+	}
+	for _, n := range sortedKeys(usageNotes) {
+		m.UsageNotes = append(m.UsageNotes, *usageNotes[n])
+	}
+	for _, n := range sortedKeys(alerts) {
+		m.Alerts = append(m.Alerts, *alerts[n])
+	}
+	for _, name := range dimensionOrder {
+		m.Dimensions = append(m.Dimensions, *dimensions[name])
+	}
+
+	return m, nil
+}
+
+// ImportMetadata applies a metadata import to the current editable metadata of a version. Dimensions in the
+// import that the version does not have are ignored, so the import should be validated first
+func ImportMetadata(current datasetApiModels.EditableMetadata, m model.MetadataImport) datasetApiModels.EditableMetadata {
+	metadata := current
+
+	if m.Title != nil {
+		metadata.Title = *m.Title
+	}
+	if m.Description != nil {
+		metadata.Description = *m.Description
+	}
+	if m.Keywords != nil {
+		metadata.Keywords = m.Keywords
+	}
+	if m.Contacts != nil {
+		metadata.Contacts = m.Contacts
+	}
+	if m.UsageNotes != nil {
+		usageNotes := m.UsageNotes
+		metadata.UsageNotes = &usageNotes
+	}
+	if m.Alerts != nil {
+		alerts := make([]datasetApiModels.Alert, len(m.Alerts))
+		for i, alert := range m.Alerts {
+			// dates are accepted in any of the alert date formats, but always written in one
+			if date, err := ParseAlertDate(alert.Date); err == nil {
+				alert.Date = date.Format(time.RFC3339)
+			}
+			alerts[i] = alert
+		}
+		metadata.Alerts = &alerts
+	}
+
+	if len(m.Dimensions) > 0 {
+		// copied so that the current metadata's dimensions are left as they were
+		metadata.Dimensions = append([]datasetApiModels.Dimension(nil), current.Dimensions...)
+		for _, imported := range m.Dimensions {
+			for i, dim := range metadata.Dimensions {
+				if dim.Name != imported.Name && dim.ID != imported.Name {
+					continue
+				}
+				if imported.Label != "" {
+					metadata.Dimensions[i].Label = imported.Label
+				}
+				if imported.Description != "" {
+					metadata.Dimensions[i].Description = imported.Description
+				}
+			}
+		}
+	}
+
+	return metadata
+}
+
+func contact(contacts map[int]*datasetApiModels.ContactDetails, n int) *datasetApiModels.ContactDetails {
+	if contacts[n] == nil {
+		contacts[n] = &datasetApiModels.ContactDetails{}
+	}
+	return contacts[n]
+}
+
+func usageNote(usageNotes map[int]*datasetApiModels.UsageNote, n int) *datasetApiModels.UsageNote {
+	if usageNotes[n] == nil {
+		usageNotes[n] = &datasetApiModels.UsageNote{}
+	}
+	return usageNotes[n]
+}
+
+func alert(alerts map[int]*datasetApiModels.Alert, n int) *datasetApiModels.Alert {
+	if alerts[n] == nil {
+		alerts[n] = &datasetApiModels.Alert{}
+	}
+	return alerts[n]
+}
+
+func sortedKeys[T any](m map[int]T) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
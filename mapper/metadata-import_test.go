@@ -0,0 +1,138 @@
+package mapper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitMetadataImportFromCSV(t *testing.T) {
+	t.Parallel()
+
+	Convey("test MetadataImportFromCSV", t, func() {
+		Convey("reads every field of a metadata file", func() {
+			file := `field,value
+title,CPIH
+description,"Consumer prices, including housing costs"
+keyword,inflation
+keyword,prices
+contact.2.name,Housing team
+contact.1.name,Prices team
+contact.1.email,prices@ons.gov.uk
+usage_note.1.title,Weights
+usage_note.1.note,Weights are updated every year
+alert.1.date,2020-11-07T00:00:00Z
+alert.1.description,Corrected weights
+alert.1.type,correction
+dimension.Aggregate.label,Special aggregate
+Dimension.Aggregate.Description,The goods and services in the basket
+`
+			m, err := MetadataImportFromCSV(strings.NewReader(file))
+
+			So(err, ShouldBeNil)
+			So(*m.Title, ShouldEqual, "CPIH")
+			So(*m.Description, ShouldEqual, "Consumer prices, including housing costs")
+			So(m.Keywords, ShouldResemble, []string{"inflation", "prices"})
+			So(m.Contacts, ShouldResemble, []models.ContactDetails{
+				{Name: "Prices team", Email: "prices@ons.gov.uk"},
+				{Name: "Housing team"},
+			})
+			So(m.UsageNotes, ShouldResemble, []models.UsageNote{{Title: "Weights", Note: "Weights are updated every year"}})
+			So(m.Alerts, ShouldResemble, []models.Alert{{Date: "2020-11-07T00:00:00Z", Description: "Corrected weights", Type: models.AlertTypeCorrection}})
+			So(m.Dimensions, ShouldResemble, []model.ImportedDimension{
+				{Name: "Aggregate", Label: "Special aggregate", Description: "The goods and services in the basket"},
+			})
+		})
+
+		Convey("leaves out the fields the file does not have", func() {
+			m, err := MetadataImportFromCSV(strings.NewReader("field,value\nkeyword,inflation\n"))
+
+			So(err, ShouldBeNil)
+			So(m.Title, ShouldBeNil)
+			So(m.Contacts, ShouldBeNil)
+			So(m.Keywords, ShouldResemble, []string{"inflation"})
+		})
+
+		Convey("returns an error if the header is wrong", func() {
+			_, err := MetadataImportFromCSV(strings.NewReader("name,value\ntitle,CPIH\n"))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `csv header must be "field,value"`)
+		})
+
+		Convey("returns an error naming the line of an unknown field", func() {
+			_, err := MetadataImportFromCSV(strings.NewReader("field,value\ntitle,CPIH\ncontact.1.fax,01633\n"))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `line 3: unknown field "contact.1.fax"`)
+		})
+
+		Convey("returns an error if a field is not numbered", func() {
+			_, err := MetadataImportFromCSV(strings.NewReader("field,value\nalert.first.date,2020-11-07\n"))
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, `line 2: "alert.first.date" must be numbered from 1`)
+		})
+
+		Convey("returns an error if a row does not have two columns", func() {
+			_, err := MetadataImportFromCSV(strings.NewReader("field,value\ntitle,CPIH,extra\n"))
+
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitImportMetadata(t *testing.T) {
+	t.Parallel()
+
+	current := models.EditableMetadata{
+		Title:      "CPIH",
+		License:    "Open Government Licence v3.0",
+		Keywords:   []string{"inflation"},
+		Contacts:   []models.ContactDetails{{Name: "Prices team"}},
+		Dimensions: []models.Dimension{{ID: "aggregate", Name: "aggregate", Label: "Aggregate"}, {Name: "time", Label: "Time"}},
+	}
+
+	Convey("test ImportMetadata", t, func() {
+		title := "Consumer Prices Index including owner occupiers' housing costs"
+		m := ImportMetadata(current, model.MetadataImport{
+			Title:      &title,
+			UsageNotes: []models.UsageNote{{Title: "Weights", Note: "Updated every year"}},
+			Dimensions: []model.ImportedDimension{{Name: "aggregate", Label: "Special aggregate"}},
+		})
+
+		So(m.Title, ShouldEqual, title)
+		So(m.License, ShouldEqual, "Open Government Licence v3.0")
+		So(m.Keywords, ShouldResemble, []string{"inflation"})
+		So(m.Contacts, ShouldResemble, []models.ContactDetails{{Name: "Prices team"}})
+		So(*m.UsageNotes, ShouldResemble, []models.UsageNote{{Title: "Weights", Note: "Updated every year"}})
+		So(m.Alerts, ShouldBeNil)
+		So(m.Dimensions, ShouldResemble, []models.Dimension{{ID: "aggregate", Name: "aggregate", Label: "Special aggregate"}, {Name: "time", Label: "Time"}})
+
+		Convey("does not change the current metadata's dimensions", func() {
+			So(current.Dimensions[0].Label, ShouldEqual, "Aggregate")
+		})
+
+		Convey("writes alert dates in RFC3339", func() {
+			m := ImportMetadata(current, model.MetadataImport{Alerts: []models.Alert{
+				{Date: "07/11/2020", Description: "Corrected weights", Type: models.AlertTypeCorrection},
+				{Date: "2020-11-20T09:30:00Z", Description: "Revised index", Type: models.AlertTypeCorrection},
+			}})
+
+			So(*m.Alerts, ShouldHaveLength, 2)
+			So((*m.Alerts)[0].Date, ShouldEqual, "2020-11-07T00:00:00Z")
+			So((*m.Alerts)[0].Description, ShouldEqual, "Corrected weights")
+			So((*m.Alerts)[1].Date, ShouldEqual, "2020-11-20T09:30:00Z")
+		})
+
+		Convey("replaces a list given in the import, even if it is empty", func() {
+			m := ImportMetadata(current, model.MetadataImport{Keywords: []string{}})
+
+			So(m.Keywords, ShouldBeEmpty)
+			So(m.Title, ShouldEqual, "CPIH")
+		})
+	})
+}
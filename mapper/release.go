@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"fmt"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+)
+
+const releaseDateLayout = "2 January 2006"
+
+// VersionRelease maps the release a version is linked to, flagging a version whose release date does not match it
+func VersionRelease(v datasetApiModels.Version, r releases.Release) model.VersionRelease {
+	releaseDate := r.ReleaseDate
+	vr := model.VersionRelease{
+		ID:          r.ID,
+		Title:       r.Title,
+		ReleaseDate: &releaseDate,
+		URI:         r.URI,
+		Cancelled:   r.Cancelled,
+	}
+
+	if strings.TrimSpace(v.ReleaseDate) == "" {
+		vr.ReleaseDateMismatch = true
+		vr.Warning = fmt.Sprintf("the version has no release date, but the release is on %s", r.ReleaseDate.UTC().Format(releaseDateLayout))
+		return vr
+	}
+
+	matches, err := r.MatchesReleaseDate(v.ReleaseDate)
+	switch {
+	case err != nil:
+		vr.ReleaseDateMismatch = true
+		vr.Warning = fmt.Sprintf("the release date of the version, %q, cannot be compared with the release", v.ReleaseDate)
+	case !matches:
+		vr.ReleaseDateMismatch = true
+		vr.Warning = fmt.Sprintf("the release date of the version, %q, is not the date of the release, %s", v.ReleaseDate, r.ReleaseDate.UTC().Format(releaseDateLayout))
+	}
+
+	return vr
+}
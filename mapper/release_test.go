@@ -0,0 +1,49 @@
+package mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-dataset-api/models"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitVersionRelease(t *testing.T) {
+	t.Parallel()
+
+	r := releases.Release{
+		ID:          "cpih-jan-2020",
+		Title:       "Consumer price inflation, UK: January 2020",
+		ReleaseDate: time.Date(2020, 2, 19, 7, 0, 0, 0, time.UTC),
+		URI:         "/releases/consumerpriceinflationukjanuary2020",
+	}
+
+	Convey("test VersionRelease", t, func() {
+		Convey("maps a release on the version's release date", func() {
+			vr := VersionRelease(models.Version{ReleaseDate: "2020-02-19T00:00:00.000Z"}, r)
+			So(vr.ID, ShouldEqual, r.ID)
+			So(vr.Title, ShouldEqual, r.Title)
+			So(*vr.ReleaseDate, ShouldEqual, r.ReleaseDate)
+			So(vr.URI, ShouldEqual, r.URI)
+			So(vr.ReleaseDateMismatch, ShouldBeFalse)
+			So(vr.Warning, ShouldBeEmpty)
+		})
+
+		Convey("flags a version released on another day", func() {
+			vr := VersionRelease(models.Version{ReleaseDate: "2020-02-20T00:00:00.000Z"}, r)
+			So(vr.ReleaseDateMismatch, ShouldBeTrue)
+			So(vr.Warning, ShouldEqual, `the release date of the version, "2020-02-20T00:00:00.000Z", is not the date of the release, 19 February 2020`)
+		})
+
+		Convey("flags a version without a release date or with one that cannot be read", func() {
+			vr := VersionRelease(models.Version{}, r)
+			So(vr.ReleaseDateMismatch, ShouldBeTrue)
+			So(vr.Warning, ShouldEqual, "the version has no release date, but the release is on 19 February 2020")
+
+			vr = VersionRelease(models.Version{ReleaseDate: "February"}, r)
+			So(vr.ReleaseDateMismatch, ShouldBeTrue)
+			So(vr.Warning, ShouldEqual, `the release date of the version, "February", cannot be compared with the release`)
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "dataset_controller"
+
+// Upstream names used to label upstream metrics
+const (
+	UpstreamDatasetAPI = "dataset-api"
+	UpstreamZebedee    = "zebedee"
+	UpstreamBabbage    = "babbage"
+	UpstreamDownload   = "download-service"
+	UpstreamIdentity   = "identity-api"
+)
+
+// Outcomes of an upstream call or batch fetch
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// unmatchedRoute labels requests that did not match a registered route
+const unmatchedRoute = "unmatched"
+
+// Metrics holds the prometheus collectors for the service's handlers and upstream clients
+type Metrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+	batchDuration    *prometheus.HistogramVec
+	batchItems       *prometheus.HistogramVec
+}
+
+// New creates the service metrics, registered on their own registry along with the go and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests handled, by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "Number of calls made to upstream services, by upstream, client method and outcome.",
+		}, []string{"upstream", "method", "outcome"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Time taken by calls to upstream services, by upstream, client method and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"upstream", "method", "outcome"}),
+		batchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_fetch_duration_seconds",
+			Help:      "Time taken to fetch all pages of a batched upstream call, by operation and outcome.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 30},
+		}, []string{"operation", "outcome"}),
+		batchItems: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_fetch_items",
+			Help:      "Number of items returned by a successful batched upstream call, by operation.",
+			Buckets:   prometheus.ExponentialBuckets(10, 2, 10),
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		m.batchDuration,
+		m.batchItems,
+	)
+
+	return m
+}
+
+// Handler returns the handler serving the metrics in the prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records the count, status code and latency of requests, labelled with the matched route template
+// so that path parameters do not create a series per dataset
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, req)
+
+		route := routeTemplate(req)
+		m.requests.WithLabelValues(route, req.Method, strconv.Itoa(rec.status)).Inc()
+		m.requestDuration.WithLabelValues(route, req.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// InstrumentUnmatched records requests the router does not match a route for, which are not passed through the
+// middleware it uses. They are counted as 404 and 405 responses against the unmatched route.
+func (m *Metrics) InstrumentUnmatched(router *mux.Router) {
+	router.NotFoundHandler = m.Middleware(http.NotFoundHandler())
+	router.MethodNotAllowedHandler = m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+}
+
+// ObserveUpstream records a call to an upstream service that started at start and returned err
+func (m *Metrics) ObserveUpstream(upstream, method string, start time.Time, err error) {
+	outcome := outcomeOf(err)
+	m.upstreamRequests.WithLabelValues(upstream, method, outcome).Inc()
+	m.upstreamDuration.WithLabelValues(upstream, method, outcome).Observe(time.Since(start).Seconds())
+}
+
+// ObserveBatch records a batched fetch that started at start and returned items items and err
+func (m *Metrics) ObserveBatch(operation string, start time.Time, items int, err error) {
+	outcome := outcomeOf(err)
+	m.batchDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+	if err == nil {
+		m.batchItems.WithLabelValues(operation).Observe(float64(items))
+	}
+}
+
+func outcomeOf(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+func routeTemplate(req *http.Request) string {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return unmatchedRoute
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+	return tmpl
+}
+
+// statusRecorder keeps the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
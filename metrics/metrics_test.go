@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a router using the metrics middleware", t, func() {
+		m := New()
+		router := mux.NewRouter()
+		router.Use(m.Middleware)
+		router.Path("/datasets/{datasetID}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		Convey("When requests are made for different datasets", func() {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/datasets/cpih01", http.NoBody))
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/datasets/cpih02", http.NoBody))
+
+			Convey("Then they are counted against the route template with their status code", func() {
+				So(testutil.ToFloat64(m.requests.WithLabelValues("/datasets/{datasetID}", "GET", "404")), ShouldEqual, 2)
+				So(testutil.CollectAndCount(m.requestDuration), ShouldEqual, 1)
+			})
+		})
+
+		Convey("When requests are made that do not match a route", func() {
+			m.InstrumentUnmatched(router)
+			router.Path("/metrics").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodGet)
+
+			notFound := httptest.NewRecorder()
+			router.ServeHTTP(notFound, httptest.NewRequest("GET", "/unknown/cpih01", http.NoBody))
+			methodNotAllowed := httptest.NewRecorder()
+			router.ServeHTTP(methodNotAllowed, httptest.NewRequest("POST", "/metrics", http.NoBody))
+
+			Convey("Then they are counted against the unmatched route with a 404 or 405", func() {
+				So(notFound.Code, ShouldEqual, http.StatusNotFound)
+				So(methodNotAllowed.Code, ShouldEqual, http.StatusMethodNotAllowed)
+				So(testutil.ToFloat64(m.requests.WithLabelValues("unmatched", "GET", "404")), ShouldEqual, 1)
+				So(testutil.ToFloat64(m.requests.WithLabelValues("unmatched", "POST", "405")), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestObserveUpstream(t *testing.T) {
+	Convey("Given metrics", t, func() {
+		m := New()
+
+		Convey("When upstream calls succeed and fail", func() {
+			m.ObserveUpstream(UpstreamDatasetAPI, "GetVersion", time.Now(), nil)
+			m.ObserveUpstream(UpstreamDatasetAPI, "GetVersion", time.Now(), errors.New("upstream error"))
+			m.ObserveUpstream(UpstreamZebedee, "GetCollection", time.Now(), nil)
+
+			Convey("Then each is counted by upstream, method and outcome", func() {
+				So(testutil.ToFloat64(m.upstreamRequests.WithLabelValues(UpstreamDatasetAPI, "GetVersion", OutcomeSuccess)), ShouldEqual, 1)
+				So(testutil.ToFloat64(m.upstreamRequests.WithLabelValues(UpstreamDatasetAPI, "GetVersion", OutcomeError)), ShouldEqual, 1)
+				So(testutil.ToFloat64(m.upstreamRequests.WithLabelValues(UpstreamZebedee, "GetCollection", OutcomeSuccess)), ShouldEqual, 1)
+				So(testutil.CollectAndCount(m.upstreamDuration), ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestObserveBatch(t *testing.T) {
+	Convey("Given metrics", t, func() {
+		m := New()
+
+		Convey("When a batch fetch succeeds and another fails", func() {
+			m.ObserveBatch("GetDatasetsInBatches", time.Now(), 250, nil)
+			m.ObserveBatch("GetDatasetsInBatches", time.Now(), 0, errors.New("upstream error"))
+
+			Convey("Then both durations are recorded but only the successful item count", func() {
+				So(testutil.CollectAndCount(m.batchDuration), ShouldEqual, 2)
+				So(testutil.CollectAndCount(m.batchItems), ShouldEqual, 1)
+			})
+		})
+	})
+}
+
+func TestHandler(t *testing.T) {
+	Convey("Given metrics with a recorded upstream call", t, func() {
+		m := New()
+		m.ObserveUpstream(UpstreamBabbage, "GetTopics", time.Now(), nil)
+
+		Convey("When the metrics handler is called", func() {
+			w := httptest.NewRecorder()
+			m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", http.NoBody))
+
+			Convey("Then the metrics are served in the exposition format", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(strings.Contains(w.Body.String(), `dataset_controller_upstream_requests_total{method="GetTopics",outcome="success",upstream="babbage"} 1`), ShouldBeTrue)
+			})
+		})
+	})
+}
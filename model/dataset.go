@@ -1,7 +1,8 @@
 package model
 
 import (
-	"github.com/ONSdigital/dp-api-clients-go/v2/dataset"
+	"time"
+
 	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
 )
 
@@ -27,11 +28,23 @@ type VersionsPage struct {
 	Versions    []Version `json:"versions"`
 }
 type Version struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Version     int    `json:"version"`
-	ReleaseDate string `json:"release_date"`
-	State       string `json:"state"`
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Version     int               `json:"version"`
+	ReleaseDate string            `json:"release_date"`
+	State       string            `json:"state"`
+	Downloads   []VersionDownload `json:"downloads"`
+}
+
+// VersionDownload is the status of one of the download formats or uploaded files of a version. A file that has been
+// generated or uploaded has a private link, and a public link once it has been published
+type VersionDownload struct {
+	Format    string `json:"format"`
+	Title     string `json:"title,omitempty"`
+	Available bool   `json:"available"`
+	Size      int64  `json:"size,omitempty"`
+	Private   bool   `json:"private"`
+	Public    bool   `json:"public"`
 }
 
 type EditMetadata struct {
@@ -42,32 +55,98 @@ type EditMetadata struct {
 	CollectionState        string                       `json:"collection_state"`
 	CollectionLastEditedBy string                       `json:"collection_last_edited_by"`
 	VersionEtag            string                       `json:"version_etag"`
+	Release                *VersionRelease              `json:"release,omitempty"`
+}
+
+// VersionState is the state a version is moved to, and the state to put it in its collection in
+type VersionState struct {
+	State           string `json:"state"`
+	CollectionState string `json:"collection_state,omitempty"`
+}
+
+// CollectionMove names the collection a version is moved to
+type CollectionMove struct {
+	CollectionID string `json:"collection_id"`
+}
+
+// EditDimension is the editable metadata of a single dimension of a version
+type EditDimension struct {
+	Label                string `json:"label"`
+	Description          string `json:"description"`
+	QualityStatementText string `json:"quality_statement_text"`
+	QualityStatementURL  string `json:"quality_statement_url"`
+	NumberOfOptions      *int   `json:"number_of_options"`
+}
+
+// DimensionOptions is a page of the options of a dimension of a version. The dataset API does not hold the
+// hierarchy of a dimension's code list, so IsAreaType is the only hierarchy flag it can give
+type DimensionOptions struct {
+	Dimension    string            `json:"dimension"`
+	IsAreaType   bool              `json:"is_area_type"`
+	ComparedWith string            `json:"compared_with,omitempty"`
+	Items        []DimensionOption `json:"items"`
+	Count        int               `json:"count"`
+	Offset       int               `json:"offset"`
+	Limit        int               `json:"limit"`
+	TotalCount   int               `json:"total_count"`
+}
+
+// DimensionOption is a single option of a dimension, flagged as new or removed when compared with the latest
+// published version
+type DimensionOption struct {
+	Code     string `json:"code"`
+	Label    string `json:"label"`
+	CodeList string `json:"code_list,omitempty"`
+	New      bool   `json:"new"`
+	Removed  bool   `json:"removed"`
+}
+
+// VersionFileReference refers to a file that has already been uploaded, to register it as a distribution of a version
+type VersionFileReference struct {
+	Path  string `json:"path"`
+	Title string `json:"title"`
+}
+
+// VersionRelease is the release calendar entry a version is linked to. ReleaseDateMismatch flags a version whose
+// release date is not the date of the release, with the reason in Warning
+type VersionRelease struct {
+	ID                  string     `json:"id"`
+	Title               string     `json:"title,omitempty"`
+	ReleaseDate         *time.Time `json:"release_date,omitempty"`
+	URI                 string     `json:"uri,omitempty"`
+	Cancelled           bool       `json:"cancelled,omitempty"`
+	ReleaseDateMismatch bool       `json:"release_date_mismatch"`
+	Warning             string     `json:"warning,omitempty"`
+}
+
+// VersionReleaseLink links a version to an entry in the release calendar
+type VersionReleaseLink struct {
+	ReleaseID string `json:"release_id"`
 }
 
 type EditVersionMetaData struct {
-	MetaData   MetaData `json:"meta_data"`
-	Collection string   `json:"collection"`
-	InstanceID string   `json:"instance_id"`
-	Published  bool     `json:"published"`
+	MetaData   MetaData     `json:"meta_data"`
+	Collection string       `json:"collection"`
+	InstanceID string       `json:"instance_id"`
+	Published  bool         `json:"published"`
+	Warnings   []FieldError `json:"warnings,omitempty"`
 }
 
 type MetaData struct {
-	Edition       string                     `json:"edition"`
-	Version       int                        `json:"version"`
-	ReleaseDate   ReleaseDate                `json:"release-date"`
-	Notices       []Notice                   `json:"notices"`
-	Dimensions    []dataset.VersionDimension `json:"dimensions"`
-	UsageNotes    []UsageNote                `json:"usage_notes"`
-	LatestChanges []LatestChanges            `json:"latest_changes"`
+	Edition       string                       `json:"edition"`
+	Version       int                          `json:"version"`
+	ReleaseDate   ReleaseDate                  `json:"release-date"`
+	Notices       []Notice                     `json:"notices"`
+	Dimensions    []datasetApiModels.Dimension `json:"dimensions"`
+	UsageNotes    []UsageNote                  `json:"usage_notes"`
+	LatestChanges []LatestChanges              `json:"latest_changes"`
 
 	Title                string           `json:"title"`
 	Summary              string           `json:"summary"`
 	Keywords             string           `json:"keywords"`
 	NationalStatistic    bool             `json:"national_statistic"`
 	License              string           `json:"license"`
-	ContactName          string           `json:"contact_name"`
-	ContactEmail         string           `json:"contact_email"`
-	ContactTelephone     string           `json:"contact_telephone"`
+	Contacts             []Contact        `json:"contacts"`
 	RelatedDatasets      []RelatedContent `json:"related_datasets"`
 	RelatedPublications  []RelatedContent `json:"related_publications"`
 	RelatedMethodologies []RelatedContent `json:"related_methodologies"`
@@ -91,6 +170,15 @@ type Notice struct {
 	SimpleListDescription string `json:"simple_list_description"`
 }
 
+type Contact struct {
+	ID                    int    `json:"id"`
+	Name                  string `json:"name"`
+	Email                 string `json:"email"`
+	Telephone             string `json:"telephone"`
+	SimpleListHeading     string `json:"simple_list_heading"`
+	SimpleListDescription string `json:"simple_list_description"`
+}
+
 type UsageNote struct {
 	ID                    int    `json:"id"`
 	Title                 string `json:"title"`
@@ -127,3 +215,22 @@ func (d Dataset) GetLabel() string {
 type Topics struct {
 	Title string `json:"title"`
 }
+
+// ErrorResponse is the body of an error response, with the ID of the request and the fields that caused the error
+type ErrorResponse struct {
+	Message   string       `json:"message"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is an error with a field of a request body, or a line of an uploaded file, found by the JSON pointer in
+// Path. Code identifies the kind of error, for errors that callers need to tell apart
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// ErrorCodeSelfReview is the code of the error for a field that would mark as reviewed a dataset or version last
+// edited by the caller
+const ErrorCodeSelfReview = "self_review"
@@ -0,0 +1,141 @@
+package model
+
+// Metadata export formats
+const (
+	ExportFormatCSVW   = "csvw"
+	ExportFormatJSONLD = "jsonld"
+	ExportFormatDCAT   = "dcat"
+)
+
+// CSVWMetadata is a CSV on the Web metadata document describing the csv download of a version
+type CSVWMetadata struct {
+	Context      string         `json:"@context"`
+	URL          string         `json:"url"`
+	Title        string         `json:"dc:title,omitempty"`
+	Description  string         `json:"dc:description,omitempty"`
+	Issued       string         `json:"dc:issued,omitempty"`
+	Modified     string         `json:"dc:modified,omitempty"`
+	License      string         `json:"dc:license,omitempty"`
+	Publisher    *CSVWPublisher `json:"dc:publisher,omitempty"`
+	Keywords     []string       `json:"dcat:keyword,omitempty"`
+	ContactPoint []VCard        `json:"dcat:contactPoint,omitempty"`
+	TableSchema  CSVWSchema     `json:"tableSchema"`
+}
+
+// CSVWPublisher is the publisher of a CSV on the Web table
+type CSVWPublisher struct {
+	Name string `json:"schema:name,omitempty"`
+	URL  string `json:"schema:url,omitempty"`
+}
+
+// CSVWSchema lists the columns of a CSV on the Web table
+type CSVWSchema struct {
+	Columns []CSVWColumn `json:"columns"`
+}
+
+// CSVWColumn describes one column of a CSV on the Web table
+type CSVWColumn struct {
+	Name        string `json:"name"`
+	Titles      string `json:"titles"`
+	Description string `json:"dc:description,omitempty"`
+}
+
+// SchemaDataset is a schema.org Dataset JSON-LD document describing a version
+type SchemaDataset struct {
+	Context          string                `json:"@context"`
+	Type             string                `json:"@type"`
+	ID               string                `json:"@id,omitempty"`
+	Name             string                `json:"name"`
+	Description      string                `json:"description,omitempty"`
+	Keywords         []string              `json:"keywords,omitempty"`
+	License          string                `json:"license,omitempty"`
+	DatePublished    string                `json:"datePublished,omitempty"`
+	DateModified     string                `json:"dateModified,omitempty"`
+	Version          string                `json:"version,omitempty"`
+	TemporalCoverage string                `json:"temporalCoverage,omitempty"`
+	Publisher        *SchemaOrganization   `json:"publisher,omitempty"`
+	ContactPoint     []SchemaContactPoint  `json:"contactPoint,omitempty"`
+	Distribution     []SchemaDataDownload  `json:"distribution,omitempty"`
+	VariableMeasured []SchemaPropertyValue `json:"variableMeasured,omitempty"`
+}
+
+// SchemaOrganization is a schema.org Organization
+type SchemaOrganization struct {
+	Type string `json:"@type"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// SchemaContactPoint is a schema.org ContactPoint
+type SchemaContactPoint struct {
+	Type      string `json:"@type"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Telephone string `json:"telephone,omitempty"`
+}
+
+// SchemaDataDownload is a schema.org DataDownload
+type SchemaDataDownload struct {
+	Type           string `json:"@type"`
+	Name           string `json:"name,omitempty"`
+	EncodingFormat string `json:"encodingFormat,omitempty"`
+	ContentURL     string `json:"contentUrl"`
+	ContentSize    int64  `json:"contentSize,omitempty"`
+}
+
+// SchemaPropertyValue is a schema.org PropertyValue, used for the dimensions of a dataset
+type SchemaPropertyValue struct {
+	Type        string `json:"@type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// DCATDataset is a DCAT JSON-LD document describing a version
+type DCATDataset struct {
+	Context            map[string]string  `json:"@context"`
+	Type               string             `json:"@type"`
+	ID                 string             `json:"@id,omitempty"`
+	Title              string             `json:"dct:title"`
+	Description        string             `json:"dct:description,omitempty"`
+	Keywords           []string           `json:"dcat:keyword,omitempty"`
+	License            string             `json:"dct:license,omitempty"`
+	Issued             string             `json:"dct:issued,omitempty"`
+	Modified           string             `json:"dct:modified,omitempty"`
+	Version            string             `json:"dcat:version,omitempty"`
+	AccrualPeriodicity string             `json:"dct:accrualPeriodicity,omitempty"`
+	Temporal           *DCATPeriodOfTime  `json:"dct:temporal,omitempty"`
+	Publisher          *DCATAgent         `json:"dct:publisher,omitempty"`
+	ContactPoint       []VCard            `json:"dcat:contactPoint,omitempty"`
+	Distribution       []DCATDistribution `json:"dcat:distribution,omitempty"`
+}
+
+// DCATPeriodOfTime is the period a DCAT dataset covers
+type DCATPeriodOfTime struct {
+	Type      string `json:"@type"`
+	StartDate string `json:"dcat:startDate,omitempty"`
+	EndDate   string `json:"dcat:endDate,omitempty"`
+}
+
+// DCATAgent is the publisher of a DCAT dataset
+type DCATAgent struct {
+	Type string `json:"@type"`
+	ID   string `json:"@id,omitempty"`
+	Name string `json:"foaf:name,omitempty"`
+}
+
+// DCATDistribution is a downloadable file of a DCAT dataset
+type DCATDistribution struct {
+	Type        string `json:"@type"`
+	Title       string `json:"dct:title,omitempty"`
+	DownloadURL string `json:"dcat:downloadURL"`
+	MediaType   string `json:"dcat:mediaType,omitempty"`
+	ByteSize    int64  `json:"dcat:byteSize,omitempty"`
+}
+
+// VCard is a contact, as used by DCAT and CSV on the Web
+type VCard struct {
+	Type      string `json:"@type"`
+	Name      string `json:"vcard:fn,omitempty"`
+	Email     string `json:"vcard:hasEmail,omitempty"`
+	Telephone string `json:"vcard:hasTelephone,omitempty"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+)
+
+// MetadataImport is a file of metadata fields to import into a version. Fields left out of the file keep their
+// current values, while a list given in the file replaces the current list
+type MetadataImport struct {
+	Title       *string                           `json:"title,omitempty"`
+	Description *string                           `json:"description,omitempty"`
+	Keywords    []string                          `json:"keywords,omitempty"`
+	Contacts    []datasetApiModels.ContactDetails `json:"contacts,omitempty"`
+	UsageNotes  []datasetApiModels.UsageNote      `json:"usage_notes,omitempty"`
+	Alerts      []datasetApiModels.Alert          `json:"alerts,omitempty"`
+	Dimensions  []ImportedDimension               `json:"dimensions,omitempty"`
+}
+
+// ImportedDimension relabels a dimension of the version, found by its name or id
+type ImportedDimension struct {
+	Name        string `json:"name"`
+	Label       string `json:"label,omitempty"`
+	Description string `json:"description,omitempty"`
+}
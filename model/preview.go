@@ -0,0 +1,44 @@
+package model
+
+// Column types detected in a csv file
+const (
+	ColumnTypeInteger = "integer"
+	ColumnTypeNumber  = "number"
+	ColumnTypeDate    = "date"
+	ColumnTypeString  = "string"
+	ColumnTypeEmpty   = "empty"
+)
+
+// Preview is a page of the rows at the start of a csv file, with the layout detected from them
+type Preview struct {
+	Path      string          `json:"path"`
+	Delimiter string          `json:"delimiter"`
+	HasHeader bool            `json:"has_header"`
+	Columns   []PreviewColumn `json:"columns"`
+	V4        *V4Header       `json:"v4,omitempty"`
+	Rows      [][]string      `json:"rows"`
+	Count     int             `json:"count"`
+	Offset    int             `json:"offset"`
+	Limit     int             `json:"limit"`
+	// HasMore is true when the file has more rows after the page
+	HasMore bool `json:"has_more"`
+}
+
+// PreviewColumn is a column of a csv file, named by its header if the file has one
+type PreviewColumn struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// V4Header is the layout of a file in the V4 format imported by CMD. The first column is the observation, followed
+// by DataMarkings columns of data markings and then a code list and label column for each dimension
+type V4Header struct {
+	DataMarkings int           `json:"data_markings"`
+	Dimensions   []V4Dimension `json:"dimensions"`
+}
+
+// V4Dimension is a dimension of a V4 file, named by the header of its label column
+type V4Dimension struct {
+	CodeList string `json:"code_list"`
+	Name     string `json:"name"`
+}
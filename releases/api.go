@@ -0,0 +1,46 @@
+package releases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dperrors "github.com/ONSdigital/dp-api-clients-go/v2/errors"
+	"github.com/ONSdigital/dp-api-clients-go/v2/releasecalendar"
+)
+
+// APISource reads releases from the release calendar API. Releases are identified by the URI of their page in the
+// release calendar, such as /releases/consumerpriceinflationukjanuary2020
+type APISource struct {
+	client *releasecalendar.Client
+}
+
+// NewAPISource creates an APISource reading releases with client
+func NewAPISource(client *releasecalendar.Client) *APISource {
+	return &APISource{client: client}
+}
+
+// Get returns the release with the URI id, or ErrNotFound if the release calendar has no such release
+func (s *APISource) Get(ctx context.Context, userAccessToken, id string) (Release, error) {
+	r, err := s.client.GetLegacyRelease(ctx, userAccessToken, "", "en", id)
+	if dperrors.StatusCode(err) == http.StatusNotFound {
+		return Release{}, ErrNotFound
+	}
+	if err != nil {
+		return Release{}, err
+	}
+
+	releaseDate, err := time.Parse(time.RFC3339Nano, r.Description.ReleaseDate)
+	if err != nil {
+		return Release{}, fmt.Errorf("release %s has an invalid release date: %w", id, err)
+	}
+
+	return Release{
+		ID:          id,
+		Title:       r.Description.Title,
+		ReleaseDate: releaseDate,
+		URI:         r.URI,
+		Cancelled:   r.Description.Cancelled,
+	}, nil
+}
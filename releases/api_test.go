@@ -0,0 +1,70 @@
+package releases
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/releasecalendar"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitAPISource(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test APISource", t, func() {
+		var requests []*http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			switch r.URL.Query().Get("url") {
+			case "/releases/cpihjanuary2020":
+				w.Write([]byte(`{"uri":"/releases/cpihjanuary2020","description":{"title":"Consumer price inflation, UK: January 2020","release_date":"2020-02-19T07:00:00.000Z","cancelled":false}}`))
+			case "/releases/baddate":
+				w.Write([]byte(`{"uri":"/releases/baddate","description":{"title":"Bad date","release_date":"February 2020"}}`))
+			case "/releases/unavailable":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		source := NewAPISource(releasecalendar.NewAPIClient(server.URL))
+
+		Convey("reads a release from the release calendar API as the caller", func() {
+			r, err := source.Get(ctx, "testuser", "/releases/cpihjanuary2020")
+
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, Release{
+				ID:          "/releases/cpihjanuary2020",
+				Title:       "Consumer price inflation, UK: January 2020",
+				ReleaseDate: time.Date(2020, 2, 19, 7, 0, 0, 0, time.UTC),
+				URI:         "/releases/cpihjanuary2020",
+			})
+			So(requests[0].URL.Path, ShouldEqual, "/releases/legacy")
+			So(requests[0].Header.Get("Authorization"), ShouldEqual, "Bearer testuser")
+		})
+
+		Convey("returns ErrNotFound for a release that is not in the calendar", func() {
+			_, err := source.Get(ctx, "testuser", "/releases/missing")
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("returns an error for a release without a valid release date", func() {
+			_, err := source.Get(ctx, "testuser", "/releases/baddate")
+			So(err, ShouldNotBeNil)
+			So(err, ShouldNotEqual, ErrNotFound)
+		})
+
+		Convey("returns an error when the release calendar API fails", func() {
+			_, err := source.Get(ctx, "testuser", "/releases/unavailable")
+			So(err, ShouldNotBeNil)
+			So(err, ShouldNotEqual, ErrNotFound)
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package releases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+// LocalSource is a stub release calendar for development, reading releases from a json file holding an array of
+// releases. The file is read on every lookup so that releases can be added without a restart. With no file there
+// are no releases
+type LocalSource struct {
+	path string
+}
+
+// NewLocalSource creates a LocalSource reading the releases in the file at path
+func NewLocalSource(path string) *LocalSource {
+	return &LocalSource{path: path}
+}
+
+// Get returns the release with the id, or ErrNotFound if it is not in the file
+func (s *LocalSource) Get(ctx context.Context, userAccessToken, id string) (Release, error) {
+	if s.path == "" {
+		return Release{}, ErrNotFound
+	}
+
+	j, err := os.ReadFile(s.path)
+	if err != nil {
+		return Release{}, err
+	}
+
+	var releases []Release
+	if err = json.Unmarshal(j, &releases); err != nil {
+		return Release{}, err
+	}
+
+	for _, r := range releases {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Release{}, ErrNotFound
+}
+
+// LocalLinkStore keeps the links of versions to releases in a json file on the local disk, for use in development.
+// Links are not shared between instances of the controller
+type LocalLinkStore struct {
+	mu   sync.RWMutex
+	path string
+}
+
+// NewLocalLinkStore creates a LocalLinkStore keeping links in the file at path, which is created when the first
+// link is put
+func NewLocalLinkStore(path string) *LocalLinkStore {
+	return &LocalLinkStore{path: path}
+}
+
+// Get returns the link of a version, or ErrNotLinked if it has none
+func (s *LocalLinkStore) Get(ctx context.Context, datasetID, edition, version string) (Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links, err := s.read()
+	if err != nil {
+		return Link{}, err
+	}
+
+	l, ok := links[linkKey(datasetID, edition, version)]
+	if !ok {
+		return Link{}, ErrNotLinked
+	}
+	return l, nil
+}
+
+// Put replaces the link of a version
+func (s *LocalLinkStore) Put(ctx context.Context, datasetID, edition, version string, l Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	links[linkKey(datasetID, edition, version)] = l
+	return s.write(links)
+}
+
+// Delete removes the link of a version, if there is one
+func (s *LocalLinkStore) Delete(ctx context.Context, datasetID, edition, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	key := linkKey(datasetID, edition, version)
+	if _, ok := links[key]; !ok {
+		return nil
+	}
+	delete(links, key)
+	return s.write(links)
+}
+
+func (s *LocalLinkStore) read() (map[string]Link, error) {
+	links := map[string]Link{}
+
+	j, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return links, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(j, &links)
+	return links, err
+}
+
+func (s *LocalLinkStore) write(links map[string]Link) error {
+	j, err := json.Marshal(links)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	// the links are written to a temporary file and renamed, so a restart part way through never leaves them truncated
+	tmp, err := os.CreateTemp(dir, "links-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(j); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func linkKey(datasetID, edition, version string) string {
+	return path.Join(datasetID, edition, version)
+}
@@ -0,0 +1,82 @@
+package releases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MongoLinkStore keeps the links of versions to releases in a MongoDB collection, so that every instance of the
+// controller sees the same links
+type MongoLinkStore struct {
+	client *mongo.Client
+	links  *mongo.Collection
+}
+
+// linkDocument is a link as it is stored in MongoDB, with the dataset, edition and version as its ID
+type linkDocument struct {
+	ID        string    `bson:"_id"`
+	ReleaseID string    `bson:"release_id"`
+	LinkedAt  time.Time `bson:"linked_at"`
+}
+
+// NewMongoLinkStore connects to the MongoDB deployment at uri and keeps links in collection of database
+func NewMongoLinkStore(ctx context.Context, uri, database, collection string) (*MongoLinkStore, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoLinkStore{
+		client: client,
+		links:  client.Database(database).Collection(collection),
+	}, nil
+}
+
+// Get returns the link of a version, or ErrNotLinked if it has none
+func (s *MongoLinkStore) Get(ctx context.Context, datasetID, edition, version string) (Link, error) {
+	var doc linkDocument
+	err := s.links.FindOne(ctx, bson.M{"_id": linkKey(datasetID, edition, version)}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Link{}, ErrNotLinked
+	}
+	if err != nil {
+		return Link{}, err
+	}
+
+	return Link{ReleaseID: doc.ReleaseID, LinkedAt: doc.LinkedAt.UTC()}, nil
+}
+
+// Put replaces the link of a version
+func (s *MongoLinkStore) Put(ctx context.Context, datasetID, edition, version string, l Link) error {
+	key := linkKey(datasetID, edition, version)
+	doc := linkDocument{ID: key, ReleaseID: l.ReleaseID, LinkedAt: l.LinkedAt}
+
+	_, err := s.links.ReplaceOne(ctx, bson.M{"_id": key}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Delete removes the link of a version, if there is one
+func (s *MongoLinkStore) Delete(ctx context.Context, datasetID, edition, version string) error {
+	_, err := s.links.DeleteOne(ctx, bson.M{"_id": linkKey(datasetID, edition, version)})
+	return err
+}
+
+// Checker reports whether MongoDB can be reached, for the service health check
+func (s *MongoLinkStore) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	if err := s.client.Ping(ctx, readpref.Primary()); err != nil {
+		return state.Update(healthcheck.StatusCritical, err.Error(), 0)
+	}
+	return state.Update(healthcheck.StatusOK, "MongoDB is healthy", 0)
+}
+
+// Close disconnects from MongoDB
+func (s *MongoLinkStore) Close(ctx context.Context) error {
+	return s.client.Disconnect(ctx)
+}
@@ -0,0 +1,170 @@
+package releases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/health"
+	"github.com/ONSdigital/dp-api-clients-go/v2/releasecalendar"
+)
+
+var (
+	// ErrNotFound is returned by a Source when there is no release with an ID
+	ErrNotFound = errors.New("release not found")
+	// ErrNotLinked is returned when a version has not been linked to a release
+	ErrNotLinked = errors.New("version is not linked to a release")
+)
+
+// Release is an entry in the release calendar
+type Release struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	ReleaseDate time.Time `json:"release_date"`
+	URI         string    `json:"uri,omitempty"`
+	Cancelled   bool      `json:"cancelled,omitempty"`
+}
+
+// Link records the release that a version of a dataset is published in
+type Link struct {
+	ReleaseID string    `json:"release_id"`
+	LinkedAt  time.Time `json:"linked_at"`
+}
+
+// Source looks up entries in a release calendar, as seen by the caller with userAccessToken
+type Source interface {
+	Get(ctx context.Context, userAccessToken, id string) (Release, error)
+}
+
+// LinkStore keeps the release that each version is linked to, as the dataset API has nowhere to hold it
+type LinkStore interface {
+	Get(ctx context.Context, datasetID, edition, version string) (Link, error)
+	Put(ctx context.Context, datasetID, edition, version string, l Link) error
+	Delete(ctx context.Context, datasetID, edition, version string) error
+}
+
+// Source types that can be configured
+const (
+	SourceLocal = "local"
+	SourceAPI   = "api"
+)
+
+// NewFromConfig creates the configured type of Source. The api source reads the release calendar API through
+// apiRouter; the local source is only meant for development
+func NewFromConfig(sourceType, localFile string, apiRouter *health.Client) (Source, error) {
+	switch sourceType {
+	case SourceLocal:
+		return NewLocalSource(localFile), nil
+	case SourceAPI:
+		return NewAPISource(releasecalendar.NewWithHealthClient(apiRouter)), nil
+	default:
+		return nil, fmt.Errorf("unknown release source type: %q", sourceType)
+	}
+}
+
+// LinkStore types that can be configured
+const (
+	LinkStoreLocal   = "local"
+	LinkStoreMongoDB = "mongodb"
+)
+
+// MongoDBConfig is where a MongoDB link store keeps links
+type MongoDBConfig struct {
+	URI        string
+	Database   string
+	Collection string
+}
+
+// NewLinkStoreFromConfig creates the configured type of LinkStore. The local store keeps links in localFile on one
+// instance's disk, so it is only meant for development
+func NewLinkStoreFromConfig(ctx context.Context, storeType, localFile string, mongoDB MongoDBConfig) (LinkStore, error) {
+	switch storeType {
+	case LinkStoreLocal:
+		return NewLocalLinkStore(localFile), nil
+	case LinkStoreMongoDB:
+		return NewMongoLinkStore(ctx, mongoDB.URI, mongoDB.Database, mongoDB.Collection)
+	default:
+		return nil, fmt.Errorf("unknown release link store type: %q", storeType)
+	}
+}
+
+// Calendar links versions to the releases in a Source
+type Calendar struct {
+	source Source
+	links  LinkStore
+}
+
+// NewCalendar creates a Calendar reading releases from source and keeping links in links
+func NewCalendar(source Source, links LinkStore) *Calendar {
+	return &Calendar{
+		source: source,
+		links:  links,
+	}
+}
+
+// Link links a version to the release with releaseID, replacing any release it was linked to, and returns the release.
+// ErrNotFound is returned if the source has no such release
+func (c *Calendar) Link(ctx context.Context, userAccessToken, datasetID, edition, version, releaseID string) (Release, error) {
+	r, err := c.source.Get(ctx, userAccessToken, releaseID)
+	if err != nil {
+		return Release{}, err
+	}
+
+	err = c.links.Put(ctx, datasetID, edition, version, Link{ReleaseID: r.ID, LinkedAt: time.Now().UTC()})
+	return r, err
+}
+
+// Unlink removes the link of a version to a release and returns it, or ErrNotLinked if the version has none
+func (c *Calendar) Unlink(ctx context.Context, datasetID, edition, version string) (Link, error) {
+	l, err := c.links.Get(ctx, datasetID, edition, version)
+	if err != nil {
+		return Link{}, err
+	}
+	return l, c.links.Delete(ctx, datasetID, edition, version)
+}
+
+// Linked returns the link of a version and the release it is linked to, or ErrNotLinked if it has none. If the
+// release cannot be read from the source the link is still returned with the error
+func (c *Calendar) Linked(ctx context.Context, userAccessToken, datasetID, edition, version string) (Link, Release, error) {
+	l, err := c.links.Get(ctx, datasetID, edition, version)
+	if err != nil {
+		return Link{}, Release{}, err
+	}
+
+	r, err := c.source.Get(ctx, userAccessToken, l.ReleaseID)
+	return l, r, err
+}
+
+// releaseDateLayouts are the layouts release dates have been entered in, as they were free text
+var releaseDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2 January 2006",
+	"02/01/2006",
+}
+
+// ParseReleaseDate reads a release date of a dataset or version
+func ParseReleaseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range releaseDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a release date", s)
+}
+
+// MatchesReleaseDate reports whether a release date of a version is on the same day as the release. Releases are
+// published in the morning, so the day is compared in UTC
+func (r Release) MatchesReleaseDate(releaseDate string) (bool, error) {
+	t, err := ParseReleaseDate(releaseDate)
+	if err != nil {
+		return false, err
+	}
+
+	y1, m1, d1 := t.UTC().Date()
+	y2, m2, d2 := r.ReleaseDate.UTC().Date()
+	return y1 == y2 && m1 == m2 && d1 == d2, nil
+}
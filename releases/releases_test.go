@@ -0,0 +1,137 @@
+package releases
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/health"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const calendar = `[
+	{"id": "cpih-jan-2020", "title": "Consumer price inflation, UK: January 2020", "release_date": "2020-02-19T07:00:00Z", "uri": "/releases/consumerpriceinflationukjanuary2020"},
+	{"id": "cpih-feb-2020", "title": "Consumer price inflation, UK: February 2020", "release_date": "2020-03-25T07:00:00Z", "cancelled": true}
+]`
+
+func TestUnitCalendar(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test Calendar with the local source and link store", t, func() {
+		dir := t.TempDir()
+		releasesFile := filepath.Join(dir, "releases.json")
+		So(os.WriteFile(releasesFile, []byte(calendar), 0o600), ShouldBeNil)
+
+		source, err := NewFromConfig(SourceLocal, releasesFile, nil)
+		So(err, ShouldBeNil)
+		c := NewCalendar(source, NewLocalLinkStore(filepath.Join(dir, "links", "links.json")))
+
+		Convey("links a version to a release and reads it back after a restart", func() {
+			r, err := c.Link(ctx, "testuser", "cpih01", "time-series", "2", "cpih-jan-2020")
+			So(err, ShouldBeNil)
+			So(r.Title, ShouldEqual, "Consumer price inflation, UK: January 2020")
+
+			restarted := NewCalendar(source, NewLocalLinkStore(filepath.Join(dir, "links", "links.json")))
+			l, linked, err := restarted.Linked(ctx, "testuser", "cpih01", "time-series", "2")
+			So(err, ShouldBeNil)
+			So(l.ReleaseID, ShouldEqual, "cpih-jan-2020")
+			So(l.LinkedAt.IsZero(), ShouldBeFalse)
+			So(linked, ShouldResemble, r)
+
+			_, _, err = restarted.Linked(ctx, "testuser", "cpih01", "time-series", "1")
+			So(err, ShouldEqual, ErrNotLinked)
+
+			Convey("and unlinks it", func() {
+				removed, err := c.Unlink(ctx, "cpih01", "time-series", "2")
+				So(err, ShouldBeNil)
+				So(removed.ReleaseID, ShouldEqual, "cpih-jan-2020")
+				_, _, err = c.Linked(ctx, "testuser", "cpih01", "time-series", "2")
+				So(err, ShouldEqual, ErrNotLinked)
+				_, err = c.Unlink(ctx, "cpih01", "time-series", "2")
+				So(err, ShouldEqual, ErrNotLinked)
+			})
+		})
+
+		Convey("returns ErrNotFound for a release that is not in the calendar", func() {
+			_, err := c.Link(ctx, "testuser", "cpih01", "time-series", "2", "missing")
+			So(err, ShouldEqual, ErrNotFound)
+			_, _, err = c.Linked(ctx, "testuser", "cpih01", "time-series", "2")
+			So(err, ShouldEqual, ErrNotLinked)
+		})
+
+		Convey("returns the link when its release has been removed from the calendar", func() {
+			_, err := c.Link(ctx, "testuser", "cpih01", "time-series", "2", "cpih-feb-2020")
+			So(err, ShouldBeNil)
+			So(os.WriteFile(releasesFile, []byte(`[]`), 0o600), ShouldBeNil)
+
+			l, _, err := c.Linked(ctx, "testuser", "cpih01", "time-series", "2")
+			So(err, ShouldEqual, ErrNotFound)
+			So(l.ReleaseID, ShouldEqual, "cpih-feb-2020")
+		})
+	})
+
+	Convey("test NewFromConfig", t, func() {
+		Convey("creates a local source with no releases when no file is given", func() {
+			source, err := NewFromConfig(SourceLocal, "", nil)
+			So(err, ShouldBeNil)
+			_, err = source.Get(ctx, "", "cpih-jan-2020")
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("creates an api source", func() {
+			source, err := NewFromConfig(SourceAPI, "", health.NewClient("api-router", "http://localhost:23200/v1"))
+			So(err, ShouldBeNil)
+			So(source, ShouldHaveSameTypeAs, &APISource{})
+		})
+
+		Convey("rejects an unknown source type", func() {
+			_, err := NewFromConfig("calendar", "", nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("test NewLinkStoreFromConfig", t, func() {
+		Convey("creates a local link store", func() {
+			links, err := NewLinkStoreFromConfig(ctx, LinkStoreLocal, filepath.Join(t.TempDir(), "links.json"), MongoDBConfig{})
+			So(err, ShouldBeNil)
+			So(links, ShouldHaveSameTypeAs, &LocalLinkStore{})
+		})
+
+		Convey("rejects an unknown link store type", func() {
+			_, err := NewLinkStoreFromConfig(ctx, "redis", "", MongoDBConfig{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitMatchesReleaseDate(t *testing.T) {
+	t.Parallel()
+
+	Convey("test MatchesReleaseDate", t, func() {
+		r := Release{ID: "cpih-jan-2020", ReleaseDate: time.Date(2020, 2, 19, 7, 0, 0, 0, time.UTC)}
+
+		Convey("matches release dates on the day of the release in any of the layouts they are entered in", func() {
+			for _, date := range []string{"2020-02-19T00:00:00.000Z", "2020-02-19", " 19 February 2020", "19/02/2020"} {
+				matches, err := r.MatchesReleaseDate(date)
+				So(err, ShouldBeNil)
+				So(matches, ShouldBeTrue)
+			}
+		})
+
+		Convey("does not match a release date on another day", func() {
+			matches, err := r.MatchesReleaseDate("2020-02-20T00:00:00.000Z")
+			So(err, ShouldBeNil)
+			So(matches, ShouldBeFalse)
+		})
+
+		Convey("returns an error for a release date that cannot be read", func() {
+			_, err := r.MatchesReleaseDate("next month")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package requestid
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderKey is the header the request ID is read from and returned in
+const HeaderKey = request.RequestHeaderKey
+
+// size is the length of generated request IDs, matching the dp-net server
+const size = 16
+
+// Middleware makes sure every request has a request ID. The ID is taken from the context, as set by the dp-net
+// server, or the X-Request-Id header, and is generated if neither is present. It is put in the context, where
+// log.go and the dp-net client pick it up for log lines and upstream calls, and returned in the response
+// header. Plain text and empty error responses are returned as JSON bodies carrying the request ID.
+//
+// When the request is traced log.go writes the trace ID in place of the request ID, so the two are logged together
+// once for each request, letting a request ID given by a caller be found in the logs.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		id := request.GetRequestId(ctx)
+		if id == "" {
+			id = req.Header.Get(HeaderKey)
+		}
+		if id == "" {
+			id = request.NewRequestID(size)
+		}
+		ctx = request.WithRequestId(ctx, id)
+
+		if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+			span.SetAttributes(attribute.String("http.request_id", id))
+			log.Info(ctx, "request id for trace", log.Data{
+				"request_id": id,
+				"trace_id":   span.SpanContext().TraceID().String(),
+				"method":     req.Method,
+				"path":       req.URL.Path,
+			})
+		}
+
+		w.Header().Set(HeaderKey, id)
+		ew := &errorWriter{ResponseWriter: w, requestID: id}
+		next.ServeHTTP(ew, req.WithContext(ctx))
+		ew.flush()
+	})
+}
+
+// errorWriter rewrites plain text or empty error responses, such as those written by http.Error, as JSON error
+// bodies
+type errorWriter struct {
+	http.ResponseWriter
+	requestID   string
+	wroteHeader bool
+	rewrite     bool
+	status      int
+	body        bytes.Buffer
+}
+
+func (w *errorWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	contentType := w.Header().Get("Content-Type")
+	if status >= http.StatusBadRequest && (contentType == "" || strings.HasPrefix(contentType, "text/plain")) {
+		w.rewrite = true
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *errorWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rewrite {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// flush writes a rewritten error response once the handler has finished writing its message
+func (w *errorWriter) flush() {
+	if !w.rewrite {
+		return
+	}
+
+	message := strings.TrimSuffix(w.body.String(), "\n")
+	if message == "" {
+		message = http.StatusText(w.status)
+	}
+
+	b, err := json.Marshal(model.ErrorResponse{
+		Message:   message,
+		RequestID: w.requestID,
+	})
+	if err != nil {
+		w.ResponseWriter.WriteHeader(w.status)
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,147 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/log.go/v2/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a handler wrapped by the request ID middleware", t, func() {
+		var ctxID string
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID = request.GetRequestId(r.Context())
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"cpih01"}`))
+		}))
+
+		Convey("When a request without a request ID is made", func() {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/datasets", http.NoBody))
+
+			Convey("Then an ID is generated, put in the context and returned", func() {
+				So(ctxID, ShouldHaveLength, 16)
+				So(w.Header().Get(HeaderKey), ShouldEqual, ctxID)
+				So(w.Body.String(), ShouldEqual, `{"id":"cpih01"}`)
+			})
+		})
+
+		Convey("When a request with an X-Request-Id header is made", func() {
+			req := httptest.NewRequest("GET", "/datasets", http.NoBody)
+			req.Header.Set(HeaderKey, "florence-id")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Convey("Then the given ID is used", func() {
+				So(ctxID, ShouldEqual, "florence-id")
+				So(w.Header().Get(HeaderKey), ShouldEqual, "florence-id")
+			})
+		})
+
+		Convey("When the request ID is already in the context", func() {
+			req := httptest.NewRequest("GET", "/datasets", http.NoBody)
+			req = req.WithContext(request.WithRequestId(req.Context(), "server-id"))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Convey("Then the context ID is used", func() {
+				So(ctxID, ShouldEqual, "server-id")
+				So(w.Header().Get(HeaderKey), ShouldEqual, "server-id")
+			})
+		})
+	})
+
+	Convey("Given a handler that writes a plain text error", t, func() {
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "error getting version", http.StatusInternalServerError)
+		}))
+
+		Convey("When a request is made", func() {
+			req := httptest.NewRequest("GET", "/datasets", http.NoBody)
+			req.Header.Set(HeaderKey, "florence-id")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Convey("Then a JSON error body with the request ID is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusInternalServerError)
+				So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+				So(w.Body.String(), ShouldEqual, `{"message":"error getting version","request_id":"florence-id"}`)
+			})
+		})
+	})
+
+	Convey("Given a handler that writes an error status without a body", t, func() {
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+
+		Convey("When a request is made", func() {
+			req := httptest.NewRequest("GET", "/datasets", http.NoBody)
+			req.Header.Set(HeaderKey, "florence-id")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Convey("Then the status text is used as the error message", func() {
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+				So(w.Body.String(), ShouldEqual, `{"message":"Not Found","request_id":"florence-id"}`)
+			})
+		})
+	})
+
+	Convey("Given a traced request", t, func() {
+		var logs bytes.Buffer
+		log.SetDestination(&logs, nil)
+		defer log.SetDestination(os.Stdout, os.Stderr)
+
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+		defer span.End()
+		req := httptest.NewRequest("GET", "/datasets", http.NoBody).WithContext(ctx)
+		req.Header.Set(HeaderKey, "florence-id")
+
+		Convey("When it is handled", func() {
+			Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(httptest.NewRecorder(), req)
+
+			Convey("Then the request ID and trace ID are logged together once", func() {
+				So(strings.Count(logs.String(), `"request_id":"florence-id"`), ShouldEqual, 1)
+				So(logs.String(), ShouldContainSubstring, `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+			})
+		})
+	})
+
+	Convey("Given a handler that calls an upstream service", t, func() {
+		var upstreamID string
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamID = r.Header.Get(HeaderKey)
+		}))
+		defer upstream.Close()
+
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, upstream.URL, http.NoBody)
+			resp, err := dphttp.NewClient().Do(r.Context(), req)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}))
+
+		Convey("When a request is made", func() {
+			req := httptest.NewRequest("GET", "/datasets", http.NoBody)
+			req.Header.Set(HeaderKey, "florence-id")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			Convey("Then the request ID is forwarded upstream", func() {
+				So(strings.HasPrefix(upstreamID, "florence-id,"), ShouldBeTrue)
+			})
+		})
+	})
+}
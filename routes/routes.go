@@ -6,22 +6,64 @@ import (
 	datasetApiSdk "github.com/ONSdigital/dp-dataset-api/sdk"
 
 	ds "github.com/ONSdigital/dp-api-clients-go/v2/dataset"
-	zc "github.com/ONSdigital/dp-api-clients-go/v2/zebedee"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/audit"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/download"
 	bc "github.com/ONSdigital/dp-publishing-dataset-controller/clients/topics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/clients/users"
+	zc "github.com/ONSdigital/dp-publishing-dataset-controller/clients/zebedee"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/config"
 	"github.com/ONSdigital/dp-publishing-dataset-controller/dataset"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/docs"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/metrics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/releases"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/requestid"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/upload"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/validation"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 )
 
 // Init initialises routes for the service
-func Init(router *mux.Router, cfg *config.Config, hc healthcheck.HealthCheck, dc *ds.Client, zebedeeClient *zc.Client, topicsClient *bc.Client, datasetApiClient *datasetApiSdk.Client) {
-	router.StrictSlash(true).Path("/health").HandlerFunc(hc.Handler)
-	router.StrictSlash(true).Path("/datasets").HandlerFunc(dataset.GetAll(datasetApiClient, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/create").HandlerFunc(dataset.GetTopics(topicsClient)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions").HandlerFunc(dataset.GetEditions(datasetApiClient)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions").HandlerFunc(dataset.GetVersions(datasetApiClient, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.GetMetadataHandler(datasetApiClient, zebedeeClient)).Methods(http.MethodGet)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.PutMetadata(datasetApiClient, zebedeeClient)).Methods(http.MethodPut)
-	router.StrictSlash(true).Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(dataset.PutEditableMetadata(datasetApiClient, zebedeeClient)).Methods(http.MethodPut)
+func Init(router *mux.Router, cfg *config.Config, hc healthcheck.HealthCheck, dc *ds.Client, zebedeeClient *zc.Client, topicsClient *bc.Client, downloadClient *download.Client, usersClient *users.Client, datasetApiClient *datasetApiSdk.Client, auditor *audit.Auditor, files upload.Backend, chunks *upload.Resumable, calendar *releases.Calendar, m *metrics.Metrics, v *validation.Validator, a *authorisation.Authoriser) {
+	router.Use(otelmux.Middleware(cfg.OTServiceName), requestid.Middleware, m.Middleware, a.Middleware, v.Middleware)
+	m.InstrumentUnmatched(router)
+	// requests that match no route are not passed through the router's middleware, so they are given a request ID here
+	router.NotFoundHandler = requestid.Middleware(router.NotFoundHandler)
+	router.MethodNotAllowedHandler = requestid.Middleware(router.MethodNotAllowedHandler)
+
+	datasetClient := dataset.NewInstrumentedDatasetAPIClient(datasetApiClient, m)
+	collectionClient := dataset.NewInstrumentedZebedeeClient(zebedeeClient, m)
+	babbageClient := dataset.NewInstrumentedBabbageClient(topicsClient, m)
+	downloadServiceClient := dataset.NewInstrumentedDownloadClient(downloadClient, m)
+	identityClient := dataset.NewInstrumentedIdentityClient(usersClient, m)
+
+	router.StrictSlash(true).Name("health").Path("/health").HandlerFunc(hc.Handler)
+	router.StrictSlash(true).Name("metrics").Path("/metrics").Handler(m.Handler()).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("docs").Path("/docs").HandlerFunc(docs.Handler).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("list-datasets").Path("/datasets").HandlerFunc(dataset.GetAll(datasetClient, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("get-topics").Path("/datasets/{datasetID}/create").HandlerFunc(dataset.GetTopics(babbageClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("get-audit-history").Path("/datasets/{datasetID}/audit").HandlerFunc(dataset.GetAuditHistory(auditor, collectionClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("list-editions").Path("/datasets/{datasetID}/editions").HandlerFunc(dataset.GetEditions(datasetClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("list-versions").Path("/datasets/{datasetID}/editions/{editionID}/versions").HandlerFunc(dataset.GetVersions(datasetClient, cfg.DatasetsBatchSize, cfg.DatasetsBatchWorkers)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("get-version").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.GetMetadataHandler(datasetClient, collectionClient, calendar)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("put-version").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}").HandlerFunc(dataset.PutMetadata(datasetClient, collectionClient, identityClient, auditor)).Methods(http.MethodPut)
+	router.StrictSlash(true).Name("get-metadata-export").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(dataset.GetMetadataExport(datasetClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("put-metadata").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata").HandlerFunc(dataset.PutEditableMetadata(datasetClient, collectionClient, identityClient, auditor)).Methods(http.MethodPut)
+	router.StrictSlash(true).Name("import-metadata").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/metadata/import").HandlerFunc(dataset.ImportMetadata(datasetClient, collectionClient, auditor)).Methods(http.MethodPost)
+	router.StrictSlash(true).Name("put-version-release").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release").HandlerFunc(dataset.PutVersionRelease(datasetClient, auditor, calendar)).Methods(http.MethodPut)
+	router.StrictSlash(true).Name("delete-version-release").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/release").HandlerFunc(dataset.DeleteVersionRelease(datasetClient, auditor, calendar)).Methods(http.MethodDelete)
+	router.StrictSlash(true).Name("get-version-summary").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/summary").HandlerFunc(dataset.GetVersionSummary(datasetClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("get-dimension").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}").HandlerFunc(dataset.GetDimension(datasetClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("put-dimension").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}").HandlerFunc(dataset.PutDimension(datasetClient, collectionClient, auditor)).Methods(http.MethodPut)
+	router.StrictSlash(true).Name("get-dimension-options").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/dimensions/{dimension}/options").HandlerFunc(dataset.GetDimensionOptions(datasetClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("list-version-files").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files").HandlerFunc(dataset.GetVersionFiles(datasetClient, files)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("post-version-file").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files").HandlerFunc(dataset.PostVersionFile(datasetClient, collectionClient, auditor, files, cfg.MaxCSVValidationSize)).Methods(http.MethodPost)
+	router.StrictSlash(true).Name("get-preview").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/preview").HandlerFunc(dataset.GetPreview(datasetClient, files, downloadServiceClient)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("get-version-file-chunk").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files/chunks").HandlerFunc(dataset.GetVersionFileChunk(chunks)).Methods(http.MethodGet)
+	router.StrictSlash(true).Name("post-version-file-chunk").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/files/chunks").HandlerFunc(dataset.PostVersionFileChunk(datasetClient, collectionClient, auditor, files, chunks, cfg.MaxCSVValidationSize)).Methods(http.MethodPost)
+	router.StrictSlash(true).Name("post-version-state").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/state").HandlerFunc(dataset.PostVersionState(datasetClient, collectionClient, identityClient, auditor)).Methods(http.MethodPost)
+	router.StrictSlash(true).Name("put-version-collection").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection").HandlerFunc(dataset.PutVersionCollection(datasetClient, collectionClient, auditor)).Methods(http.MethodPut)
+	router.StrictSlash(true).Name("delete-version-collection").Path("/datasets/{datasetID}/editions/{editionID}/versions/{versionID}/collection").HandlerFunc(dataset.DeleteVersionCollection(datasetClient, collectionClient, auditor)).Methods(http.MethodDelete)
 }
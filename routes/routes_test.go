@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/authorisation"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/config"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/docs"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/metrics"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/requestid"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRoutesAreDocumented(t *testing.T) {
+	Convey("Given the OpenAPI spec and the routes registered by Init", t, func() {
+		spec, err := openapi3.NewLoader().LoadFromData(docs.Spec)
+		So(err, ShouldBeNil)
+
+		cfg, err := config.Get()
+		So(err, ShouldBeNil)
+
+		v, err := validation.New(docs.Spec, cfg.MaxRequestBodySize, cfg.MaxUploadSize)
+		So(err, ShouldBeNil)
+
+		router := mux.NewRouter()
+		Init(router, cfg, healthcheck.HealthCheck{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, metrics.New(), v, authorisation.New(false, nil, nil, nil))
+
+		Convey("Then every route and method is described by the spec", func() {
+			var routes int
+			err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+				path, err := route.GetPathTemplate()
+				if err != nil {
+					return err
+				}
+				methods, err := route.GetMethods()
+				if err != nil {
+					// routes without a method restriction are documented with GET
+					methods = []string{"GET"}
+				}
+
+				item := spec.Paths.Find(path)
+				So(item, ShouldNotBeNil)
+				for _, method := range methods {
+					op := item.GetOperation(strings.ToUpper(method))
+					So(op, ShouldNotBeNil)
+					// route names are used to scope service tokens, so are documented as operation IDs
+					So(op.OperationID, ShouldEqual, route.GetName())
+					routes++
+				}
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(routes, ShouldBeGreaterThan, 0)
+		})
+	})
+}
+
+func TestUnmatchedRequestsHaveARequestID(t *testing.T) {
+	Convey("Given the routes registered by Init", t, func() {
+		cfg, err := config.Get()
+		So(err, ShouldBeNil)
+
+		v, err := validation.New(docs.Spec, cfg.MaxRequestBodySize, cfg.MaxUploadSize)
+		So(err, ShouldBeNil)
+
+		router := mux.NewRouter()
+		Init(router, cfg, healthcheck.HealthCheck{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, metrics.New(), v, authorisation.New(false, nil, nil, nil))
+
+		Convey("When a request is made to a path with no route", func() {
+			req := httptest.NewRequest(http.MethodGet, "/not-a-route", http.NoBody)
+			req.Header.Set(requestid.HeaderKey, "florence-id")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Convey("Then the 404 response carries the request ID", func() {
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+				So(w.Header().Get(requestid.HeaderKey), ShouldEqual, "florence-id")
+				So(w.Body.String(), ShouldContainSubstring, `"request_id":"florence-id"`)
+			})
+		})
+
+		Convey("When a request is made with a method the route does not allow", func() {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/docs", http.NoBody))
+
+			Convey("Then the 405 response carries a request ID", func() {
+				So(w.Code, ShouldEqual, http.StatusMethodNotAllowed)
+				So(w.Header().Get(requestid.HeaderKey), ShouldHaveLength, 16)
+			})
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Config holds the settings for exporting traces
+type Config struct {
+	Enabled      bool
+	ServiceName  string
+	Endpoint     string
+	BatchTimeout time.Duration
+}
+
+// ShutdownFunc flushes any spans that have not been exported and stops the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// ErrNoEndpoint is returned when tracing is enabled without an OTLP endpoint to export to
+var ErrNoEndpoint = errors.New("an OTLP endpoint must be configured when tracing is enabled")
+
+// Init sets the global propagator so that trace context is read from incoming requests and passed on to
+// upstream services. If tracing is enabled it also sets a global tracer provider that exports spans over
+// OTLP/HTTP; otherwise spans are not recorded.
+func Init(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if cfg.Endpoint == "" {
+		return nil, ErrNoEndpoint
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(cfg.Endpoint), otlptracehttp.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(cfg.BatchTimeout)),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// NewClient returns a dp-net http client whose requests are traced and carry the caller's trace context
+func NewClient() dphttp.Clienter {
+	return dphttp.NewClientWithTransport(otelhttp.NewTransport(dphttp.DefaultTransport))
+}
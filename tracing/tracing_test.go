@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInit(t *testing.T) {
+	Convey("Given tracing is disabled", t, func() {
+		shutdown, err := Init(context.Background(), Config{Enabled: false})
+
+		Convey("Then the trace context propagator is still set and shutdown does nothing", func() {
+			So(err, ShouldBeNil)
+			So(otel.GetTextMapPropagator().Fields(), ShouldContain, "traceparent")
+			So(shutdown(context.Background()), ShouldBeNil)
+		})
+	})
+
+	Convey("Given tracing is enabled without an endpoint", t, func() {
+		_, err := Init(context.Background(), Config{Enabled: true, ServiceName: "test"})
+
+		Convey("Then an error is returned", func() {
+			So(err, ShouldEqual, ErrNoEndpoint)
+		})
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	Convey("Given a traced client and a span in the request context", t, func() {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		provider := sdktrace.NewTracerProvider()
+		ctx, span := provider.Tracer("test").Start(context.Background(), "handler")
+		defer span.End()
+
+		var traceparent string
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+		}))
+		defer upstream.Close()
+
+		Convey("When a request is made upstream", func() {
+			req, err := http.NewRequest(http.MethodGet, upstream.URL, http.NoBody)
+			So(err, ShouldBeNil)
+			resp, err := NewClient().Do(ctx, req)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+
+			Convey("Then the trace context is passed on", func() {
+				So(traceparent, ShouldContainSubstring, span.SpanContext().TraceID().String())
+			})
+		})
+	})
+}
@@ -0,0 +1,178 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	localFilesDir    = "files"
+	localMetadataDir = "metadata"
+)
+
+// LocalBackend stores uploaded files on the local disk, for use in development. The state of each file is kept
+// alongside it in a json file. There is no virus scanner, so every file is marked as skipped rather than clean
+type LocalBackend struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewLocalBackend creates a LocalBackend storing files under dir, which is created when the first file is uploaded
+func NewLocalBackend(dir string) *LocalBackend {
+	return &LocalBackend{dir: dir}
+}
+
+// Upload writes the content read from r to path, replacing any file already there. The content is written to a
+// temporary file first, so the file at path is only replaced once the upload is complete and readers of it are not
+// held up while it is copied
+func (b *LocalBackend) Upload(ctx context.Context, path, contentType string, r io.Reader) (File, error) {
+	path, err := CleanPath(path)
+	if err != nil {
+		return File{}, err
+	}
+
+	filePath := b.filePath(path)
+	if err = os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return File{}, err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*.tmp")
+	if err != nil {
+		return File{}, err
+	}
+	defer os.Remove(f.Name())
+
+	size, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return File{}, err
+	}
+	if err = f.Close(); err != nil {
+		return File{}, err
+	}
+
+	uploadedAt := time.Now().UTC()
+	file := File{
+		Path:        path,
+		ContentType: contentType,
+		SizeInBytes: size,
+		State:       StateUploaded,
+		ScanState:   ScanSkipped,
+		UploadedAt:  &uploadedAt,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err = os.Rename(f.Name(), filePath); err != nil {
+		return File{}, err
+	}
+	return file, b.writeMetadata(file)
+}
+
+// Get returns the state of the file at path, or ErrNotFound if it has not been uploaded
+func (b *LocalBackend) Get(ctx context.Context, path string) (File, error) {
+	path, err := CleanPath(path)
+	if err != nil {
+		return File{}, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.readMetadata(b.metadataPath(path))
+}
+
+// Open returns a reader of the content of the file at path, or ErrNotFound if it has not been uploaded. The reader
+// must be closed by the caller
+func (b *LocalBackend) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	path, err := CleanPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	f, err := os.Open(b.filePath(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// List returns the files whose paths start with prefix, sorted by path
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]File, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	files := []File{}
+	root := filepath.Join(b.dir, localMetadataDir)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		f, err := b.readMetadata(p)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(f.Path, prefix) {
+			files = append(files, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func (b *LocalBackend) writeMetadata(f File) error {
+	metadataPath := b.metadataPath(f.Path)
+	if err := os.MkdirAll(filepath.Dir(metadataPath), 0o700); err != nil {
+		return err
+	}
+
+	j, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metadataPath, j, 0o600)
+}
+
+func (b *LocalBackend) readMetadata(metadataPath string) (File, error) {
+	j, err := os.ReadFile(metadataPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return File{}, ErrNotFound
+	}
+	if err != nil {
+		return File{}, err
+	}
+
+	var f File
+	err = json.Unmarshal(j, &f)
+	return f, err
+}
+
+func (b *LocalBackend) filePath(path string) string {
+	return filepath.Join(b.dir, localFilesDir, filepath.FromSlash(path))
+}
+
+func (b *LocalBackend) metadataPath(path string) string {
+	return filepath.Join(b.dir, localMetadataDir, filepath.FromSlash(path)+".json")
+}
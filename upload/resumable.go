@@ -0,0 +1,481 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Errors returned by Resumable for chunks that cannot be stored
+var (
+	ErrInvalidChunk = errors.New("invalid chunk")
+	// ErrChunkChecksum is returned when a chunk does not match the checksum sent with it, so the chunk can be sent again
+	ErrChunkChecksum = errors.New("chunk does not match its checksum")
+	// ErrFileChecksum is returned when the joined chunks do not make up the file that was sent. The upload is
+	// discarded and has to be started again
+	ErrFileChecksum = errors.New("file does not match its checksum")
+	// ErrUploadMismatch is returned when a chunk does not describe the same file as the upload it belongs to
+	ErrUploadMismatch = errors.New("chunk does not match the upload with the same identifier")
+)
+
+var identifierPattern = regexp.MustCompile(`^[0-9A-Za-z_.-]+$`)
+
+// Chunk is one chunk of a resumable upload, described by the parameters that Resumable.js sends with each chunk
+type Chunk struct {
+	Identifier  string
+	Filename    string
+	ContentType string
+	// Number is the position of the chunk in the file, counting from 1
+	Number      int
+	CurrentSize int64
+	TotalSize   int64
+	TotalChunks int
+	// Checksum and FileChecksum are optional hex encoded sha256 checksums of the chunk and of the whole file
+	Checksum     string
+	FileChecksum string
+}
+
+// Validate checks that the chunk is a part of a file no larger than maxSize bytes
+func (c Chunk) Validate(maxSize int64) error {
+	switch {
+	case !identifierPattern.MatchString(c.Identifier) || strings.Trim(c.Identifier, ".") == "":
+		return fmt.Errorf("%w: identifier %q must only contain letters, numbers, '_', '-' and '.'", ErrInvalidChunk, c.Identifier)
+	case c.Filename == "":
+		return fmt.Errorf("%w: filename must be set", ErrInvalidChunk)
+	case c.TotalSize < 1:
+		return fmt.Errorf("%w: total size must be at least 1 byte", ErrInvalidChunk)
+	case maxSize > 0 && c.TotalSize > maxSize:
+		return fmt.Errorf("%w: file must not be larger than %d bytes", ErrInvalidChunk, maxSize)
+	case c.TotalChunks < 1 || c.Number < 1 || c.Number > c.TotalChunks:
+		return fmt.Errorf("%w: chunk number %d is not between 1 and %d", ErrInvalidChunk, c.Number, c.TotalChunks)
+	case c.CurrentSize < 1 || c.CurrentSize > c.TotalSize:
+		return fmt.Errorf("%w: chunk size %d is not between 1 and %d", ErrInvalidChunk, c.CurrentSize, c.TotalSize)
+	}
+	return nil
+}
+
+// ResumableState is the progress of a resumable upload. It is kept in a StateStore so that an upload can be resumed
+// after the controller restarts
+type ResumableState struct {
+	// Key is the identifier of the upload under the directory of the file it creates, as Resumable.js identifiers
+	// are only unique to a browser session and the same file may be uploaded to more than one version
+	Key          string `json:"key"`
+	Identifier   string `json:"identifier"`
+	Path         string `json:"path"`
+	ContentType  string `json:"content_type,omitempty"`
+	TotalSize    int64  `json:"total_size"`
+	TotalChunks  int    `json:"total_chunks"`
+	FileChecksum string `json:"file_checksum,omitempty"`
+	// Chunks holds the sha256 checksum of each chunk that has been received, by chunk number
+	Chunks map[int]string `json:"chunks"`
+	// UpdatedAt is when the last chunk was received, so that an upload that has been abandoned can be swept away
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Complete reports whether every chunk of the upload has been received
+func (s ResumableState) Complete() bool {
+	return len(s.Chunks) == s.TotalChunks
+}
+
+// File returns the file that the upload is creating, which stays in the created state until it is complete
+func (s ResumableState) File() File {
+	return File{
+		Path:        s.Path,
+		ContentType: s.ContentType,
+		SizeInBytes: s.TotalSize,
+		State:       StateCreated,
+		ScanState:   ScanPending,
+	}
+}
+
+func (s ResumableState) matches(path string, c Chunk) bool {
+	return s.Path == path && s.TotalSize == c.TotalSize && s.TotalChunks == c.TotalChunks
+}
+
+// CheckFunc checks a complete file, read from r, before it is uploaded to the backend. Returning an error stops the
+// upload and discards its chunks, and the error is returned from WriteChunk
+type CheckFunc func(ctx context.Context, r io.Reader) error
+
+// StateStore keeps the progress of resumable uploads by their key
+type StateStore interface {
+	Get(ctx context.Context, key string) (ResumableState, error)
+	List(ctx context.Context) ([]ResumableState, error)
+	Put(ctx context.Context, s ResumableState) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Resumable receives files in chunks, using the protocol of Resumable.js, so that an upload that fails part way can
+// carry on from the chunks that were received. Chunks are held on the local disk until the last one arrives, when
+// they are joined, checked against their checksums and the file is uploaded to a Backend.
+type Resumable struct {
+	backend  Backend
+	store    StateStore
+	chunkDir string
+	maxSize  int64
+
+	mu         sync.Mutex
+	assembling map[string]bool
+}
+
+// NewResumable creates a Resumable that holds chunks under chunkDir and accepts files of up to maxSize bytes
+func NewResumable(backend Backend, store StateStore, chunkDir string, maxSize int64) *Resumable {
+	return &Resumable{
+		backend:    backend,
+		store:      store,
+		chunkDir:   chunkDir,
+		maxSize:    maxSize,
+		assembling: map[string]bool{},
+	}
+}
+
+// HasChunk reports whether a chunk of the file being uploaded to path has already been received, so that it does
+// not need to be sent again
+func (r *Resumable) HasChunk(ctx context.Context, path string, c Chunk) (bool, error) {
+	if err := c.Validate(r.maxSize); err != nil {
+		return false, err
+	}
+	path, err := CleanPath(path)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrInvalidChunk, err.Error())
+	}
+	key := uploadKey(path, c.Identifier)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, err := r.store.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, ok := s.Chunks[c.Number]; !ok || !s.matches(path, c) {
+		return false, nil
+	}
+
+	// a chunk recorded in the state but lost from the disk has to be sent again
+	if _, err = os.Stat(r.chunkPath(key, c.Number)); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// WriteChunk stores a chunk of the file being uploaded to path, reading its content from body. When the last chunk
+// is received the joined file is passed to check, if it is set, and then uploaded to the backend and returned in the
+// uploaded state. Until then the returned file is in the created state.
+func (r *Resumable) WriteChunk(ctx context.Context, path string, c Chunk, body io.Reader, check CheckFunc) (File, error) {
+	if err := c.Validate(r.maxSize); err != nil {
+		return File{}, err
+	}
+	path, err := CleanPath(path)
+	if err != nil {
+		return File{}, fmt.Errorf("%w: %s", ErrInvalidChunk, err.Error())
+	}
+
+	key := uploadKey(path, c.Identifier)
+
+	tmp, checksum, err := r.writeTempChunk(key, c, body)
+	if err != nil {
+		return File{}, err
+	}
+	defer os.Remove(tmp)
+
+	r.mu.Lock()
+	s, err := r.recordChunk(ctx, key, path, c, tmp, checksum)
+	assemble := err == nil && s.Complete() && !r.assembling[key]
+	if assemble {
+		r.assembling[key] = true
+	}
+	r.mu.Unlock()
+	if err != nil || !assemble {
+		return s.File(), err
+	}
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.assembling, key)
+		r.mu.Unlock()
+	}()
+	return r.assemble(ctx, s, check)
+}
+
+// writeTempChunk writes the content of a chunk to a temporary file, checking its size and checksum
+func (r *Resumable) writeTempChunk(key string, c Chunk, body io.Reader) (string, string, error) {
+	dir := r.uploadDir(key)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", "", err
+	}
+
+	f, err := os.CreateTemp(dir, "chunk-*.tmp")
+	if err != nil {
+		return "", "", err
+	}
+
+	h := sha256.New()
+	// reading one byte past the size of the chunk shows whether more was sent than described
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(body, c.CurrentSize+1))
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil && n != c.CurrentSize {
+		err = fmt.Errorf("%w: chunk %d has %d bytes but its size is %d", ErrInvalidChunk, c.Number, n, c.CurrentSize)
+	}
+
+	checksum := hex.EncodeToString(h.Sum(nil))
+	if err == nil && c.Checksum != "" && !strings.EqualFold(c.Checksum, checksum) {
+		err = fmt.Errorf("%w: chunk %d", ErrChunkChecksum, c.Number)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", "", err
+	}
+
+	return f.Name(), checksum, nil
+}
+
+// recordChunk moves a chunk into place and records it in the state of its upload, which is started by the first
+// chunk received. It must be called holding r.mu.
+func (r *Resumable) recordChunk(ctx context.Context, key, path string, c Chunk, tmp, checksum string) (ResumableState, error) {
+	s, err := r.store.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		s, err = ResumableState{
+			Key:         key,
+			Identifier:  c.Identifier,
+			Path:        path,
+			ContentType: c.ContentType,
+			TotalSize:   c.TotalSize,
+			TotalChunks: c.TotalChunks,
+			Chunks:      map[int]string{},
+		}, nil
+	}
+	if err != nil {
+		return ResumableState{}, err
+	}
+	if !s.matches(path, c) {
+		return ResumableState{}, fmt.Errorf("%w: %s", ErrUploadMismatch, c.Identifier)
+	}
+	if c.FileChecksum != "" {
+		s.FileChecksum = strings.ToLower(c.FileChecksum)
+	}
+
+	if err = os.Rename(tmp, r.chunkPath(key, c.Number)); err != nil {
+		return ResumableState{}, err
+	}
+
+	s.Chunks[c.Number] = checksum
+	s.UpdatedAt = time.Now().UTC()
+	return s, r.store.Put(ctx, s)
+}
+
+// assemble joins the chunks of a complete upload, checks the file and uploads it to the backend. If the chunks do
+// not make up the file that was sent, or the file fails the check, the upload is discarded, as sending the last
+// chunk again would not fix it.
+func (r *Resumable) assemble(ctx context.Context, s ResumableState, check CheckFunc) (File, error) {
+	f, err := os.CreateTemp(r.uploadDir(s.Key), "file-*.tmp")
+	if err != nil {
+		return File{}, err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	if err = r.join(f, s); err != nil {
+		if errors.Is(err, ErrFileChecksum) {
+			r.discard(ctx, s.Key)
+		}
+		return File{}, err
+	}
+
+	if check != nil {
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return File{}, err
+		}
+		if err = check(ctx, f); err != nil {
+			r.discard(ctx, s.Key)
+			return File{}, err
+		}
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return File{}, err
+	}
+	file, err := r.backend.Upload(ctx, s.Path, s.ContentType, f)
+	if err != nil {
+		return File{}, err
+	}
+
+	r.discard(ctx, s.Key)
+	return file, nil
+}
+
+// join writes the chunks of an upload to w in order, checking each one and the whole file against their checksums
+func (r *Resumable) join(w io.Writer, s ResumableState) error {
+	fileHash := sha256.New()
+	var size int64
+	for n := 1; n <= s.TotalChunks; n++ {
+		chunk, err := os.Open(r.chunkPath(s.Key, n))
+		if err != nil {
+			return err
+		}
+
+		chunkHash := sha256.New()
+		written, err := io.Copy(io.MultiWriter(w, fileHash, chunkHash), chunk)
+		chunk.Close()
+		if err != nil {
+			return err
+		}
+		size += written
+
+		if hex.EncodeToString(chunkHash.Sum(nil)) != s.Chunks[n] {
+			return fmt.Errorf("%w: chunk %d has changed since it was received", ErrFileChecksum, n)
+		}
+	}
+
+	if size != s.TotalSize {
+		return fmt.Errorf("%w: the chunks have %d bytes but the file size is %d", ErrFileChecksum, size, s.TotalSize)
+	}
+	if s.FileChecksum != "" && hex.EncodeToString(fileHash.Sum(nil)) != s.FileChecksum {
+		return ErrFileChecksum
+	}
+	return nil
+}
+
+// discard removes the chunks and state of an upload. Errors are ignored, as they only leave files to be tidied up
+func (r *Resumable) discard(ctx context.Context, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	os.RemoveAll(r.uploadDir(key))
+	r.store.Delete(ctx, key)
+}
+
+// Sweep removes the chunks and state of every upload that has not received a chunk for maxAge, and any chunks left
+// on the disk without a state that are older than maxAge. It returns the number of uploads removed.
+func (r *Resumable) Sweep(ctx context.Context, maxAge time.Duration) (int, error) {
+	cutoff := time.Now().Add(-maxAge)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states, err := r.store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	live := map[string]bool{}
+	for _, s := range states {
+		if r.assembling[s.Key] || !s.UpdatedAt.Before(cutoff) {
+			live[s.Key] = true
+			continue
+		}
+		if err = os.RemoveAll(r.uploadDir(s.Key)); err != nil {
+			return removed, err
+		}
+		if err = r.store.Delete(ctx, s.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, r.sweepOrphans(live, cutoff)
+}
+
+// StartSweeping sweeps away abandoned uploads in the background, until ctx is done. Uploads are swept every
+// maxAge, or every hour if that is sooner.
+func (r *Resumable) StartSweeping(ctx context.Context, maxAge time.Duration) {
+	interval := min(maxAge, time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				removed, err := r.Sweep(ctx, maxAge)
+				if err != nil {
+					log.Error(ctx, "error sweeping resumable uploads", err)
+					continue
+				}
+				if removed > 0 {
+					log.Info(ctx, "swept abandoned resumable uploads", log.Data{"removed": removed})
+				}
+			}
+		}
+	}()
+}
+
+// sweepOrphans removes the files under the chunk directory that do not belong to a live upload and were last
+// modified before cutoff, such as the chunks of an upload whose state was lost, and then any directories left empty.
+// It must be called holding r.mu.
+func (r *Resumable) sweepOrphans(live map[string]bool, cutoff time.Time) error {
+	var dirs []string
+	err := filepath.WalkDir(r.chunkDir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == r.chunkDir {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != r.chunkDir {
+				dirs = append(dirs, p)
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(r.chunkDir, filepath.Dir(p))
+		if err != nil || live[filepath.ToSlash(rel)] {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return err
+		}
+		return os.Remove(p)
+	})
+	if err != nil {
+		return err
+	}
+
+	// directories are removed deepest first, and only succeed when they are empty
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if info, err := os.Stat(dirs[i]); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(dirs[i])
+		}
+	}
+	return nil
+}
+
+// uploadKey returns the key of the upload with identifier creating the file at the cleaned path
+func uploadKey(cleanedPath, identifier string) string {
+	return path.Join(path.Dir(cleanedPath), identifier)
+}
+
+func (r *Resumable) uploadDir(key string) string {
+	return filepath.Join(r.chunkDir, filepath.FromSlash(key))
+}
+
+func (r *Resumable) chunkPath(key string, number int) string {
+	return filepath.Join(r.uploadDir(key), strconv.Itoa(number))
+}
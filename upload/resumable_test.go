@@ -0,0 +1,265 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func checksum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestUnitResumable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	const path = "cpih01/time-series/2/cpih.csv"
+	content := []string{"a,b\n", "1,2\n", "3"}
+
+	chunk := func(n int) Chunk {
+		return Chunk{
+			Identifier:   "9-cpihcsv",
+			Filename:     "cpih.csv",
+			ContentType:  "text/csv",
+			Number:       n,
+			CurrentSize:  int64(len(content[n-1])),
+			TotalSize:    9,
+			TotalChunks:  3,
+			Checksum:     checksum(content[n-1]),
+			FileChecksum: checksum(strings.Join(content, "")),
+		}
+	}
+
+	Convey("test Resumable", t, func() {
+		dir := t.TempDir()
+		backend := NewLocalBackend(filepath.Join(dir, "files"))
+		store := NewLocalStateStore(filepath.Join(dir, "state"))
+		r := NewResumable(backend, store, filepath.Join(dir, "chunks"), 1024)
+
+		Convey("joins the chunks in order once they have all been received, whatever order they arrive in", func() {
+			for _, n := range []int{3, 1} {
+				f, err := r.WriteChunk(ctx, path, chunk(n), strings.NewReader(content[n-1]), nil)
+				So(err, ShouldBeNil)
+				So(f.State, ShouldEqual, StateCreated)
+				So(f.Path, ShouldEqual, path)
+			}
+
+			has, err := r.HasChunk(ctx, path, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeTrue)
+			has, err = r.HasChunk(ctx, path, chunk(2))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeFalse)
+
+			f, err := r.WriteChunk(ctx, path, chunk(2), strings.NewReader(content[1]), nil)
+			So(err, ShouldBeNil)
+			So(f.State, ShouldEqual, StateUploaded)
+			So(f.SizeInBytes, ShouldEqual, 9)
+
+			b, err := os.ReadFile(filepath.Join(dir, "files", "files", "cpih01", "time-series", "2", "cpih.csv"))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "a,b\n1,2\n3")
+
+			Convey("and discards the chunks and state", func() {
+				_, err = store.Get(ctx, "cpih01/time-series/2/9-cpihcsv")
+				So(err, ShouldEqual, ErrNotFound)
+				_, err = os.Stat(filepath.Join(dir, "chunks", "cpih01", "time-series", "2", "9-cpihcsv"))
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("resumes an upload from the state store after a restart", func() {
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader(content[0]), nil)
+			So(err, ShouldBeNil)
+
+			restarted := NewResumable(backend, NewLocalStateStore(filepath.Join(dir, "state")), filepath.Join(dir, "chunks"), 1024)
+			has, err := restarted.HasChunk(ctx, path, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeTrue)
+
+			_, err = restarted.WriteChunk(ctx, path, chunk(2), strings.NewReader(content[1]), nil)
+			So(err, ShouldBeNil)
+			f, err := restarted.WriteChunk(ctx, path, chunk(3), strings.NewReader(content[2]), nil)
+			So(err, ShouldBeNil)
+			So(f.State, ShouldEqual, StateUploaded)
+		})
+
+		Convey("checks the joined file before uploading it", func() {
+			existing, err := backend.Upload(ctx, path, "text/csv", strings.NewReader("x,y\n"))
+			So(err, ShouldBeNil)
+
+			var checked string
+			errCheck := errors.New("test check error")
+			check := func(ctx context.Context, r io.Reader) error {
+				b, err := io.ReadAll(r)
+				checked = string(b)
+				if err != nil {
+					return err
+				}
+				return errCheck
+			}
+
+			for n := 1; n <= 2; n++ {
+				_, err = r.WriteChunk(ctx, path, chunk(n), strings.NewReader(content[n-1]), check)
+				So(err, ShouldBeNil)
+			}
+			So(checked, ShouldBeEmpty)
+
+			_, err = r.WriteChunk(ctx, path, chunk(3), strings.NewReader(content[2]), check)
+			So(err, ShouldEqual, errCheck)
+			So(checked, ShouldEqual, strings.Join(content, ""))
+
+			Convey("leaving the file that was already at the path and discarding the upload", func() {
+				f, err := backend.Get(ctx, path)
+				So(err, ShouldBeNil)
+				So(f.SizeInBytes, ShouldEqual, existing.SizeInBytes)
+
+				_, err = store.Get(ctx, "cpih01/time-series/2/9-cpihcsv")
+				So(err, ShouldEqual, ErrNotFound)
+			})
+		})
+
+		Convey("rejects a chunk that does not match its checksum so that it can be sent again", func() {
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader("a,c\n"), nil)
+			So(errors.Is(err, ErrChunkChecksum), ShouldBeTrue)
+
+			has, err := r.HasChunk(ctx, path, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeFalse)
+		})
+
+		Convey("rejects a chunk with a different size to the one described", func() {
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader("a,b\n1"), nil)
+			So(errors.Is(err, ErrInvalidChunk), ShouldBeTrue)
+		})
+
+		Convey("discards an upload whose chunks do not make up the file", func() {
+			for n := 1; n <= 2; n++ {
+				_, err := r.WriteChunk(ctx, path, chunk(n), strings.NewReader(content[n-1]), nil)
+				So(err, ShouldBeNil)
+			}
+
+			c := chunk(3)
+			c.FileChecksum = checksum("something else")
+			_, err := r.WriteChunk(ctx, path, c, strings.NewReader(content[2]), nil)
+			So(errors.Is(err, ErrFileChecksum), ShouldBeTrue)
+
+			_, err = store.Get(ctx, "cpih01/time-series/2/9-cpihcsv")
+			So(err, ShouldEqual, ErrNotFound)
+			_, err = backend.Get(ctx, path)
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("keeps uploads to different versions with the same identifier apart", func() {
+			const otherPath = "cpih01/time-series/3/cpih.csv"
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader(content[0]), nil)
+			So(err, ShouldBeNil)
+
+			has, err := r.HasChunk(ctx, otherPath, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeFalse)
+
+			for n := 1; n <= 3; n++ {
+				_, err = r.WriteChunk(ctx, otherPath, chunk(n), strings.NewReader(content[n-1]), nil)
+				So(err, ShouldBeNil)
+			}
+			_, err = backend.Get(ctx, otherPath)
+			So(err, ShouldBeNil)
+
+			has, err = r.HasChunk(ctx, path, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeTrue)
+			_, err = backend.Get(ctx, path)
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("does not report a chunk of the same upload sent for another file in the version", func() {
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader(content[0]), nil)
+			So(err, ShouldBeNil)
+
+			has, err := r.HasChunk(ctx, "cpih01/time-series/2/other.csv", chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeFalse)
+		})
+
+		Convey("rejects a chunk for a different file with the same identifier", func() {
+			_, err := r.WriteChunk(ctx, path, chunk(1), strings.NewReader(content[0]), nil)
+			So(err, ShouldBeNil)
+
+			c := chunk(2)
+			c.TotalSize = 10
+			_, err = r.WriteChunk(ctx, path, c, strings.NewReader(content[1]), nil)
+			So(errors.Is(err, ErrUploadMismatch), ShouldBeTrue)
+		})
+
+		Convey("sweeps away uploads that have not received a chunk for longer than the maximum age", func() {
+			const otherPath = "cpih01/time-series/3/cpih.csv"
+			for _, p := range []string{path, otherPath} {
+				_, err := r.WriteChunk(ctx, p, chunk(1), strings.NewReader(content[0]), nil)
+				So(err, ShouldBeNil)
+			}
+
+			stale, err := store.Get(ctx, "cpih01/time-series/2/9-cpihcsv")
+			So(err, ShouldBeNil)
+			stale.UpdatedAt = time.Now().Add(-2 * time.Hour)
+			So(store.Put(ctx, stale), ShouldBeNil)
+
+			// chunks left behind by an upload whose state has been lost
+			orphan := filepath.Join(dir, "chunks", "cpih01", "time-series", "4", "lost", "1")
+			So(os.MkdirAll(filepath.Dir(orphan), 0o700), ShouldBeNil)
+			So(os.WriteFile(orphan, []byte(content[0]), 0o600), ShouldBeNil)
+			old := time.Now().Add(-2 * time.Hour)
+			So(os.Chtimes(orphan, old, old), ShouldBeNil)
+
+			removed, err := r.Sweep(ctx, time.Hour)
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 1)
+
+			_, err = store.Get(ctx, "cpih01/time-series/2/9-cpihcsv")
+			So(err, ShouldEqual, ErrNotFound)
+			has, err := r.HasChunk(ctx, path, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeFalse)
+
+			has, err = r.HasChunk(ctx, otherPath, chunk(1))
+			So(err, ShouldBeNil)
+			So(has, ShouldBeTrue)
+
+			_, err = os.Stat(orphan)
+			So(errors.Is(err, os.ErrNotExist), ShouldBeTrue)
+		})
+
+		Convey("sweeps nothing when no upload has been started", func() {
+			removed, err := r.Sweep(ctx, time.Hour)
+			So(err, ShouldBeNil)
+			So(removed, ShouldEqual, 0)
+		})
+
+		Convey("rejects invalid chunks", func() {
+			tooLarge := chunk(1)
+			tooLarge.TotalSize = 2048
+			badIdentifier := chunk(1)
+			badIdentifier.Identifier = "../state"
+			badNumber := chunk(1)
+			badNumber.Number = 4
+
+			for _, c := range []Chunk{tooLarge, badIdentifier, badNumber} {
+				_, err := r.HasChunk(ctx, path, c)
+				So(errors.Is(err, ErrInvalidChunk), ShouldBeTrue)
+				_, err = r.WriteChunk(ctx, path, c, strings.NewReader(content[0]), nil)
+				So(errors.Is(err, ErrInvalidChunk), ShouldBeTrue)
+			}
+		})
+	})
+}
@@ -0,0 +1,118 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LocalStateStore keeps the state of each resumable upload in a json file on the local disk, so that uploads can
+// be resumed after a restart
+type LocalStateStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewLocalStateStore creates a LocalStateStore keeping state under dir, which is created when the first state is put
+func NewLocalStateStore(dir string) *LocalStateStore {
+	return &LocalStateStore{dir: dir}
+}
+
+// Get returns the state of the upload with the key, or ErrNotFound if there is no upload in progress
+func (s *LocalStateStore) Get(ctx context.Context, key string) (ResumableState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ResumableState{}, ErrNotFound
+	}
+	if err != nil {
+		return ResumableState{}, err
+	}
+
+	var state ResumableState
+	err = json.Unmarshal(j, &state)
+	return state, err
+}
+
+// Put replaces the state of an upload
+func (s *LocalStateStore) Put(ctx context.Context, state ResumableState) error {
+	j, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.path(state.Key)
+	if err = os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	// the state is written to a temporary file and renamed, so a restart part way through never leaves it truncated
+	tmp, err := os.CreateTemp(filepath.Dir(p), "state-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(j); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Delete removes the state of an upload, if there is one
+func (s *LocalStateStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the state of every upload in progress
+func (s *LocalStateStore) List(ctx context.Context) ([]ResumableState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var states []ResumableState
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == s.dir {
+			return fs.SkipAll
+		}
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".json") {
+			return err
+		}
+
+		j, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var state ResumableState
+		if err = json.Unmarshal(j, &state); err != nil {
+			return err
+		}
+		states = append(states, state)
+		return nil
+	})
+	return states, err
+}
+
+func (s *LocalStateStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key)+".json")
+}
@@ -0,0 +1,93 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// States of the upload of a file
+const (
+	StateCreated  = "created"
+	StateUploaded = "uploaded"
+)
+
+// States of the virus scan of an uploaded file
+const (
+	ScanPending  = "pending"
+	ScanClean    = "clean"
+	ScanInfected = "infected"
+	ScanSkipped  = "skipped"
+)
+
+// ErrNotFound is returned by a Backend when there is no file at a path
+var ErrNotFound = errors.New("file not found")
+
+// File is a file held by a Backend, with the state of its upload and virus scan
+type File struct {
+	Path        string     `json:"path"`
+	ContentType string     `json:"content_type,omitempty"`
+	SizeInBytes int64      `json:"size_in_bytes"`
+	State       string     `json:"state"`
+	ScanState   string     `json:"scan_state"`
+	UploadedAt  *time.Time `json:"uploaded_at,omitempty"`
+}
+
+// Ready reports whether the file has been uploaded in full and has not been found to be infected, so that it can be
+// registered as a distribution of a version
+func (f File) Ready() bool {
+	return f.State == StateUploaded && f.ScanState != ScanInfected
+}
+
+// Backend stores the files uploaded for static datasets
+type Backend interface {
+	Upload(ctx context.Context, path, contentType string, r io.Reader) (File, error)
+	Get(ctx context.Context, path string) (File, error)
+	List(ctx context.Context, prefix string) ([]File, error)
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+}
+
+// Backend types that can be configured
+const (
+	BackendLocal = "local"
+)
+
+// NewFromConfig creates the configured type of Backend. The local backend does not scan files for viruses, so it is
+// only created for development, when allowLocal is set
+func NewFromConfig(backendType, localDir string, allowLocal bool) (Backend, error) {
+	switch backendType {
+	case BackendLocal:
+		if !allowLocal {
+			return nil, errors.New("the local backend does not scan files for viruses and must be allowed for development")
+		}
+		if localDir == "" {
+			return nil, errors.New("upload directory must be set for the local backend")
+		}
+		return NewLocalBackend(localDir), nil
+	default:
+		return nil, fmt.Errorf("unknown upload backend type: %q", backendType)
+	}
+}
+
+// VersionPath returns the path that a file uploaded for a version is stored at
+func VersionPath(datasetID, edition, version, filename string) string {
+	return path.Join(VersionPrefix(datasetID, edition, version), path.Base(filename))
+}
+
+// VersionPrefix returns the path prefix of the files uploaded for a version
+func VersionPrefix(datasetID, edition, version string) string {
+	return path.Join(datasetID, edition, version) + "/"
+}
+
+// CleanPath checks that a path stays inside the backend, returning it in a canonical form
+func CleanPath(p string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(p, "/"))
+	if p == "" || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid file path %q", p)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,160 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLocalBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	Convey("test LocalBackend", t, func() {
+		dir := t.TempDir()
+		b := NewLocalBackend(dir)
+
+		Convey("uploads a file and reads back its state", func() {
+			f, err := b.Upload(ctx, "cpih01/time-series/2/cpih.csv", "text/csv", strings.NewReader("a,b\n1,2\n"))
+			So(err, ShouldBeNil)
+			So(f.Path, ShouldEqual, "cpih01/time-series/2/cpih.csv")
+			So(f.SizeInBytes, ShouldEqual, 8)
+			So(f.State, ShouldEqual, StateUploaded)
+			So(f.ScanState, ShouldEqual, ScanSkipped)
+			So(f.Ready(), ShouldBeTrue)
+
+			content, err := os.ReadFile(filepath.Join(dir, "files", "cpih01", "time-series", "2", "cpih.csv"))
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual, "a,b\n1,2\n")
+
+			got, err := b.Get(ctx, "/cpih01/time-series/2/cpih.csv")
+			So(err, ShouldBeNil)
+			So(got.Path, ShouldEqual, f.Path)
+			So(got.ContentType, ShouldEqual, "text/csv")
+
+			r, err := b.Open(ctx, f.Path)
+			So(err, ShouldBeNil)
+			defer r.Close()
+			opened, err := io.ReadAll(r)
+			So(err, ShouldBeNil)
+			So(string(opened), ShouldEqual, "a,b\n1,2\n")
+		})
+
+		Convey("keeps the file already at a path when an upload to it fails", func() {
+			_, err := b.Upload(ctx, "cpih01/time-series/2/cpih.csv", "text/csv", strings.NewReader("a,b\n"))
+			So(err, ShouldBeNil)
+
+			_, err = b.Upload(ctx, "cpih01/time-series/2/cpih.csv", "text/csv", io.MultiReader(strings.NewReader("c,d"), iotest.ErrReader(errors.New("connection reset"))))
+			So(err, ShouldNotBeNil)
+
+			content, err := os.ReadFile(filepath.Join(dir, "files", "cpih01", "time-series", "2", "cpih.csv"))
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual, "a,b\n")
+			entries, err := os.ReadDir(filepath.Join(dir, "files", "cpih01", "time-series", "2"))
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 1)
+		})
+
+		Convey("reads files while an upload is in progress", func() {
+			_, err := b.Upload(ctx, "cpih01/time-series/2/cpih.csv", "text/csv", strings.NewReader("a,b\n"))
+			So(err, ShouldBeNil)
+
+			pr, pw := io.Pipe()
+			done := make(chan error)
+			go func() {
+				_, err := b.Upload(ctx, "cpih01/time-series/2/cpih.csv", "text/csv", pr)
+				done <- err
+			}()
+			_, err = pw.Write([]byte("c,d\n"))
+			So(err, ShouldBeNil)
+
+			got, err := b.Get(ctx, "cpih01/time-series/2/cpih.csv")
+			So(err, ShouldBeNil)
+			So(got.SizeInBytes, ShouldEqual, 4)
+			r, err := b.Open(ctx, "cpih01/time-series/2/cpih.csv")
+			So(err, ShouldBeNil)
+			content, err := io.ReadAll(r)
+			r.Close()
+			So(err, ShouldBeNil)
+			So(string(content), ShouldEqual, "a,b\n")
+
+			So(pw.Close(), ShouldBeNil)
+			So(<-done, ShouldBeNil)
+		})
+
+		Convey("lists the files with a prefix", func() {
+			_, err := b.Upload(ctx, "cpih01/time-series/2/b.csv", "text/csv", strings.NewReader("b"))
+			So(err, ShouldBeNil)
+			_, err = b.Upload(ctx, "cpih01/time-series/2/a.xlsx", "", strings.NewReader("a"))
+			So(err, ShouldBeNil)
+			_, err = b.Upload(ctx, "cpih01/time-series/1/a.csv", "text/csv", strings.NewReader("a"))
+			So(err, ShouldBeNil)
+
+			files, err := b.List(ctx, VersionPrefix("cpih01", "time-series", "2"))
+			So(err, ShouldBeNil)
+			So(files, ShouldHaveLength, 2)
+			So(files[0].Path, ShouldEqual, "cpih01/time-series/2/a.xlsx")
+			So(files[1].Path, ShouldEqual, "cpih01/time-series/2/b.csv")
+		})
+
+		Convey("lists no files before anything is uploaded", func() {
+			files, err := b.List(ctx, "")
+			So(err, ShouldBeNil)
+			So(files, ShouldBeEmpty)
+		})
+
+		Convey("returns ErrNotFound for a file that has not been uploaded", func() {
+			_, err := b.Get(ctx, "cpih01/time-series/2/missing.csv")
+			So(err, ShouldEqual, ErrNotFound)
+
+			_, err = b.Open(ctx, "cpih01/time-series/2/missing.csv")
+			So(err, ShouldEqual, ErrNotFound)
+		})
+
+		Convey("rejects a path outside the backend", func() {
+			_, err := b.Upload(ctx, "../outside.csv", "text/csv", strings.NewReader("a"))
+			So(err, ShouldNotBeNil)
+
+			_, err = b.Get(ctx, "cpih01/../../outside.csv")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitFile(t *testing.T) {
+	t.Parallel()
+
+	Convey("test File.Ready", t, func() {
+		So(File{State: StateUploaded, ScanState: ScanClean}.Ready(), ShouldBeTrue)
+		So(File{State: StateUploaded, ScanState: ScanPending}.Ready(), ShouldBeTrue)
+		So(File{State: StateUploaded, ScanState: ScanInfected}.Ready(), ShouldBeFalse)
+		So(File{State: StateCreated, ScanState: ScanPending}.Ready(), ShouldBeFalse)
+	})
+
+	Convey("test VersionPath", t, func() {
+		So(VersionPath("cpih01", "time-series", "2", "../../cpih.csv"), ShouldEqual, "cpih01/time-series/2/cpih.csv")
+	})
+
+	Convey("test NewFromConfig", t, func() {
+		b, err := NewFromConfig(BackendLocal, t.TempDir(), true)
+		So(err, ShouldBeNil)
+		So(b, ShouldHaveSameTypeAs, &LocalBackend{})
+
+		_, err = NewFromConfig(BackendLocal, t.TempDir(), false)
+		So(err, ShouldNotBeNil)
+
+		_, err = NewFromConfig(BackendLocal, "", true)
+		So(err, ShouldNotBeNil)
+
+		_, err = NewFromConfig("s3", "uploads", true)
+		So(err, ShouldNotBeNil)
+	})
+}
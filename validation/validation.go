@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const (
+	jsonContentType      = "application/json"
+	multipartContentType = "multipart/form-data"
+)
+
+// Validator checks request bodies against the schemas in the OpenAPI spec before they reach a handler
+type Validator struct {
+	router        routers.Router
+	maxBodySize   int64
+	maxUploadSize int64
+}
+
+// New creates a Validator from an OpenAPI spec. Request bodies larger than maxBodySize bytes are rejected, except
+// for multipart file uploads which can be up to maxUploadSize bytes.
+func New(spec []byte, maxBodySize, maxUploadSize int64) (*Validator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err = doc.Validate(loader.Context); err != nil {
+		return nil, err
+	}
+	// match requests whatever host they are made to
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{router: router, maxBodySize: maxBodySize, maxUploadSize: maxUploadSize}, nil
+}
+
+// Middleware rejects requests whose body is too large, or does not match the schema of the operation in the
+// spec, with an error response listing each invalid field. Requests for operations without a request body are
+// passed on unchecked. Multipart uploads are not read into memory to be checked against the schema: their body is
+// passed on to be streamed by the handler, which fails with an *http.MaxBytesError once it has read more than
+// maxUploadSize bytes.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		route, pathParams, err := v.router.FindRoute(req)
+		if err != nil || route.Operation == nil || route.Operation.RequestBody == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == multipartContentType {
+			req.Body = http.MaxBytesReader(w, req.Body, v.maxUploadSize)
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		b, err := io.ReadAll(http.MaxBytesReader(w, req.Body, v.maxBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				log.Error(ctx, "request body too large", err, log.Data{"max_body_size": v.maxBodySize})
+				writeError(w, req, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body must not be larger than %d bytes", v.maxBodySize), nil)
+				return
+			}
+			log.Error(ctx, "error reading request body", err)
+			writeError(w, req, http.StatusBadRequest, "error reading body", nil)
+			return
+		}
+
+		// the controller only accepts json, so a body sent without a content type is read as json
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", jsonContentType)
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{MultiError: true},
+		}
+		if err = openapi3filter.ValidateRequestBody(ctx, input, route.Operation.RequestBody.Value); err != nil {
+			fieldErrors := toFieldErrors(err)
+			log.Error(ctx, "request body does not match schema", err, log.Data{"errors": fieldErrors})
+			writeError(w, req, http.StatusBadRequest, "request body does not match schema", fieldErrors)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		next.ServeHTTP(w, req)
+	})
+}
+
+// toFieldErrors flattens a validation error into one error per invalid field, sorted by path
+func toFieldErrors(err error) []model.FieldError {
+	var fieldErrors []model.FieldError
+
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, e := range multi {
+			fieldErrors = append(fieldErrors, toFieldErrors(e)...)
+		}
+		sort.SliceStable(fieldErrors, func(i, j int) bool { return fieldErrors[i].Path < fieldErrors[j].Path })
+		return fieldErrors
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []model.FieldError{{
+			Path:    "/" + strings.Join(schemaErr.JSONPointer(), "/"),
+			Message: schemaErr.Reason,
+		}}
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) && requestErr.Reason != "" {
+		return []model.FieldError{{Path: "/", Message: requestErr.Reason}}
+	}
+
+	return []model.FieldError{{Path: "/", Message: err.Error()}}
+}
+
+func writeError(w http.ResponseWriter, req *http.Request, status int, message string, fieldErrors []model.FieldError) {
+	b, err := json.Marshal(model.ErrorResponse{
+		Message:   message,
+		RequestID: request.GetRequestId(req.Context()),
+		Errors:    fieldErrors,
+	})
+	if err != nil {
+		http.Error(w, message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(status)
+	if _, err = w.Write(b); err != nil {
+		log.Error(req.Context(), "error writing response", err)
+	}
+}
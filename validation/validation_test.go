@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-publishing-dataset-controller/docs"
+	"github.com/ONSdigital/dp-publishing-dataset-controller/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const metadataURL = "/datasets/cpih01/editions/time-series/versions/1/metadata"
+
+func TestMiddleware(t *testing.T) {
+	Convey("Given a handler wrapped by the validation middleware", t, func() {
+		v, err := New(docs.Spec, 1024, 4096)
+		So(err, ShouldBeNil)
+
+		var called bool
+		var handlerBody string
+		handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			b, _ := io.ReadAll(r.Body)
+			handlerBody = string(b)
+		}))
+
+		serve := func(method, target, body, contentType string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, target, strings.NewReader(body))
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			return w
+		}
+
+		decode := func(w *httptest.ResponseRecorder) model.ErrorResponse {
+			var resp model.ErrorResponse
+			So(json.Unmarshal(w.Body.Bytes(), &resp), ShouldBeNil)
+			return resp
+		}
+
+		Convey("When a valid body is sent", func() {
+			body := `{"dataset":{"id":"cpih01","title":"CPIH"},"version":{"version":1}}`
+			w := serve(http.MethodPut, metadataURL, body, "application/json")
+
+			Convey("Then the handler is called and can read the body", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+				So(handlerBody, ShouldEqual, body)
+			})
+		})
+
+		Convey("When a valid body is sent without a content type", func() {
+			w := serve(http.MethodPut, metadataURL, `{"dataset":{},"version":{}}`, "")
+
+			Convey("Then it is read as json and the handler is called", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+			})
+		})
+
+		Convey("When a body with an unknown field is sent", func() {
+			w := serve(http.MethodPut, metadataURL, `{"dataset":{"colour":"blue"},"version":{}}`, "application/json")
+
+			Convey("Then a 400 naming the invalid field is returned and the handler is not called", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+				So(called, ShouldBeFalse)
+
+				resp := decode(w)
+				So(resp.Message, ShouldEqual, "request body does not match schema")
+				So(resp.Errors, ShouldHaveLength, 1)
+				So(resp.Errors[0].Path, ShouldEqual, "/dataset")
+				So(resp.Errors[0].Message, ShouldContainSubstring, "colour")
+			})
+		})
+
+		Convey("When a body with fields of the wrong type is sent", func() {
+			w := serve(http.MethodPut, metadataURL, `{"dataset":{"title":1},"version":{"version":"one"}}`, "application/json")
+
+			Convey("Then a 400 listing each invalid field by path is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(called, ShouldBeFalse)
+
+				resp := decode(w)
+				So(resp.Errors, ShouldHaveLength, 2)
+				So(resp.Errors[0].Path, ShouldEqual, "/dataset/title")
+				So(resp.Errors[1].Path, ShouldEqual, "/version/version")
+			})
+		})
+
+		Convey("When a body that is not json is sent", func() {
+			w := serve(http.MethodPut, metadataURL, `not json`, "application/json")
+
+			Convey("Then a 400 is returned", func() {
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+				So(called, ShouldBeFalse)
+				So(decode(w).Errors, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When a body larger than the maximum size is sent", func() {
+			body := `{"dataset":{"title":"` + strings.Repeat("a", 2048) + `"},"version":{}}`
+			w := serve(http.MethodPut, metadataURL, body, "application/json")
+
+			Convey("Then a 413 is returned and the handler is not called", func() {
+				So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+				So(called, ShouldBeFalse)
+				So(decode(w).Message, ShouldEqual, "request body must not be larger than 1024 bytes")
+			})
+		})
+
+		Convey("When a multipart upload is sent", func() {
+			var readErr error
+			handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				_, readErr = io.ReadAll(r.Body)
+			}))
+			upload := func(size int) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/datasets/cpih01/editions/time-series/versions/1/files", strings.NewReader(strings.Repeat("a", size)))
+				req.Header.Set("Content-Type", "multipart/form-data; boundary=test")
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				return w
+			}
+
+			Convey("Then it is passed on to the handler to stream without being checked", func() {
+				w := upload(2048)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+				So(readErr, ShouldBeNil)
+			})
+
+			Convey("Then the handler cannot read more than the maximum upload size", func() {
+				upload(8192)
+				So(called, ShouldBeTrue)
+				var tooLarge *http.MaxBytesError
+				So(errors.As(readErr, &tooLarge), ShouldBeTrue)
+				So(tooLarge.Limit, ShouldEqual, 4096)
+			})
+		})
+
+		Convey("When a request for an operation without a body is made", func() {
+			w := serve(http.MethodGet, "/datasets/cpih01/editions/time-series/versions/1", "", "")
+
+			Convey("Then it is passed on unchecked", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+			})
+		})
+
+		Convey("When a request for a path not in the spec is made", func() {
+			w := serve(http.MethodPost, "/unknown", "not json", "")
+
+			Convey("Then it is passed on unchecked", func() {
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(called, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("When a validator is created from an invalid spec", t, func() {
+		_, err := New([]byte("openapi: [not a spec"), 1024, 4096)
+
+		Convey("Then an error is returned", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
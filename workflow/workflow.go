@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	datasetApiModels "github.com/ONSdigital/dp-dataset-api/models"
+)
+
+// Collection states used by zebedee for a dataset version held in a collection
+const (
+	CollectionStateInProgress = "InProgress"
+	CollectionStateComplete   = "Complete"
+	CollectionStateReviewed   = "Reviewed"
+)
+
+// transitions lists, for each version state, the states a version is allowed to move to next
+var transitions = map[string][]string{
+	datasetApiModels.CreatedState:          {datasetApiModels.EditionConfirmedState},
+	datasetApiModels.EditionConfirmedState: {datasetApiModels.AssociatedState},
+	datasetApiModels.AssociatedState:       {datasetApiModels.EditionConfirmedState, datasetApiModels.ApprovedState},
+	datasetApiModels.ApprovedState:         {datasetApiModels.AssociatedState, datasetApiModels.PublishedState},
+	datasetApiModels.PublishedState:        {},
+}
+
+// collectionStates maps a version state to the state the version should hold in its zebedee collection
+var collectionStates = map[string]string{
+	datasetApiModels.EditionConfirmedState: CollectionStateInProgress,
+	datasetApiModels.AssociatedState:       CollectionStateComplete,
+	datasetApiModels.ApprovedState:         CollectionStateReviewed,
+	datasetApiModels.PublishedState:        CollectionStateReviewed,
+}
+
+// ErrUnknownState is returned when a state is not part of the version publishing workflow
+type ErrUnknownState struct {
+	State string
+}
+
+// Error returns the stringified version of the error
+func (e ErrUnknownState) Error() string {
+	return fmt.Sprintf("unknown version state: %q", e.State)
+}
+
+// ErrInvalidTransition is returned when a version is not allowed to move between two states
+type ErrInvalidTransition struct {
+	From string
+	To   string
+}
+
+// Error returns the stringified version of the error
+func (e ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("version cannot move from state %q to state %q", e.From, e.To)
+}
+
+// CheckTransition returns an error if a version in state from is not allowed to move to state to
+func CheckTransition(from, to string) error {
+	if _, ok := transitions[to]; !ok {
+		return ErrUnknownState{State: to}
+	}
+
+	next, ok := transitions[from]
+	if !ok {
+		return ErrUnknownState{State: from}
+	}
+
+	for _, state := range next {
+		if state == to {
+			return nil
+		}
+	}
+
+	return ErrInvalidTransition{From: from, To: to}
+}
+
+// CollectionState returns the zebedee collection state matching a version state, or an empty string if
+// the version state has no collection state
+func CollectionState(state string) string {
+	return collectionStates[state]
+}
+
+// IsReviewed reports whether a collection state marks content as reviewed
+func IsReviewed(collectionState string) bool {
+	return strings.EqualFold(collectionState, CollectionStateReviewed)
+}
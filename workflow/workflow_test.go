@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitCheckTransition(t *testing.T) {
+	t.Parallel()
+
+	Convey("test CheckTransition", t, func() {
+		Convey("allows each step of the publishing workflow", func() {
+			So(CheckTransition("created", "edition-confirmed"), ShouldBeNil)
+			So(CheckTransition("edition-confirmed", "associated"), ShouldBeNil)
+			So(CheckTransition("associated", "approved"), ShouldBeNil)
+			So(CheckTransition("approved", "published"), ShouldBeNil)
+		})
+
+		Convey("allows a version to be sent back a step", func() {
+			So(CheckTransition("associated", "edition-confirmed"), ShouldBeNil)
+			So(CheckTransition("approved", "associated"), ShouldBeNil)
+		})
+
+		Convey("rejects skipping a step", func() {
+			So(CheckTransition("edition-confirmed", "approved"), ShouldResemble, ErrInvalidTransition{From: "edition-confirmed", To: "approved"})
+		})
+
+		Convey("rejects moving a published version", func() {
+			So(CheckTransition("published", "associated"), ShouldResemble, ErrInvalidTransition{From: "published", To: "associated"})
+		})
+
+		Convey("rejects an unknown target state", func() {
+			So(CheckTransition("associated", "detached"), ShouldResemble, ErrUnknownState{State: "detached"})
+		})
+
+		Convey("rejects an unknown current state", func() {
+			So(CheckTransition("failed", "associated"), ShouldResemble, ErrUnknownState{State: "failed"})
+		})
+	})
+}
+
+func TestUnitCollectionState(t *testing.T) {
+	t.Parallel()
+
+	Convey("test CollectionState", t, func() {
+		So(CollectionState("edition-confirmed"), ShouldEqual, CollectionStateInProgress)
+		So(CollectionState("associated"), ShouldEqual, CollectionStateComplete)
+		So(CollectionState("approved"), ShouldEqual, CollectionStateReviewed)
+		So(CollectionState("created"), ShouldBeEmpty)
+	})
+}